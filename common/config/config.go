@@ -17,17 +17,24 @@ type Config struct {
 	// 2. 中间件配置
 	Auth      AuthConfig      `mapstructure:"auth"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Tenant    TenantConfig    `mapstructure:"tenant"`
+	Casbin    CasbinConfig    `mapstructure:"casbin"`
 
 	// 3. 业务逻辑相关配置
 	Token      TokenConfig      `mapstructure:"token"`
 	SnowFlake  SnowFlakeConfig  `mapstructure:"snow_flake"`
 	Validation ValidationConfig `mapstructure:"validation"`
+	MFA        MFAConfig        `mapstructure:"mfa"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	User       UserConfig       `mapstructure:"user"`
 
 	// 4. 外部服务依赖配置
 	DatabaseCommon  DatabaseConfig            `mapstructure:"database_common"`
 	Databases       map[string]DatabaseConfig `mapstructure:"databases"`
 	DatabaseAliases map[string]string         `mapstructure:"database_aliases"`
 	Redis           RedisConfig               `mapstructure:"redis"`
+	WeChat          WeChatConfig              `mapstructure:"wechat"`
+	SMS             SMSConfig                 `mapstructure:"sms"`
 
 	// 5. 日志配置
 	Zap ZapConfig `mapstructure:"zap"`
@@ -58,6 +65,23 @@ type AuthConfig struct {
 	Whitelist []string `mapstructure:"whitelist"`
 }
 
+// TenantConfig 多租户配置，租户即Casbin中的域
+type TenantConfig struct {
+	Header        string `mapstructure:"header"`         // JWT未携带租户时读取的请求头，默认 X-Tenant-ID
+	DefaultTenant string `mapstructure:"default_tenant"` // 既无声明也无请求头时使用的租户，默认 default
+}
+
+// CasbinConfig 权限策略配置
+type CasbinConfig struct {
+	Watcher CasbinWatcherConfig `mapstructure:"watcher"`
+}
+
+// CasbinWatcherConfig 多实例间的策略变更同步，通过Redis发布订阅通知其他实例
+type CasbinWatcherConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否启用，单实例部署可关闭
+	Channel string `mapstructure:"channel"` // 发布订阅频道，默认 casbin:policy:changed
+}
+
 type RateLimitConfig struct {
 	Enabled         bool          `mapstructure:"enabled"`
 	FillInterval    time.Duration `mapstructure:"fill_interval"`
@@ -70,7 +94,82 @@ type RateLimitConfig struct {
 // --- 3. 业务逻辑相关配置 ---
 
 type TokenConfig struct {
-	ExpiredTime int `mapstructure:"expired_time"`
+	ExpiredTime        int              `mapstructure:"expired_time"`         // 访问令牌过期时间(分钟)
+	RefreshExpiredTime int              `mapstructure:"refresh_expired_time"` // 刷新令牌过期时间(分钟)
+	Signing            SigningConfig    `mapstructure:"signing"`              // 非对称签名配置
+	LoginGuard         LoginGuardConfig `mapstructure:"login_guard"`          // 密码登录防暴力破解配置
+	MaxSessions        int              `mapstructure:"max_sessions"`         // 每个用户的最大并发会话数，超出时淘汰最早的会话，0表示不限制
+}
+
+// LoginGuardConfig 密码登录失败计数与锁定配置
+// 连续失败达到 DelayAfter 后每次失败都需要等待递增的时间，达到上限后临时锁定
+type LoginGuardConfig struct {
+	MaxFailures   int           `mapstructure:"max_failures"`    // 同一手机号连续失败锁定阈值
+	IPMaxFailures int           `mapstructure:"ip_max_failures"` // 同一IP连续失败锁定阈值
+	DelayAfter    int           `mapstructure:"delay_after"`     // 开始递增等待的失败次数
+	BaseDelay     time.Duration `mapstructure:"base_delay"`      // 首次等待时间，之后每次翻倍
+	MaxDelay      time.Duration `mapstructure:"max_delay"`       // 单次等待时间上限
+	LockDuration  time.Duration `mapstructure:"lock_duration"`   // 锁定时长
+	FailureWindow time.Duration `mapstructure:"failure_window"`  // 失败计数统计窗口
+}
+
+// MFAConfig 两步验证配置
+type MFAConfig struct {
+	Issuer        string        `mapstructure:"issuer"`         // 身份验证器中显示的签发方名称，为空时使用 system.server_name
+	EncryptionKey string        `mapstructure:"encryption_key"` // TOTP密钥加密口令，为空时使用 system.secret_key
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`  // 密码验证通过后完成两步验证的有效期
+	MaxAttempts   int           `mapstructure:"max_attempts"`   // 单次两步验证最大尝试次数
+	MaxFailures   int           `mapstructure:"max_failures"`   // 每个用户连续验证失败上限，跨两步验证令牌累计
+	LockDuration  time.Duration `mapstructure:"lock_duration"`  // 达到失败上限后的锁定时长，同时是失败计数的统计窗口
+	RecoveryCodes int           `mapstructure:"recovery_codes"` // 启用时生成的恢复码数量
+}
+
+// PasswordConfig 密码策略与找回密码配置
+type PasswordConfig struct {
+	MinLength     int                 `mapstructure:"min_length"`     // 最小长度
+	MaxLength     int                 `mapstructure:"max_length"`     // 最大长度，不超过72(bcrypt上限)
+	RequireLetter bool                `mapstructure:"require_letter"` // 必须包含字母
+	RequireUpper  bool                `mapstructure:"require_upper"`  // 必须包含大写字母
+	RequireLower  bool                `mapstructure:"require_lower"`  // 必须包含小写字母
+	RequireDigit  bool                `mapstructure:"require_digit"`  // 必须包含数字
+	RequireSymbol bool                `mapstructure:"require_symbol"` // 必须包含特殊字符
+	HistorySize   int                 `mapstructure:"history_size"`   // 新密码不能与最近N次使用的密码相同(含当前密码)，0表示不限制
+	Reset         PasswordResetConfig `mapstructure:"reset"`
+}
+
+// PasswordResetConfig 找回密码配置
+type PasswordResetConfig struct {
+	TokenTTL     time.Duration `mapstructure:"token_ttl"`     // 重置令牌有效期
+	SendInterval time.Duration `mapstructure:"send_interval"` // 同一手机号两次申请的最小间隔
+	URL          string        `mapstructure:"url"`           // 重置页面地址，令牌以 token 查询参数附加；为空时只发送令牌
+}
+
+// UserConfig 用户管理配置
+type UserConfig struct {
+	PurgeRetention time.Duration    `mapstructure:"purge_retention"` // 软删除的用户保留多久后可被 user purge 彻底删除，默认720h(30天)
+	Import         UserImportConfig `mapstructure:"import"`
+	Phone          UserPhoneConfig  `mapstructure:"phone"`
+}
+
+// UserPhoneConfig 手机号配置，地区使用 ISO 3166-1 二位代码
+type UserPhoneConfig struct {
+	DefaultRegion  string   `mapstructure:"default_region"`  // 不带国际区号的号码按此地区解析，默认CN
+	AllowedRegions []string `mapstructure:"allowed_regions"` // 允许使用的手机号地区，为空时不限制
+}
+
+// UserImportConfig 批量导入用户配置
+type UserImportConfig struct {
+	BatchSize   int           `mapstructure:"batch_size"`    // 每批插入的用户数，默认500
+	MaxRows     int           `mapstructure:"max_rows"`      // 单个文件最多导入的行数，默认10000
+	MaxFileSize int64         `mapstructure:"max_file_size"` // 上传文件大小上限(字节)，默认10MB
+	JobTTL      time.Duration `mapstructure:"job_ttl"`       // 导入任务进度和错误报告的保留时长，默认24h
+}
+
+// SigningConfig JWT非对称签名配置，KeyDir为空时使用 system.secret_key 进行HS256签名
+type SigningConfig struct {
+	KeyDir         string        `mapstructure:"key_dir"`         // PEM密钥目录，<kid>.key.pem 为私钥，<kid>.pub.pem 为公钥
+	ActiveKID      string        `mapstructure:"active_kid"`      // 签名使用的kid，为空时使用kid排序最大的私钥
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // 密钥目录检查间隔
 }
 
 type SnowFlakeConfig struct {
@@ -110,6 +209,31 @@ type RedisConfig struct {
 	PoolSize  int    `mapstructure:"pool_size"`
 }
 
+// WeChatConfig 微信小程序配置
+type WeChatConfig struct {
+	AppID     string        `mapstructure:"app_id"`
+	AppSecret string        `mapstructure:"app_secret"`
+	BaseURL   string        `mapstructure:"base_url"` // 微信API地址，为空时使用 https://api.weixin.qq.com
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// SMSConfig 短信配置
+type SMSConfig struct {
+	Driver   string        `mapstructure:"driver"`    // 发送驱动：log(仅输出日志)、file(追加写入文件)
+	FilePath string        `mapstructure:"file_path"` // file 驱动的输出文件
+	Code     SMSCodeConfig `mapstructure:"code"`
+}
+
+// SMSCodeConfig 短信验证码配置
+type SMSCodeConfig struct {
+	Length          int           `mapstructure:"length"`            // 验证码位数
+	TTL             time.Duration `mapstructure:"ttl"`               // 验证码有效期
+	MaxAttempts     int           `mapstructure:"max_attempts"`      // 单个验证码最大校验次数
+	SendInterval    time.Duration `mapstructure:"send_interval"`     // 同一手机号两次发送的最小间隔
+	PhoneDailyLimit int           `mapstructure:"phone_daily_limit"` // 同一手机号每日发送上限
+	IPHourlyLimit   int           `mapstructure:"ip_hourly_limit"`   // 同一IP每小时发送上限
+}
+
 // --- 5. 日志配置 ---
 
 type ZapConfig struct {
@@ -55,4 +55,4 @@ func ValidatedConfig() (*Config, error) {
 	}
 
 	return cfg, nil
-}
\ No newline at end of file
+}
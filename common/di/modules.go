@@ -10,8 +10,11 @@ import (
 	"common/pkg/casbin"
 	"common/pkg/idgen"
 	"common/pkg/jwt"
+	"common/pkg/pagination"
+	"common/pkg/sms"
 	"common/pkg/timezone"
 	"common/pkg/validation"
+	"common/pkg/wechat"
 )
 
 // ConfigModule 配置模块
@@ -39,6 +42,11 @@ var IDGenModule = fx.Module("idgen",
 	idgen.Module,
 )
 
+// PaginationModule 分页模块
+var PaginationModule = fx.Module("pagination",
+	pagination.Module,
+)
+
 // JWTModule JWT模块
 var JWTModule = fx.Module("jwt",
 	jwt.Module,
@@ -59,6 +67,16 @@ var CasbinModule = fx.Module("casbin",
 	casbin.Module,
 )
 
+// WeChatModule 微信客户端模块
+var WeChatModule = fx.Module("wechat",
+	wechat.Module,
+)
+
+// SMSModule 短信发送模块
+var SMSModule = fx.Module("sms",
+	sms.Module,
+)
+
 // GetCoreModules 获取核心模块，用于CLI和其他应用
 func GetCoreModules() fx.Option {
 	return fx.Options(
@@ -70,6 +88,7 @@ func GetCoreModules() fx.Option {
 		IDGenModule,
 		JWTModule,
 		TimezoneModule,
+		PaginationModule,
 	)
 }
 
@@ -78,5 +97,7 @@ func GetWebModules() fx.Option {
 	return fx.Options(
 		GetCoreModules(),
 		HTTPModule,
+		WeChatModule,
+		SMSModule,
 	)
 }
@@ -14,8 +14,61 @@ import (
 	"common/response"
 )
 
+// ClaimsCheckFunc 令牌解析成功后的附加检查函数类型
+// 返回错误时请求被拒绝，返回 *response.DomainError 可自定义响应
+type ClaimsCheckFunc func(ctx context.Context, claims *jwt.CustomClaims) error
+
+// APIKeyPrincipal API Key认证通过后的调用方
+type APIKeyPrincipal struct {
+	KeyID   string   // API Key ID
+	Subject string   // 调用方身份，写入 contextutil.UserIDKey 参与权限校验
+	Scopes  []string // 授权范围，"*" 表示全部
+}
+
+// HasScope 判断调用方是否拥有指定授权范围
+func (p *APIKeyPrincipal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == "*" || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthFunc 校验API Key并返回调用方
+// 返回错误时请求被拒绝，返回 *response.DomainError 可自定义响应
+type APIKeyAuthFunc func(ctx context.Context, apiKey string) (*APIKeyPrincipal, error)
+
+// authOptions 认证中间件选项
+type authOptions struct {
+	claimsChecks []ClaimsCheckFunc
+	apiKeyAuth   APIKeyAuthFunc
+}
+
+// AuthOption 认证中间件选项函数
+type AuthOption func(*authOptions)
+
+// WithClaimsCheck 添加令牌附加检查，如吊销列表检查
+func WithClaimsCheck(check ClaimsCheckFunc) AuthOption {
+	return func(o *authOptions) {
+		o.claimsChecks = append(o.claimsChecks, check)
+	}
+}
+
+// WithAPIKeyAuth 允许使用 X-API-Key 请求头认证，携带该请求头时不再检查 Authorization
+func WithAPIKeyAuth(auth APIKeyAuthFunc) AuthOption {
+	return func(o *authOptions) {
+		o.apiKeyAuth = auth
+	}
+}
+
 // AuthMiddleware 认证中间件
-func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig) gin.HandlerFunc {
+func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig, opts ...AuthOption) gin.HandlerFunc {
+	options := &authOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
@@ -27,6 +80,14 @@ func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig) gin.HandlerFunc
 			return
 		}
 
+		// 服务间调用使用API Key认证
+		if options.apiKeyAuth != nil {
+			if apiKey := c.GetHeader(string(contextutil.APIKeyHeaderKey)); apiKey != "" {
+				authenticateAPIKey(c, apiKey, options.apiKeyAuth)
+				return
+			}
+		}
+
 		// 获取Authorization头
 		authHeader := c.GetHeader(string(contextutil.AuthHeaderKey))
 		if authHeader == "" {
@@ -58,7 +119,7 @@ func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig) gin.HandlerFunc
 		}
 
 		// 验证token并获取用户信息
-		userID, err := validateToken(ctx, token, jwtService)
+		claims, err := validateToken(ctx, token, jwtService)
 		if err != nil {
 			logger.Error(ctx, "Token validation failed", zap.Error(err))
 			authErr := response.NewUnauthorizedError("Invalid token")
@@ -67,9 +128,25 @@ func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig) gin.HandlerFunc
 			return
 		}
 
-		// 将用户ID存储到context中
+		// 执行附加检查
+		for _, check := range options.claimsChecks {
+			if err := check(ctx, claims); err != nil {
+				logger.Warn(ctx, "Token rejected by claims check",
+					zap.String("user_id", claims.UserID),
+					zap.String("jti", claims.ID),
+					zap.Error(err))
+				response.Handle(c, nil, err)
+				c.Abort()
+				return
+			}
+		}
+
+		// 将用户ID和声明存储到context中
+		userID := claims.UserID
 		c.Set(contextutil.UserIDKey, userID)
+		c.Set(contextutil.ClaimsKey, claims)
 		ctx = context.WithValue(ctx, contextutil.UserIDKey, userID)
+		ctx = context.WithValue(ctx, contextutil.ClaimsKey, claims)
 		c.Request = c.Request.WithContext(ctx)
 
 		logger.Debug(ctx, "Authentication successful",
@@ -79,6 +156,63 @@ func AuthMiddleware(jwtService *jwt.JWT, cfg config.AuthConfig) gin.HandlerFunc
 	}
 }
 
+// authenticateAPIKey 校验API Key，通过后将调用方身份写入与JWT认证相同的用户ID键
+func authenticateAPIKey(c *gin.Context, apiKey string, auth APIKeyAuthFunc) {
+	ctx := c.Request.Context()
+
+	principal, err := auth(ctx, apiKey)
+	if err != nil {
+		logger.Warn(ctx, "API key authentication failed", zap.Error(err))
+		if _, ok := err.(*response.DomainError); !ok {
+			err = response.NewUnauthorizedError("Invalid API key")
+		}
+		response.Handle(c, nil, err)
+		c.Abort()
+		return
+	}
+
+	c.Set(contextutil.UserIDKey, principal.Subject)
+	c.Set(contextutil.APIKeyPrincipalKey, principal)
+	ctx = context.WithValue(ctx, contextutil.UserIDKey, principal.Subject)
+	ctx = context.WithValue(ctx, contextutil.APIKeyPrincipalKey, principal)
+	c.Request = c.Request.WithContext(ctx)
+
+	logger.Debug(ctx, "API key authentication successful",
+		zap.String("key_id", principal.KeyID),
+		zap.String("subject", principal.Subject))
+
+	c.Next()
+}
+
+// APIKeyPrincipalFromContext 从context中获取API Key调用方，JWT认证的请求返回false
+func APIKeyPrincipalFromContext(ctx context.Context) (*APIKeyPrincipal, bool) {
+	principal, ok := ctx.Value(contextutil.APIKeyPrincipalKey).(*APIKeyPrincipal)
+	return principal, ok
+}
+
+// RequireScope 要求API Key调用方拥有指定授权范围，JWT认证的用户请求不受影响
+// 需放在 AuthMiddleware 之后，用于不经过Casbin授权的路由；经过Casbin授权的路由使用 WithRouteScopes
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := APIKeyPrincipalFromContext(c.Request.Context())
+		if ok && !principal.HasScope(scope) {
+			abortMissingScope(c, principal, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// abortMissingScope 以403拒绝授权范围不足的API Key请求
+func abortMissingScope(c *gin.Context, principal *APIKeyPrincipal, scope string) {
+	logger.Warn(c.Request.Context(), "API key missing required scope",
+		zap.String("key_id", principal.KeyID),
+		zap.String("scope", scope))
+	response.Handle(c, nil, response.NewForbiddenError("API key missing required scope").
+		WithContext("scope", scope))
+	c.Abort()
+}
+
 // isWhitelistedPath 检查是否为白名单路径
 func isWhitelistedPath(path string, whiteList []string) bool {
 	for _, p := range whiteList {
@@ -89,15 +223,14 @@ func isWhitelistedPath(path string, whiteList []string) bool {
 	return false
 }
 
-// validateToken 验证token并返回用户ID
-func validateToken(ctx context.Context, tokenString string, jwtService *jwt.JWT) (string, error) {
+// validateToken 验证token并返回声明
+func validateToken(ctx context.Context, tokenString string, jwtService *jwt.JWT) (*jwt.CustomClaims, error) {
 	// 解析JWT token
 	claims, err := jwtService.ParseToken(tokenString)
 	if err != nil {
 		logger.Debug(ctx, "Token parsing failed", zap.Error(err), zap.String("token", tokenString[:10]+"..."))
-		return "", err
+		return nil, err
 	}
 
-	// 返回用户ID
-	return claims.UserID, nil
+	return claims, nil
 }
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"common/config"
+)
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		scopes []string
+		apiKey string
+		want   int
+	}{
+		{name: "read key", scopes: []string{"users:read"}, apiKey: "valid", want: http.StatusForbidden},
+		{name: "write key", scopes: []string{"users:write"}, apiKey: "valid", want: http.StatusNoContent},
+		{name: "full access key", scopes: []string{"*"}, apiKey: "valid", want: http.StatusNoContent},
+		{name: "invalid key", scopes: []string{"users:write"}, apiKey: "invalid", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			me := engine.Group("/api/v1/users/me",
+				AuthMiddleware(nil, config.AuthConfig{}, WithAPIKeyAuth(testAPIKeyAuth(tt.scopes))),
+				RequireScope("users:write"),
+			)
+			me.PATCH("", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/users/me", nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+
+	// 不是API Key认证的请求(如JWT用户)不受授权范围限制
+	t.Run("without api key principal", func(t *testing.T) {
+		engine := gin.New()
+		engine.PATCH("/api/v1/users/me", RequireScope("users:write"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/api/v1/users/me", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -6,35 +6,137 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"common/config"
 	"common/logger"
 	"common/pkg/contextutil"
 	"common/response"
 )
 
-// PermissionEnforceFunc 权限检查函数类型
-type PermissionEnforceFunc func(ctx context.Context, sub, obj, act string) (bool, error)
+// PermissionEnforceFunc 权限检查函数类型，dom 为请求所属租户
+type PermissionEnforceFunc func(ctx context.Context, sub, dom, obj, act string) (bool, error)
+
+// CasbinOption Casbin中间件选项
+type CasbinOption func(*casbinOptions)
+
+type casbinOptions struct {
+	resolveDomain DomainResolverFunc
+	permissions   RoutePermissions
+	scopes        RouteScopes
+	ownership     *Ownership
+	enforceOwner  OwnerEnforceFunc
+}
+
+// RoutePermissions 为路由指定权限名，键为 "方法 路由模板"，如 "GET /api/v1/users/:id"
+// 指定了权限名的路由按权限名授权，其余路由按路由模板授权
+type RoutePermissions map[string]string
+
+// Object 返回路由参与授权的资源
+func (p RoutePermissions) Object(method, fullPath string) string {
+	if name, ok := p[method+" "+fullPath]; ok {
+		return name
+	}
+	return fullPath
+}
+
+// RouteScopes 为路由指定API Key所需的授权范围，键与 RoutePermissions 相同
+// 通过Casbin授权之前先检查API Key的授权范围，未列出的路由只允许授权范围为 "*" 的API Key访问
+type RouteScopes map[string]string
+
+// Scope 返回路由要求的授权范围，未列出时返回false
+func (s RouteScopes) Scope(method, fullPath string) (string, bool) {
+	scope, ok := s[method+" "+fullPath]
+	return scope, ok
+}
+
+// WithDomainResolver 自定义租户解析方式，默认使用 TenantResolver 的默认配置
+func WithDomainResolver(resolver DomainResolverFunc) CasbinOption {
+	return func(o *casbinOptions) {
+		o.resolveDomain = resolver
+	}
+}
+
+// WithRoutePermissions 为部分路由指定权限名，使策略与路径解耦
+func WithRoutePermissions(permissions RoutePermissions) CasbinOption {
+	return func(o *casbinOptions) {
+		o.permissions = permissions
+	}
+}
+
+// WithRouteScopes 为路由指定API Key所需的授权范围，API Key除所有者的权限外还受授权范围限制
+func WithRouteScopes(scopes RouteScopes) CasbinOption {
+	return func(o *casbinOptions) {
+		o.scopes = scopes
+	}
+}
 
 // CasbinMiddleware 创建基于Casbin的授权中间件
-func CasbinMiddleware(enforceFunc PermissionEnforceFunc) gin.HandlerFunc {
+// 资源为gin的路由模板(如 /api/v1/users/:id)或路由指定的权限名，而不是实际请求路径，
+// 因此策略按路由编写即可，不受路径参数影响。
+// 解析出的租户会写入上下文(contextutil.TenantIDKey)，供后续处理器按租户读写数据
+func CasbinMiddleware(enforceFunc PermissionEnforceFunc, opts ...CasbinOption) gin.HandlerFunc {
+	options := casbinOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.resolveDomain == nil {
+		options.resolveDomain = TenantResolver(config.TenantConfig{})
+	}
+
 	return func(c *gin.Context) {
+		domain, err := options.resolveDomain(c)
+		if err != nil {
+			response.Handle(c, nil, err)
+			c.Abort()
+			return
+		}
+		withTenant(c, domain)
 		ctx := c.Request.Context()
 
 		var userID string
-		if id, ok := contextutil.GetUserIDFromContext(ctx); ok {
+		id, authenticated := contextutil.GetUserIDFromContext(ctx)
+		if authenticated {
 			userID = id
 		} else {
 			userID = "anonymous"
 		}
 
-		// 获取请求的资源和操作
-		resource := c.Request.URL.Path
+		// 获取请求的资源和操作，未匹配到路由时(如作为全局中间件)退回实际路径
 		action := c.Request.Method
+		resource := c.Request.URL.Path
+		fullPath := c.FullPath()
+		if fullPath != "" {
+			resource = options.permissions.Object(action, fullPath)
+		}
+
+		// API Key的权限不超过其授权范围，即使所有者本身拥有该权限
+		if principal, ok := APIKeyPrincipalFromContext(ctx); ok {
+			scope, ok := options.scopes.Scope(action, fullPath)
+			if !ok {
+				scope = "*"
+			}
+			if !principal.HasScope(scope) {
+				abortMissingScope(c, principal, scope)
+				return
+			}
+		}
 
-		// 检查权限
-		allowed, err := enforceFunc(c.Request.Context(), userID, resource, action)
+		// 检查权限，声明了所有权校验的路由同时携带资源所有者，未认证的请求不可能是所有者
+		var allowed bool
+		var owner string
+		if loader := options.ownership.loader(action, fullPath); loader != nil && options.enforceOwner != nil && authenticated {
+			if owner, err = loader(c); err != nil {
+				response.Handle(c, nil, err)
+				c.Abort()
+				return
+			}
+			allowed, err = options.enforceOwner(ctx, userID, domain, resource, action, owner)
+		} else {
+			allowed, err = enforceFunc(ctx, userID, domain, resource, action)
+		}
 		if err != nil {
 			logger.Error(ctx, "Failed to enforce policy",
 				zap.String("user_id", userID),
+				zap.String("domain", domain),
 				zap.String("resource", resource),
 				zap.String("action", action),
 				zap.Error(err))
@@ -47,8 +149,10 @@ func CasbinMiddleware(enforceFunc PermissionEnforceFunc) gin.HandlerFunc {
 		if !allowed {
 			logger.Warn(ctx, "Access denied",
 				zap.String("user_id", userID),
+				zap.String("domain", domain),
 				zap.String("resource", resource),
-				zap.String("action", action))
+				zap.String("action", action),
+				zap.String("owner", owner))
 			forbiddenErr := response.NewForbiddenError("Access denied")
 			response.Handle(c, nil, forbiddenErr)
 			c.Abort()
@@ -58,6 +162,7 @@ func CasbinMiddleware(enforceFunc PermissionEnforceFunc) gin.HandlerFunc {
 		// 权限检查通过，继续处理请求
 		logger.Debug(ctx, "Access granted",
 			zap.String("user_id", userID),
+			zap.String("domain", domain),
 			zap.String("resource", resource),
 			zap.String("action", action))
 		c.Next()
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"common/config"
+	"common/response"
+)
+
+// testAPIKeyAuth 只接受 "valid" 的API Key认证，授权范围为 scopes
+func testAPIKeyAuth(scopes []string) APIKeyAuthFunc {
+	return func(ctx context.Context, apiKey string) (*APIKeyPrincipal, error) {
+		if apiKey != "valid" {
+			return nil, response.NewUnauthorizedError("Invalid API key")
+		}
+		return &APIKeyPrincipal{KeyID: "k1", Subject: "svc:billing", Scopes: scopes}, nil
+	}
+}
+
+// newScopedRouter 创建经过API Key认证和Casbin授权的路由，所有者拥有全部权限
+func newScopedRouter(scopes []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	allowAll := func(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+		return true, nil
+	}
+
+	engine := gin.New()
+	group := engine.Group("/api/v1",
+		AuthMiddleware(nil, config.AuthConfig{}, WithAPIKeyAuth(testAPIKeyAuth(scopes))),
+		CasbinMiddleware(allowAll, WithRouteScopes(RouteScopes{
+			"GET /api/v1/users":        "users:read",
+			"PATCH /api/v1/users/:id":  "users:write",
+			"DELETE /api/v1/users/:id": "users:write",
+		})),
+	)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	group.GET("/users", ok)
+	group.PATCH("/users/:id", ok)
+	group.DELETE("/users/:id", ok)
+	group.GET("/admin/policies", ok)
+	return engine
+}
+
+func TestCasbinMiddleware_RouteScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "read key reads", scopes: []string{"users:read"}, method: http.MethodGet, path: "/api/v1/users", want: http.StatusNoContent},
+		{name: "read key writes", scopes: []string{"users:read"}, method: http.MethodPatch, path: "/api/v1/users/1", want: http.StatusForbidden},
+		{name: "read key deletes", scopes: []string{"users:read"}, method: http.MethodDelete, path: "/api/v1/users/1", want: http.StatusForbidden},
+		{name: "write key writes", scopes: []string{"users:read", "users:write"}, method: http.MethodPatch, path: "/api/v1/users/1", want: http.StatusNoContent},
+		{name: "route without scope", scopes: []string{"users:read", "users:write"}, method: http.MethodGet, path: "/api/v1/admin/policies", want: http.StatusForbidden},
+		{name: "full access key", scopes: []string{"*"}, method: http.MethodGet, path: "/api/v1/admin/policies", want: http.StatusNoContent},
+		{name: "key without scopes", scopes: nil, method: http.MethodGet, path: "/api/v1/users", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newScopedRouter(tt.scopes)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-API-Key", "valid")
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
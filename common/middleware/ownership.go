@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OwnerLoader 加载请求所访问资源的所有者(用户ID)
+// 资源不存在时返回空字符串，由后续处理器返回404；返回错误时中止请求
+type OwnerLoader func(c *gin.Context) (string, error)
+
+// OwnerEnforceFunc 携带资源所有者的权限检查函数
+type OwnerEnforceFunc func(ctx context.Context, sub, dom, obj, act, owner string) (bool, error)
+
+// OwnerFromParam 资源所有者即路径参数本身，如 /users/:id 的所有者就是 id 对应的用户
+func OwnerFromParam(name string) OwnerLoader {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
+}
+
+// Ownership 需要所有权校验的路由表
+// 授权中间件挂在路由组上，先于路由自身的处理器执行，因此所有权校验在注册路由时声明，由授权中间件按路由查找
+type Ownership struct {
+	mu      sync.RWMutex
+	loaders map[string]OwnerLoader
+}
+
+// NewOwnership 创建所有权校验路由表
+func NewOwnership() *Ownership {
+	return &Ownership{loaders: make(map[string]OwnerLoader)}
+}
+
+// Require 声明路由需要所有权校验，rg 和 relativePath 与注册路由时一致
+func (o *Ownership) Require(rg *gin.RouterGroup, method, relativePath string, loader OwnerLoader) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.loaders[method+" "+joinPaths(rg.BasePath(), relativePath)] = loader
+}
+
+// loader 查找路由的所有者加载器，未声明时返回nil
+func (o *Ownership) loader(method, fullPath string) OwnerLoader {
+	if o == nil {
+		return nil
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.loaders[method+" "+fullPath]
+}
+
+// joinPaths 与gin拼接路由组路径的规则一致
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+	finalPath := path.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
+
+// WithOwnership 启用资源所有权校验
+// 声明了所有权校验的路由改用 enforceFunc 鉴权，主体为 $owner 的策略会放行资源所有者本人
+func WithOwnership(ownership *Ownership, enforceFunc OwnerEnforceFunc) CasbinOption {
+	return func(o *casbinOptions) {
+		o.ownership = ownership
+		o.enforceOwner = enforceFunc
+	}
+}
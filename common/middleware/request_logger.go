@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,16 +17,24 @@ import (
 )
 
 // ResponseWriter 包装gin的ResponseWriter以捕获响应体
+// 只捕获JSON响应，文件下载和流式导出等响应体不缓存，内存占用不随响应大小增长
 type ResponseWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
 }
 
 func (w *ResponseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.body.Write(b)
+	}
 	return w.ResponseWriter.Write(b)
 }
 
+// Unwrap 返回被包装的ResponseWriter，使 http.ResponseController 可以调整写超时
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // RequestLogMiddleware 请求日志中间件，记录请求信息并包含用户ID和真实业务状态码
 func RequestLogMiddleware() gin.HandlerFunc {
 	return requestLoggerInternal(false)
@@ -93,6 +103,9 @@ func requestLoggerInternal(detailed bool) gin.HandlerFunc {
 		var responseData response.Response
 		if err := json.Unmarshal(responseBody, &responseData); err == nil {
 			businessCode = responseData.Code
+		} else if len(responseBody) == 0 && httpStatus < http.StatusBadRequest {
+			// 非JSON响应没有业务状态码，按HTTP状态码判断是否成功
+			businessCode = response.CodeSuccess
 		}
 
 		// 构建日志字段
@@ -128,4 +141,4 @@ func requestLoggerInternal(detailed bool) gin.HandlerFunc {
 			logger.Info(ctx, msg+" successfully", logFields...)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"common/config"
+	pkgcasbin "common/pkg/casbin"
+	"common/pkg/contextutil"
+	"common/pkg/jwt"
+	"common/response"
+)
+
+// tenantPattern 租户标识格式，* 另行放行用于平台级管理
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:-]{0,63}$`)
+
+// DomainResolverFunc 解析请求所属租户(Casbin域)的函数类型
+type DomainResolverFunc func(c *gin.Context) (string, error)
+
+// TenantResolver 按 JWT 的 tid 声明、请求头、默认租户的顺序解析租户
+// 令牌已绑定租户时以声明为准，请求头指定了其他租户则拒绝请求，避免用户通过请求头切换到其他租户
+func TenantResolver(cfg config.TenantConfig) DomainResolverFunc {
+	header := cfg.Header
+	if header == "" {
+		header = string(contextutil.TenantHeaderKey)
+	}
+	defaultTenant := pkgcasbin.ResolveDefaultDomain(cfg)
+
+	return func(c *gin.Context) (string, error) {
+		tenantID := strings.TrimSpace(c.GetHeader(header))
+		if claims, ok := jwt.ClaimsFromContext(c.Request.Context()); ok && claims.TenantID != "" {
+			if tenantID != "" && tenantID != claims.TenantID {
+				return "", response.NewForbiddenError("令牌已绑定其他租户").WithContext("header", header)
+			}
+			return claims.TenantID, nil
+		}
+
+		if tenantID == "" {
+			return defaultTenant, nil
+		}
+		if tenantID != pkgcasbin.AllDomains && !tenantPattern.MatchString(tenantID) {
+			return "", response.NewValidationError("租户标识格式不正确").WithContext("header", header)
+		}
+		return tenantID, nil
+	}
+}
+
+// withTenant 将租户写入gin上下文和请求上下文，供后续处理器读取
+func withTenant(c *gin.Context, tenantID string) {
+	c.Set(contextutil.TenantIDKey, tenantID)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextutil.TenantIDKey, tenantID))
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/pkg/contextutil"
+	"common/pkg/jwt"
+	"common/response"
+)
+
+func errorType(t response.ErrorType) *response.ErrorType {
+	return &t
+}
+
+func TestTenantResolver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolve := TenantResolver(config.TenantConfig{DefaultTenant: "default"})
+
+	tests := []struct {
+		name      string
+		claimsTID string
+		header    string
+		want      string
+		wantErr   *response.ErrorType
+	}{
+		{name: "default tenant", want: "default"},
+		{name: "header", header: "acme", want: "acme"},
+		{name: "invalid header", header: "acme corp", wantErr: errorType(response.ErrorTypeValidationFailed)},
+		{name: "claims", claimsTID: "acme", want: "acme"},
+		{name: "claims with same header", claimsTID: "acme", header: "acme", want: "acme"},
+		{name: "claims with other header", claimsTID: "acme", header: "globex", wantErr: errorType(response.ErrorTypeForbidden)},
+		{name: "claims without tenant", claimsTID: "", header: "globex", want: "globex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(string(contextutil.TenantHeaderKey), tt.header)
+			}
+			claims := &jwt.CustomClaims{UserID: "u1", TenantID: tt.claimsTID}
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextutil.ClaimsKey, claims))
+
+			got, err := resolve(c)
+			if tt.wantErr != nil {
+				var domainErr *response.DomainError
+				require.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, *tt.wantErr, domainErr.Type)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,72 +1,108 @@
 package casbin
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/util"
 	entadapter "github.com/casbin/ent-adapter"
+	entcasbin "github.com/casbin/ent-adapter/ent"
+	"github.com/casbin/ent-adapter/ent/casbinrule"
 	"go.uber.org/zap"
 
+	"common/config"
 	"common/databases/rdbms"
 )
 
+const (
+	// DefaultDomain 未配置默认租户时使用的域
+	DefaultDomain = "default"
+	// AllDomains 匹配全部租户的域，用于平台级角色和策略
+	AllDomains = "*"
+	// OwnerSubject 策略主体为该值时，表示资源所有者本人可以访问，如 p, $owner, *, /api/v1/users/:id, GET
+	OwnerSubject = "$owner"
+)
+
+// OwnerEnforceContext 携带资源所有者的鉴权，请求为 (sub, dom, obj, act, owner)，与普通鉴权共用 p 策略
+var OwnerEnforceContext = casbin.EnforceContext{RType: "r2", PType: "p", EType: "e", MType: "m2"}
+
+// modelText 带域的RBAC模型
+// 策略和角色分配的域均支持keyMatch，域为 * 时对全部租户生效；
+// r2/m2 在RBAC之外额外允许主体为 $owner 的策略放行资源所有者本人
+const modelText = `
+[request_definition]
+r = sub, dom, obj, act
+r2 = sub, dom, obj, act, owner
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && keyMatch(r.dom, p.dom) && keyMatch(r.obj, p.obj) && (r.act == p.act || p.act == "*")
+m2 = (g(r2.sub, p.sub, r2.dom) || (p.sub == "$owner" && r2.owner != "" && r2.owner == r2.sub)) && keyMatch(r2.dom, p.dom) && keyMatch(r2.obj, p.obj) && (r2.act == p.act || p.act == "*")
+`
+
 // EnforcerParams 定义了创建Casbin Enforcer所需的依赖
 type EnforcerParams struct {
 	Client *rdbms.Client
 	Logger *zap.Logger
 }
 
-// NewEnforcer 创建一个 Casbin SyncedCachedEnforcer 实例
-func NewEnforcer(client *rdbms.Client, logger *zap.Logger) (*casbin.SyncedCachedEnforcer, error) {
-	// 使用数据源名称创建适配器，复用现有的数据库配置
-	// 获取数据库配置信息
-	config := client.Config()
-
-	// 构建数据源名称
-	charset := config.Charset
-	if charset == "" {
-		charset = "utf8mb4"
+// NewModel 创建带域的Casbin模型
+func NewModel() (model.Model, error) {
+	return model.NewModelFromString(modelText)
+}
+
+// enableDomainMatching 使域为 * 的角色分配对全部租户生效
+func enableDomainMatching(enforcer *casbin.SyncedCachedEnforcer) {
+	enforcer.AddNamedDomainMatchingFunc("g", "keyMatch", util.KeyMatch)
+}
+
+// EnforceWithOwner 鉴权时携带资源所有者，owner 为空时等同于普通鉴权
+func EnforceWithOwner(enforcer *casbin.SyncedCachedEnforcer, sub, dom, obj, act, owner string) (bool, error) {
+	return enforcer.Enforce(OwnerEnforceContext, sub, dom, obj, act, owner)
+}
+
+// ResolveDefaultDomain 返回配置的默认租户
+func ResolveDefaultDomain(cfg config.TenantConfig) string {
+	if cfg.DefaultTenant != "" {
+		return cfg.DefaultTenant
 	}
-	dataSourceName := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		config.Username,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.Database,
-		charset)
+	return DefaultDomain
+}
 
+// NewEnforcer 创建一个 Casbin SyncedCachedEnforcer 实例
+// 模型按租户(域)隔离角色和策略，tenantCfg 决定旧策略迁移到哪个默认租户
+func NewEnforcer(client *rdbms.Client, tenantCfg config.TenantConfig, logger *zap.Logger) (*casbin.SyncedCachedEnforcer, error) {
 	logger.Info("Initializing Casbin adapter",
-		zap.String("host", config.Host),
-		zap.Int("port", config.Port),
-		zap.String("database", config.Database))
-
-	// 创建适配器
-	// 注意：Casbin表结构现在通过Ent的schema管理，使用ent迁移功能创建
-	a, err := entadapter.NewAdapter("mysql", dataSourceName)
+		zap.String("client", client.Name()),
+		zap.String("type", client.Config().Type),
+		zap.String("database", client.Config().Database))
+
+	// 复用数据库客户端的连接池和方言，MySQL、PostgreSQL、SQLite 均可使用
+	// casbin_rules 表由适配器在启动时自动创建
+	ruleClient := entcasbin.NewClient(entcasbin.Driver(client.Driver()))
+	a, err := entadapter.NewAdapterWithClient(ruleClient)
 	if err != nil {
 		logger.Error("Failed to create casbin ent adapter", zap.Error(err))
 		return nil, fmt.Errorf("failed to create casbin ent adapter: %w", err)
 	}
 
-	// 从字符串加载 Casbin 模型
-	text := `
-[request_definition]
-r = sub, obj, act
-
-[policy_definition]
-p = sub, obj, act
-
-[role_definition]
-g = _, _
-
-[policy_effect]
-e = some(where (p.eft == allow))
+	// 旧版本的策略不含域，加载前归入默认租户
+	if err := upgradeLegacyRules(context.Background(), ruleClient, tenantCfg, logger); err != nil {
+		return nil, err
+	}
 
-[matchers]
-m = g(r.sub, p.sub) && keyMatch(r.obj, p.obj) && (r.act == p.act || p.act == "*")
-	`
-	m, err := model.NewModelFromString(text)
+	m, err := NewModel()
 	if err != nil {
 		logger.Error("Failed to load casbin model from string", zap.Error(err))
 		return nil, fmt.Errorf("failed to load casbin model from string: %w", err)
@@ -79,8 +115,10 @@ m = g(r.sub, p.sub) && keyMatch(r.obj, p.obj) && (r.act == p.act || p.act == "*"
 		return nil, fmt.Errorf("failed to create casbin enforcer: %w", err)
 	}
 
-	// 设置缓存过期时间（例如：10分钟）
-	enforcer.SetExpireTime(10 * 60)
+	enableDomainMatching(enforcer)
+
+	// 设置缓存过期时间，策略变更时由权限服务和策略同步器主动清空
+	enforcer.SetExpireTime(10 * time.Minute)
 
 	// 从数据库加载策略
 	logger.Info("Loading casbin policy from database")
@@ -92,3 +130,43 @@ m = g(r.sub, p.sub) && keyMatch(r.obj, p.obj) && (r.act == p.act || p.act == "*"
 	logger.Info("Casbin enforcer initialized successfully")
 	return enforcer, nil
 }
+
+// upgradeLegacyRules 将不含域的旧策略(p, sub, obj, act 与 g, user, role)迁移到默认租户
+// 通过 v3/v2 为空识别旧规则，重复执行不会产生影响
+func upgradeLegacyRules(ctx context.Context, ruleClient *entcasbin.Client, tenantCfg config.TenantConfig, logger *zap.Logger) error {
+	domain := ResolveDefaultDomain(tenantCfg)
+
+	legacyPolicies, err := ruleClient.CasbinRule.Query().
+		Where(casbinrule.Ptype("p"), casbinrule.V2NEQ(""), casbinrule.V3(""), casbinrule.V4("")).
+		All(ctx)
+	if err != nil {
+		logger.Error("Failed to query legacy casbin rules", zap.Error(err))
+		return fmt.Errorf("failed to query legacy casbin rules: %w", err)
+	}
+	for _, rule := range legacyPolicies {
+		if err := ruleClient.CasbinRule.UpdateOne(rule).
+			SetV1(domain).
+			SetV2(rule.V1).
+			SetV3(rule.V2).
+			Exec(ctx); err != nil {
+			logger.Error("Failed to upgrade legacy casbin rule", zap.Int("id", rule.ID), zap.Error(err))
+			return fmt.Errorf("failed to upgrade legacy casbin rule %d: %w", rule.ID, err)
+		}
+	}
+
+	groupings, err := ruleClient.CasbinRule.Update().
+		Where(casbinrule.Ptype("g"), casbinrule.V1NEQ(""), casbinrule.V2("")).
+		SetV2(domain).
+		Save(ctx)
+	if err != nil {
+		logger.Error("Failed to upgrade legacy casbin rules", zap.Error(err))
+		return fmt.Errorf("failed to upgrade legacy casbin rules: %w", err)
+	}
+
+	if upgraded := len(legacyPolicies) + groupings; upgraded > 0 {
+		logger.Info("Upgraded legacy casbin rules to default domain",
+			zap.String("domain", domain),
+			zap.Int("rules", upgraded))
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package casbin
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	entcasbin "github.com/casbin/ent-adapter/ent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/rdbms"
+)
+
+func newTestEnforcer(t *testing.T) *casbin.SyncedCachedEnforcer {
+	t.Helper()
+	m, err := NewModel()
+	require.NoError(t, err)
+	enforcer, err := casbin.NewSyncedCachedEnforcer(m)
+	require.NoError(t, err)
+	enableDomainMatching(enforcer)
+	return enforcer
+}
+
+func TestModel_RolesAreScopedToDomain(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy("admin", "acme", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddPolicy("admin", "globex", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("alice", "admin", "acme")
+	require.NoError(t, err)
+
+	allowed, err := e.Enforce("alice", "acme", "/api/v1/users/1", "GET")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// 同名角色在其他租户中未分配给alice
+	allowed, err = e.Enforce("alice", "globex", "/api/v1/users/1", "GET")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestModel_AllDomainsWildcard(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy("platform_admin", AllDomains, "/api/v1/admin/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("root", "platform_admin", AllDomains)
+	require.NoError(t, err)
+	_, err = e.AddPolicy("tenant_admin", "acme", "/api/v1/admin/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("bob", "tenant_admin", "acme")
+	require.NoError(t, err)
+
+	for _, domain := range []string{"acme", "globex", AllDomains} {
+		allowed, err := e.Enforce("root", domain, "/api/v1/admin/policies", "POST")
+		require.NoError(t, err)
+		assert.True(t, allowed, "domain %s", domain)
+	}
+
+	allowed, err := e.Enforce("bob", "acme", "/api/v1/admin/policies", "POST")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// 租户管理员不能以 * 域管理平台级规则
+	allowed, err = e.Enforce("bob", AllDomains, "/api/v1/admin/policies", "POST")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestResolveDefaultDomain(t *testing.T) {
+	assert.Equal(t, DefaultDomain, ResolveDefaultDomain(config.TenantConfig{}))
+	assert.Equal(t, "acme", ResolveDefaultDomain(config.TenantConfig{DefaultTenant: "acme"}))
+}
+
+func newSQLiteClient(t *testing.T) *rdbms.Client {
+	t.Helper()
+	manager, err := rdbms.NewManager(rdbms.ManagerParams{
+		Config: &config.Config{
+			Databases: map[string]config.DatabaseConfig{
+				"casbin": {Type: "sqlite", Database: filepath.Join(t.TempDir(), "casbin.db") + "?_fk=1"},
+			},
+		},
+		Logger: zap.NewNop(),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	client, err := manager.GetClient("casbin")
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewEnforcer_SQLite(t *testing.T) {
+	client := newSQLiteClient(t)
+
+	e, err := NewEnforcer(client, config.TenantConfig{}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = e.AddPolicy("admin", "acme", "/api/v1/admin/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("alice", "admin", "acme")
+	require.NoError(t, err)
+
+	// 新的执行器从同一个库加载策略
+	reloaded, err := NewEnforcer(client, config.TenantConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	allowed, err := reloaded.Enforce("alice", "acme", "/api/v1/admin/roles", "GET")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	_, err = reloaded.RemovePolicy("admin", "acme", "/api/v1/admin/*", "*")
+	require.NoError(t, err)
+	require.NoError(t, e.LoadPolicy())
+	policies, err := e.GetPolicy()
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+}
+
+func TestNewEnforcer_UpgradesLegacyRules(t *testing.T) {
+	client := newSQLiteClient(t)
+	ctx := context.Background()
+
+	// 模拟升级前不含域的规则
+	ruleClient := entcasbin.NewClient(entcasbin.Driver(client.Driver()))
+	require.NoError(t, ruleClient.Schema.Create(ctx))
+	ruleClient.CasbinRule.CreateBulk(
+		ruleClient.CasbinRule.Create().SetPtype("p").SetV0("admin").SetV1("/api/v1/admin/*").SetV2("*"),
+		ruleClient.CasbinRule.Create().SetPtype("g").SetV0("alice").SetV1("admin"),
+	).ExecX(ctx)
+
+	e, err := NewEnforcer(client, config.TenantConfig{DefaultTenant: "acme"}, zap.NewNop())
+	require.NoError(t, err)
+
+	policies, err := e.GetPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"admin", "acme", "/api/v1/admin/*", "*"}}, policies)
+
+	allowed, err := e.Enforce("alice", "acme", "/api/v1/admin/roles", "DELETE")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// 重复执行不会再次改写已迁移的规则
+	_, err = NewEnforcer(client, config.TenantConfig{DefaultTenant: "acme"}, zap.NewNop())
+	require.NoError(t, err)
+	count := ruleClient.CasbinRule.Query().CountX(ctx)
+	assert.Equal(t, 2, count)
+}
+
+func TestUncoveredRoutes(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy("admin", AllDomains, "/api/v1/admin/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddPolicy("viewer", "acme", "/api/v1/users/:id", "GET")
+	require.NoError(t, err)
+	_, err = e.AddPolicy("operator", "acme", "users:export", "POST")
+	require.NoError(t, err)
+
+	routes := []Route{
+		{Method: "GET", Object: "/api/v1/admin/policies"},
+		{Method: "DELETE", Object: "/api/v1/admin/users/:id/roles/:role"},
+		{Method: "GET", Object: "/api/v1/users/:id"},
+		{Method: "DELETE", Object: "/api/v1/users/:id"},
+		{Method: "GET", Object: "/api/v1/users"},
+		{Method: "POST", Object: "users:export"},
+	}
+	uncovered, err := UncoveredRoutes(e, routes)
+	require.NoError(t, err)
+	assert.Equal(t, []Route{
+		{Method: "DELETE", Object: "/api/v1/users/:id"},
+		{Method: "GET", Object: "/api/v1/users"},
+	}, uncovered)
+}
+
+func TestModel_OwnerOrRole(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy(OwnerSubject, AllDomains, "/api/v1/users/:id", "GET")
+	require.NoError(t, err)
+	_, err = e.AddPolicy("admin", "acme", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("root", "admin", "acme")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		sub     string
+		act     string
+		owner   string
+		allowed bool
+	}{
+		{"owner reads own record", "alice", "GET", "alice", true},
+		{"other user is denied", "bob", "GET", "alice", false},
+		{"owner policy does not grant other actions", "alice", "DELETE", "alice", false},
+		{"unknown owner is denied", "alice", "GET", "", false},
+		{"admin reads any record", "root", "GET", "alice", true},
+		{"admin without owner", "root", "DELETE", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := EnforceWithOwner(e, tt.sub, "acme", "/api/v1/users/:id", tt.act, tt.owner)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+
+	// 普通鉴权不识别 $owner 策略
+	allowed, err := e.Enforce("alice", "acme", "/api/v1/users/:id", "GET")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
@@ -0,0 +1,44 @@
+package casbin
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
+)
+
+// Route 参与授权的路由
+type Route struct {
+	Method string // HTTP方法
+	Object string // 授权资源，即路由模板或权限名
+}
+
+// UncoveredRoutes 返回没有任何策略能放行的路由
+// 只检查策略的资源和操作，不区分主体和租户：被覆盖的路由至少有人可以访问，
+// 未被覆盖的路由在启用授权后对所有人返回403
+func UncoveredRoutes(enforcer *casbin.SyncedCachedEnforcer, routes []Route) ([]Route, error) {
+	policies, err := enforcer.GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	uncovered := make([]Route, 0)
+	for _, route := range routes {
+		if !isCovered(route, policies) {
+			uncovered = append(uncovered, route)
+		}
+	}
+	return uncovered, nil
+}
+
+// isCovered 与模型匹配器保持一致：资源按keyMatch匹配，操作相同或为 *
+func isCovered(route Route, policies [][]string) bool {
+	for _, policy := range policies {
+		if len(policy) < 4 {
+			continue
+		}
+		obj, act := policy[2], policy[3]
+		if util.KeyMatch(route.Object, obj) && (act == route.Method || act == "*") {
+			return true
+		}
+	}
+	return false
+}
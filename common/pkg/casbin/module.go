@@ -1,17 +1,21 @@
 package casbin
 
 import (
+	"context"
+
 	"github.com/casbin/casbin/v2"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"common/config"
 	"common/databases/rdbms"
+	"common/databases/redis"
 )
 
-// Module 提供了 Casbin Enforcer
+// Module 提供了 Casbin Enforcer 及跨实例的策略同步器
 var Module = fx.Module("casbin",
 	// 提供一个函数来创建Casbin执行器，该函数接收数据库管理器并返回执行器
-	fx.Provide(func(manager rdbms.ManagerInterface, logger *zap.Logger) (*casbin.SyncedCachedEnforcer, error) {
+	fx.Provide(func(manager rdbms.ManagerInterface, cfg *config.Config, logger *zap.Logger) (*casbin.SyncedCachedEnforcer, error) {
 		// 尝试通过别名获取Casbin专用数据库，如果没有则使用默认数据库
 		var client *rdbms.Client
 		var err error
@@ -26,6 +30,19 @@ var Module = fx.Module("casbin",
 		}
 
 		// 使用数据库客户端创建Casbin执行器
-		return NewEnforcer(client, logger)
+		return NewEnforcer(client, cfg.Tenant, logger)
+	}),
+	fx.Provide(func(enforcer *casbin.SyncedCachedEnforcer, client *redis.RedisClient, cfg *config.Config, logger *zap.Logger) (*PolicyWatcher, error) {
+		return NewPolicyWatcher(enforcer, client, cfg.Casbin.Watcher, logger)
+	}),
+	fx.Invoke(func(lc fx.Lifecycle, watcher *PolicyWatcher) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				return watcher.Start(ctx)
+			},
+			OnStop: func(ctx context.Context) error {
+				return watcher.Stop(ctx)
+			},
+		})
 	}),
 )
@@ -0,0 +1,227 @@
+package casbin
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 策略文件格式
+const (
+	FormatYAML = "yaml"
+	FormatCSV  = "csv"
+)
+
+// PolicyRule p 规则
+type PolicyRule struct {
+	Sub string `yaml:"sub"`
+	Dom string `yaml:"dom"`
+	Obj string `yaml:"obj"`
+	Act string `yaml:"act"`
+}
+
+// RoleRule g 规则，即租户内的角色分配
+type RoleRule struct {
+	User string `yaml:"user"`
+	Role string `yaml:"role"`
+	Dom  string `yaml:"dom"`
+}
+
+// PolicyFile 声明式的全部策略和角色分配，作为策略在版本库中的来源
+type PolicyFile struct {
+	Policies []PolicyRule `yaml:"policies"`
+	Roles    []RoleRule   `yaml:"roles"`
+}
+
+// Values 转换为casbin规则
+func (r PolicyRule) Values() []string { return []string{r.Sub, r.Dom, r.Obj, r.Act} }
+
+// Values 转换为casbin规则
+func (r RoleRule) Values() []string { return []string{r.User, r.Role, r.Dom} }
+
+// String CSV形式，便于输出差异
+func (r PolicyRule) String() string { return "p, " + strings.Join(r.Values(), ", ") }
+
+// String CSV形式，便于输出差异
+func (r RoleRule) String() string { return "g, " + strings.Join(r.Values(), ", ") }
+
+// NewPolicyFile 由casbin规则构造策略文件，字段不完整的规则被忽略
+func NewPolicyFile(policies, roles [][]string) *PolicyFile {
+	file := &PolicyFile{
+		Policies: make([]PolicyRule, 0, len(policies)),
+		Roles:    make([]RoleRule, 0, len(roles)),
+	}
+	for _, rule := range policies {
+		if len(rule) >= 4 {
+			file.Policies = append(file.Policies, PolicyRule{Sub: rule[0], Dom: rule[1], Obj: rule[2], Act: rule[3]})
+		}
+	}
+	for _, rule := range roles {
+		if len(rule) >= 3 {
+			file.Roles = append(file.Roles, RoleRule{User: rule[0], Role: rule[1], Dom: rule[2]})
+		}
+	}
+	file.Sort()
+	return file
+}
+
+// Sort 按租户、主体、资源排序，导出结果稳定，便于在git中比对
+func (f *PolicyFile) Sort() {
+	sort.Slice(f.Policies, func(i, j int) bool {
+		return lessValues(f.Policies[i].Values(), f.Policies[j].Values(), 1, 0, 2, 3)
+	})
+	sort.Slice(f.Roles, func(i, j int) bool {
+		return lessValues(f.Roles[i].Values(), f.Roles[j].Values(), 2, 1, 0)
+	})
+}
+
+func lessValues(a, b []string, order ...int) bool {
+	for _, i := range order {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// Validate 检查规则字段是否完整
+func (f *PolicyFile) Validate() error {
+	for i, rule := range f.Policies {
+		for _, v := range rule.Values() {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("policies[%d]: sub, dom, obj and act are required", i)
+			}
+		}
+	}
+	for i, rule := range f.Roles {
+		for _, v := range rule.Values() {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("roles[%d]: user, role and dom are required", i)
+			}
+		}
+		if rule.User == OwnerSubject || rule.Role == OwnerSubject {
+			return fmt.Errorf("roles[%d]: %s can only be used as a policy subject", i, OwnerSubject)
+		}
+	}
+	return nil
+}
+
+// FormatFromPath 按扩展名判断文件格式
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported policy file %q, expected .yaml, .yml or .csv", path)
+	}
+}
+
+// ReadPolicyFile 读取策略文件
+// CSV与casbin文件适配器格式相同：每行一条规则，以 p 或 g 开头，# 开头的行为注释
+func ReadPolicyFile(r io.Reader, format string) (*PolicyFile, error) {
+	var (
+		file *PolicyFile
+		err  error
+	)
+	switch format {
+	case FormatYAML:
+		file = &PolicyFile{}
+		if err = yaml.NewDecoder(r).Decode(file); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse yaml policy file: %w", err)
+		}
+	case FormatCSV:
+		if file, err = readCSV(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported policy file format %q", format)
+	}
+
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func readCSV(r io.Reader) (*PolicyFile, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var policies, roles [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse csv policy file: %w", err)
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+
+		line, _ := reader.FieldPos(0)
+		switch record[0] {
+		case "p":
+			if len(record) != 5 {
+				return nil, fmt.Errorf("line %d: p rule expects sub, dom, obj, act", line)
+			}
+			policies = append(policies, record[1:])
+		case "g":
+			if len(record) != 4 {
+				return nil, fmt.Errorf("line %d: g rule expects user, role, dom", line)
+			}
+			roles = append(roles, record[1:])
+		default:
+			return nil, fmt.Errorf("line %d: unknown rule type %q", line, record[0])
+		}
+	}
+
+	file := &PolicyFile{}
+	for _, rule := range policies {
+		file.Policies = append(file.Policies, PolicyRule{Sub: rule[0], Dom: rule[1], Obj: rule[2], Act: rule[3]})
+	}
+	for _, rule := range roles {
+		file.Roles = append(file.Roles, RoleRule{User: rule[0], Role: rule[1], Dom: rule[2]})
+	}
+	return file, nil
+}
+
+// WritePolicyFile 按指定格式写出策略文件
+func WritePolicyFile(w io.Writer, file *PolicyFile, format string) error {
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(file); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		for _, rule := range file.Policies {
+			if err := writer.Write(append([]string{"p"}, rule.Values()...)); err != nil {
+				return err
+			}
+		}
+		for _, rule := range file.Roles {
+			if err := writer.Write(append([]string{"g"}, rule.Values()...)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported policy file format %q", format)
+	}
+}
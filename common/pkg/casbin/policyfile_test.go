@@ -0,0 +1,79 @@
+package casbin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyFile_RoundTrip(t *testing.T) {
+	file := NewPolicyFile(
+		[][]string{
+			{"viewer", "acme", "/api/v1/users/:id", "GET"},
+			{"admin", AllDomains, "/api/v1/admin/*", "*"},
+		},
+		[][]string{{"alice", "viewer", "acme"}, {"root", "admin", AllDomains}},
+	)
+	// 按租户排序
+	assert.Equal(t, "admin", file.Policies[0].Sub)
+	assert.Equal(t, "root", file.Roles[0].User)
+
+	for _, format := range []string{FormatYAML, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WritePolicyFile(&buf, file, format))
+			parsed, err := ReadPolicyFile(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, file, parsed)
+		})
+	}
+}
+
+func TestReadPolicyFile_CSV(t *testing.T) {
+	content := `# 平台管理员
+p, admin, *, /api/v1/admin/*, *
+p, $owner, *, /api/v1/users/:id, GET
+
+g, root, admin, *
+`
+	file, err := ReadPolicyFile(strings.NewReader(content), FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyRule{
+		{Sub: "admin", Dom: "*", Obj: "/api/v1/admin/*", Act: "*"},
+		{Sub: OwnerSubject, Dom: "*", Obj: "/api/v1/users/:id", Act: "GET"},
+	}, file.Policies)
+	assert.Equal(t, []RoleRule{{User: "root", Role: "admin", Dom: "*"}}, file.Roles)
+}
+
+func TestReadPolicyFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+	}{
+		{"csv missing field", FormatCSV, "p, admin, *, /api/v1/admin/*\n"},
+		{"csv unknown type", FormatCSV, "x, admin, *\n"},
+		{"yaml empty field", FormatYAML, "policies:\n  - sub: admin\n    dom: acme\n    obj: /api/v1/users\n"},
+		{"owner as role", FormatYAML, "roles:\n  - user: alice\n    role: $owner\n    dom: acme\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadPolicyFile(strings.NewReader(tt.content), tt.format)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	format, err := FormatFromPath("deploy/policies.YML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+	format, err = FormatFromPath("policies.csv")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	_, err = FormatFromPath("policies.json")
+	assert.Error(t, err)
+}
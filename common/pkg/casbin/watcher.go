@@ -0,0 +1,367 @@
+package casbin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+)
+
+// DefaultWatcherChannel 未配置频道时使用的策略变更通知频道
+const DefaultWatcherChannel = "casbin:policy:changed"
+
+const (
+	watcherOpAddPolicies          = "add_policies"
+	watcherOpRemovePolicies       = "remove_policies"
+	watcherOpRemoveFilteredPolicy = "remove_filtered_policy"
+	watcherOpReload               = "reload"
+
+	// watcherPublishTimeout 发布通知的超时时间
+	watcherPublishTimeout = 3 * time.Second
+	// watcherRetryInterval 订阅连接异常后的重试间隔
+	watcherRetryInterval = time.Second
+)
+
+var _ persist.WatcherEx = (*PolicyWatcher)(nil)
+
+// watcherMessage 策略变更通知
+type watcherMessage struct {
+	InstanceID  string     `json:"instance_id"`
+	Op          string     `json:"op"`
+	Sec         string     `json:"sec,omitempty"`
+	PType       string     `json:"ptype,omitempty"`
+	Rules       [][]string `json:"rules,omitempty"`
+	FieldIndex  int        `json:"field_index,omitempty"`
+	FieldValues []string   `json:"field_values,omitempty"`
+}
+
+// SyncStatus 本实例的策略同步状态
+type SyncStatus struct {
+	Enabled    bool      // 是否启用跨实例同步
+	InstanceID string    // 本实例标识，用于忽略自己发布的通知
+	LastSyncAt time.Time // 最近一次加载策略或应用其他实例变更的时间
+}
+
+// PolicyWatcher 基于Redis发布订阅的策略变更同步器
+// 本实例修改策略后由enforcer自动发布通知，其他实例只在内存中增量应用变更并清空鉴权缓存，不会重复写库；
+// 通知无法增量应用或订阅断线重连后，从数据库全量重新加载策略
+type PolicyWatcher struct {
+	enforcer   *casbin.SyncedCachedEnforcer
+	client     *redis.RedisClient
+	logger     *zap.Logger
+	enabled    bool
+	channel    string
+	instanceID string
+
+	mu       sync.RWMutex
+	callback func(string)
+	lastSync time.Time
+
+	pubsub *goredis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPolicyWatcher 创建策略同步器，启用时注册为enforcer的watcher
+// enforcer在创建时已加载过策略，因此以创建时间作为首次同步时间
+func NewPolicyWatcher(enforcer *casbin.SyncedCachedEnforcer, client *redis.RedisClient, cfg config.CasbinWatcherConfig, logger *zap.Logger) (*PolicyWatcher, error) {
+	w := &PolicyWatcher{
+		enforcer:   enforcer,
+		client:     client,
+		logger:     logger,
+		enabled:    cfg.Enabled,
+		channel:    cfg.Channel,
+		instanceID: newInstanceID(),
+		lastSync:   time.Now(),
+	}
+	if w.channel == "" {
+		w.channel = DefaultWatcherChannel
+	}
+	w.callback = w.applyMessage
+
+	if !w.enabled {
+		return w, nil
+	}
+	if err := enforcer.SetWatcher(w); err != nil {
+		return nil, fmt.Errorf("failed to set casbin watcher: %w", err)
+	}
+	return w, nil
+}
+
+// newInstanceID 生成实例标识，主机名便于排查，随机后缀区分同一主机上的多个进程
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
+
+// Start 订阅策略变更频道
+func (w *PolicyWatcher) Start(ctx context.Context) error {
+	if !w.enabled {
+		return nil
+	}
+
+	pubsub := w.client.Subscribe(ctx, w.channel)
+	// 等待订阅确认，之后再收到的订阅确认都意味着断线重连
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return fmt.Errorf("failed to subscribe casbin watcher channel: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	w.pubsub = pubsub
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go w.run(runCtx)
+
+	w.logger.Info("Casbin policy watcher started",
+		zap.String("channel", w.channel),
+		zap.String("instance_id", w.instanceID))
+	return nil
+}
+
+// Stop 取消订阅并等待接收协程退出
+func (w *PolicyWatcher) Stop(ctx context.Context) error {
+	if w.pubsub == nil {
+		return nil
+	}
+
+	w.cancel()
+	err := w.pubsub.Close()
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	w.pubsub = nil
+	return err
+}
+
+func (w *PolicyWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	for {
+		received, err := w.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			w.logger.Warn("Casbin watcher failed to receive message", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watcherRetryInterval):
+			}
+			continue
+		}
+
+		switch msg := received.(type) {
+		case *goredis.Subscription:
+			// 断线期间的通知已经丢失，只能全量重新加载
+			if msg.Kind == "subscribe" {
+				w.reload("resubscribed")
+			}
+		case *goredis.Message:
+			w.handle(msg.Payload)
+		}
+	}
+}
+
+// handle 忽略本实例发布的通知，其余交给回调处理
+func (w *PolicyWatcher) handle(payload string) {
+	var msg watcherMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err == nil && msg.InstanceID == w.instanceID {
+		return
+	}
+
+	w.mu.RLock()
+	callback := w.callback
+	w.mu.RUnlock()
+	callback(payload)
+}
+
+// applyMessage 默认回调，增量应用其他实例的策略变更
+func (w *PolicyWatcher) applyMessage(payload string) {
+	var msg watcherMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		w.logger.Warn("Casbin watcher received invalid message", zap.String("payload", payload), zap.Error(err))
+		w.reload("invalid message")
+		return
+	}
+	if msg.Op == watcherOpReload {
+		w.reload("requested by " + msg.InstanceID)
+		return
+	}
+
+	if err := w.apply(msg); err != nil {
+		w.logger.Warn("Casbin watcher failed to apply policy change incrementally",
+			zap.String("op", msg.Op),
+			zap.String("from", msg.InstanceID),
+			zap.Error(err))
+		w.reload("incremental update failed")
+		return
+	}
+	if err := w.enforcer.InvalidateCache(); err != nil {
+		w.logger.Warn("Casbin watcher failed to invalidate cache", zap.Error(err))
+	}
+	w.markSynced()
+
+	w.logger.Debug("Casbin policy change applied",
+		zap.String("op", msg.Op),
+		zap.String("ptype", msg.PType),
+		zap.String("from", msg.InstanceID))
+}
+
+// apply 直接修改内存中的模型，enforcer的Self*方法在开启自动保存时仍会写库，因此不能使用
+func (w *PolicyWatcher) apply(msg watcherMessage) error {
+	lock := w.enforcer.GetLock()
+	lock.Lock()
+	defer lock.Unlock()
+
+	enforcer := w.enforcer.SyncedEnforcer.Enforcer
+	m := enforcer.GetModel()
+
+	var (
+		op       model.PolicyOp
+		affected [][]string
+		err      error
+	)
+	switch msg.Op {
+	case watcherOpAddPolicies:
+		op = model.PolicyAdd
+		affected, err = m.AddPoliciesWithAffected(msg.Sec, msg.PType, msg.Rules)
+	case watcherOpRemovePolicies:
+		op = model.PolicyRemove
+		affected, err = m.RemovePoliciesWithAffected(msg.Sec, msg.PType, msg.Rules)
+	case watcherOpRemoveFilteredPolicy:
+		op = model.PolicyRemove
+		_, affected, err = m.RemoveFilteredPolicy(msg.Sec, msg.PType, msg.FieldIndex, msg.FieldValues...)
+	default:
+		return fmt.Errorf("unknown watcher op %q", msg.Op)
+	}
+	if err != nil {
+		return err
+	}
+
+	if msg.Sec == "g" && len(affected) > 0 {
+		return enforcer.BuildIncrementalRoleLinks(op, msg.PType, affected)
+	}
+	return nil
+}
+
+// reload 从数据库全量重新加载策略，同时清空鉴权缓存
+func (w *PolicyWatcher) reload(reason string) {
+	if err := w.enforcer.LoadPolicy(); err != nil {
+		w.logger.Error("Casbin watcher failed to reload policy", zap.String("reason", reason), zap.Error(err))
+		return
+	}
+	w.markSynced()
+	w.logger.Info("Casbin policy reloaded", zap.String("reason", reason))
+}
+
+func (w *PolicyWatcher) markSynced() {
+	w.mu.Lock()
+	w.lastSync = time.Now()
+	w.mu.Unlock()
+}
+
+// Status 返回本实例的策略同步状态
+func (w *PolicyWatcher) Status() SyncStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return SyncStatus{
+		Enabled:    w.enabled,
+		InstanceID: w.instanceID,
+		LastSyncAt: w.lastSync,
+	}
+}
+
+// publish 发布策略变更通知
+// 变更已写库并在本实例生效，发布失败只记录日志而不返回错误，避免调用方误判为变更失败
+func (w *PolicyWatcher) publish(msg watcherMessage) error {
+	msg.InstanceID = w.instanceID
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		w.logger.Error("Failed to encode casbin policy change", zap.String("op", msg.Op), zap.Error(err))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), watcherPublishTimeout)
+	defer cancel()
+	if err := w.client.Publish(ctx, w.channel, payload).Err(); err != nil {
+		w.logger.Error("Failed to publish casbin policy change, other instances stay stale until reload",
+			zap.String("op", msg.Op),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// SetUpdateCallback 替换收到通知后的处理逻辑
+func (w *PolicyWatcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update 通知其他实例全量重新加载策略
+func (w *PolicyWatcher) Update() error {
+	return w.publish(watcherMessage{Op: watcherOpReload})
+}
+
+// Close 实现 persist.Watcher
+func (w *PolicyWatcher) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), watcherPublishTimeout)
+	defer cancel()
+	_ = w.Stop(ctx)
+}
+
+// UpdateForAddPolicy 实现 persist.WatcherEx
+func (w *PolicyWatcher) UpdateForAddPolicy(sec, ptype string, params ...string) error {
+	return w.UpdateForAddPolicies(sec, ptype, params)
+}
+
+// UpdateForRemovePolicy 实现 persist.WatcherEx
+func (w *PolicyWatcher) UpdateForRemovePolicy(sec, ptype string, params ...string) error {
+	return w.UpdateForRemovePolicies(sec, ptype, params)
+}
+
+// UpdateForRemoveFilteredPolicy 实现 persist.WatcherEx
+func (w *PolicyWatcher) UpdateForRemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return w.publish(watcherMessage{
+		Op:          watcherOpRemoveFilteredPolicy,
+		Sec:         sec,
+		PType:       ptype,
+		FieldIndex:  fieldIndex,
+		FieldValues: fieldValues,
+	})
+}
+
+// UpdateForSavePolicy 实现 persist.WatcherEx，整体保存后其他实例全量重新加载
+func (w *PolicyWatcher) UpdateForSavePolicy(model.Model) error {
+	return w.Update()
+}
+
+// UpdateForAddPolicies 实现 persist.WatcherEx
+func (w *PolicyWatcher) UpdateForAddPolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(watcherMessage{Op: watcherOpAddPolicies, Sec: sec, PType: ptype, Rules: rules})
+}
+
+// UpdateForRemovePolicies 实现 persist.WatcherEx
+func (w *PolicyWatcher) UpdateForRemovePolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(watcherMessage{Op: watcherOpRemovePolicies, Sec: sec, PType: ptype, Rules: rules})
+}
@@ -0,0 +1,110 @@
+package casbin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"common/config"
+)
+
+func newTestWatcher(t *testing.T) *PolicyWatcher {
+	t.Helper()
+	w, err := NewPolicyWatcher(newTestEnforcer(t), nil, config.CasbinWatcherConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	return w
+}
+
+func encodeMessage(t *testing.T, msg watcherMessage) string {
+	t.Helper()
+	payload, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return string(payload)
+}
+
+func TestPolicyWatcher_AppliesRemoteChangesAndInvalidatesCache(t *testing.T) {
+	w := newTestWatcher(t)
+	e := w.enforcer
+	before := w.Status().LastSyncAt
+
+	// 先缓存一次拒绝结果，确认收到通知后缓存被清空
+	allowed, err := e.Enforce("alice", "acme", "/api/v1/users/1", "GET")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	w.handle(encodeMessage(t, watcherMessage{
+		InstanceID: "other",
+		Op:         watcherOpAddPolicies,
+		Sec:        "p",
+		PType:      "p",
+		Rules:      [][]string{{"admin", "acme", "/api/v1/users/*", "*"}},
+	}))
+	w.handle(encodeMessage(t, watcherMessage{
+		InstanceID: "other",
+		Op:         watcherOpAddPolicies,
+		Sec:        "g",
+		PType:      "g",
+		Rules:      [][]string{{"alice", "admin", "acme"}},
+	}))
+
+	allowed, err = e.Enforce("alice", "acme", "/api/v1/users/1", "GET")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.False(t, w.Status().LastSyncAt.Before(before))
+
+	w.handle(encodeMessage(t, watcherMessage{
+		InstanceID:  "other",
+		Op:          watcherOpRemoveFilteredPolicy,
+		Sec:         "g",
+		PType:       "g",
+		FieldIndex:  0,
+		FieldValues: []string{"alice"},
+	}))
+
+	allowed, err = e.Enforce("alice", "acme", "/api/v1/users/1", "GET")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	hasPolicy, err := e.HasPolicy("admin", "acme", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	assert.True(t, hasPolicy)
+
+	w.handle(encodeMessage(t, watcherMessage{
+		InstanceID: "other",
+		Op:         watcherOpRemovePolicies,
+		Sec:        "p",
+		PType:      "p",
+		Rules:      [][]string{{"admin", "acme", "/api/v1/users/*", "*"}},
+	}))
+	hasPolicy, err = e.HasPolicy("admin", "acme", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	assert.False(t, hasPolicy)
+}
+
+func TestPolicyWatcher_IgnoresOwnMessages(t *testing.T) {
+	w := newTestWatcher(t)
+
+	w.handle(encodeMessage(t, watcherMessage{
+		InstanceID: w.Status().InstanceID,
+		Op:         watcherOpAddPolicies,
+		Sec:        "p",
+		PType:      "p",
+		Rules:      [][]string{{"admin", "acme", "/api/v1/users/*", "*"}},
+	}))
+
+	hasPolicy, err := w.enforcer.HasPolicy("admin", "acme", "/api/v1/users/*", "*")
+	require.NoError(t, err)
+	assert.False(t, hasPolicy)
+}
+
+func TestPolicyWatcher_Disabled(t *testing.T) {
+	w := newTestWatcher(t)
+	status := w.Status()
+	assert.False(t, status.Enabled)
+	assert.NotEmpty(t, status.InstanceID)
+	assert.False(t, status.LastSyncAt.IsZero())
+	assert.NoError(t, w.Start(t.Context()))
+	assert.NoError(t, w.Stop(t.Context()))
+}
@@ -11,6 +11,8 @@ type contextKey string
 var (
 	// UserIDKey 是在context中存储用户ID的键
 	UserIDKey contextKey = "user_id"
+	// ClaimsKey 是在context中存储JWT声明(*jwt.CustomClaims)的键
+	ClaimsKey contextKey = "claims"
 	// ClientIPContextKey 是在context中存储客户端IP字符串的键
 	ClientIPContextKey contextKey = "clientIP"
 	// ClientParsedIPContextKey 是在context中存储解析后的net.IP对象的键
@@ -19,6 +21,14 @@ var (
 	TraceIDKey contextKey = "traceID"
 	// AuthHeaderKey 认证头键名
 	AuthHeaderKey contextKey = "Authorization"
+	// APIKeyHeaderKey 服务间调用携带API Key的请求头键名
+	APIKeyHeaderKey contextKey = "X-API-Key"
+	// APIKeyPrincipalKey 是在context中存储API Key调用方(*middleware.APIKeyPrincipal)的键
+	APIKeyPrincipalKey contextKey = "apiKeyPrincipal"
+	// TenantIDKey 是在context中存储当前请求所属租户(Casbin域)的键
+	TenantIDKey contextKey = "tenantID"
+	// TenantHeaderKey 未在JWT中携带租户时指定租户的默认请求头键名
+	TenantHeaderKey contextKey = "X-Tenant-ID"
 	// ContextLoggerKey 存储日志记录器的键
 	ContextLoggerKey contextKey = "contextLogger"
 )
@@ -32,3 +42,9 @@ func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserIDKey).(string)
 	return userID, ok
 }
+
+// GetTenantIDFromContext 从context中获取当前请求所属租户
+func GetTenantIDFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(TenantIDKey).(string)
+	return tenantID, ok && tenantID != ""
+}
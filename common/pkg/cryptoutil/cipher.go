@@ -0,0 +1,71 @@
+package cryptoutil
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCiphertext 密文格式错误或认证失败(密钥不匹配、数据被篡改)
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Cipher AES-GCM 字段级加密，用于在数据库中保存敏感数据
+// 密文格式为 base64(nonce || ciphertext || tag)
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher 使用16/24/32字节的密钥创建加密器
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create aes cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// NewCipherFromSecret 由任意长度的口令派生32字节密钥创建加密器
+func NewCipherFromSecret(secret string) (*Cipher, error) {
+	if secret == "" {
+		return nil, errors.New("empty encryption secret")
+	}
+	key := sha256.Sum256([]byte(secret))
+	return NewCipher(key[:])
+}
+
+// Encrypt 加密字符串，每次加密使用随机nonce，相同明文的密文不同
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,50 @@
+package cryptoutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipherFromSecret("secret")
+	require.NoError(t, err)
+
+	first, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	second, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "nonce should be random")
+
+	plaintext, err := c.Decrypt(first)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plaintext)
+}
+
+func TestCipher_RejectsTamperedOrForeignCiphertext(t *testing.T) {
+	c, err := NewCipherFromSecret("secret")
+	require.NoError(t, err)
+	other, err := NewCipherFromSecret("other")
+	require.NoError(t, err)
+
+	ciphertext, err := c.Encrypt("value")
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = c.Decrypt("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = c.Decrypt("AAAA")
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestNewCipher_InvalidKey(t *testing.T) {
+	_, err := NewCipher([]byte("short"))
+	assert.Error(t, err)
+
+	_, err = NewCipherFromSecret("")
+	assert.Error(t, err)
+}
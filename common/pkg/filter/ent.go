@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"entgo.io/ent/dialect/sql"
+)
+
+// Predicates 将过滤条件转换为ent谓词，P 为生成代码中的谓词类型，例如 predicate.User
+//
+//	query.Where(filter.Predicates[predicate.User](q)...)
+func Predicates[P ~func(*sql.Selector)](q *Query) []P {
+	if q == nil {
+		return nil
+	}
+	predicates := make([]P, 0, len(q.Conditions))
+	for _, c := range q.Conditions {
+		predicates = append(predicates, P(predicate(c)))
+	}
+	return predicates
+}
+
+// OrderOptions 将排序转换为ent排序选项，O 为生成代码中的排序类型，例如 user.OrderOption
+//
+//	query.Order(filter.OrderOptions[user.OrderOption](q)...)
+func OrderOptions[O ~func(*sql.Selector)](q *Query) []O {
+	if q == nil {
+		return nil
+	}
+	orders := make([]O, 0, len(q.Sorts))
+	for _, s := range q.Sorts {
+		direction := sql.OrderAsc()
+		if s.Desc {
+			direction = sql.OrderDesc()
+		}
+		orders = append(orders, O(sql.OrderByField(s.Column, direction).ToFunc()))
+	}
+	return orders
+}
+
+func predicate(c Condition) func(*sql.Selector) {
+	switch c.Op {
+	case OpNe:
+		return sql.FieldNEQ(c.Column, c.Values[0])
+	case OpGt:
+		return sql.FieldGT(c.Column, c.Values[0])
+	case OpGte:
+		return sql.FieldGTE(c.Column, c.Values[0])
+	case OpLt:
+		return sql.FieldLT(c.Column, c.Values[0])
+	case OpLte:
+		return sql.FieldLTE(c.Column, c.Values[0])
+	case OpLike:
+		// FieldContains 会转义 % 和 _，用户输入按字面匹配
+		return sql.FieldContains(c.Column, c.Values[0].(string))
+	case OpIn:
+		return sql.FieldIn(c.Column, c.Values...)
+	default:
+		return sql.FieldEQ(c.Column, c.Values[0])
+	}
+}
@@ -0,0 +1,112 @@
+package filter
+
+// Operator 过滤操作符
+type Operator string
+
+// 支持的过滤操作符
+const (
+	OpEq   Operator = "eq"   // 等于，省略操作符时的默认值
+	OpNe   Operator = "ne"   // 不等于
+	OpGt   Operator = "gt"   // 大于
+	OpGte  Operator = "gte"  // 大于等于
+	OpLt   Operator = "lt"   // 小于
+	OpLte  Operator = "lte"  // 小于等于
+	OpLike Operator = "like" // 包含，仅用于字符串
+	OpIn   Operator = "in"   // 属于，多个值以逗号分隔
+)
+
+// FieldType 字段值类型，决定查询参数如何解析
+type FieldType int
+
+const (
+	TypeString FieldType = iota
+	TypeInt
+	TypeTime // RFC3339 或 YYYY-MM-DD，日期按本地时区解析
+)
+
+// Field 允许过滤或排序的字段
+type Field struct {
+	Name      string     // 查询参数中的字段名
+	Column    string     // 数据库列名，为空时与Name相同
+	Type      FieldType  // 值类型
+	Operators []Operator // 允许的过滤操作符，为空时该字段不能过滤
+	Enum      []string   // 允许的取值，为空时不限制
+	Sortable  bool       // 是否允许排序
+	// Normalize 在按类型解析前转换取值(如把手机号转换为保存时的格式)，返回的错误作为该参数的错误描述
+	Normalize func(raw string) (string, error)
+}
+
+// column 数据库列名
+func (f *Field) column() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.Name
+}
+
+func (f *Field) allows(op Operator) bool {
+	for _, allowed := range f.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Field) inEnum(raw string) bool {
+	if len(f.Enum) == 0 {
+		return true
+	}
+	for _, v := range f.Enum {
+		if v == raw {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema 资源的过滤和排序白名单，每个资源声明一次，请求中出现白名单以外的字段或操作符时解析失败
+type Schema struct {
+	fields      map[string]*Field
+	defaultSort []Sort
+}
+
+// NewSchema 创建白名单，defaultSort 为未指定 sort 参数时的排序，格式与 sort 参数相同
+func NewSchema(defaultSort string, fields ...Field) *Schema {
+	s := &Schema{fields: make(map[string]*Field, len(fields))}
+	for i := range fields {
+		if fields[i].allows(OpLike) && fields[i].Type != TypeString {
+			panic("filter: like operator requires a string field: " + fields[i].Name)
+		}
+		s.fields[fields[i].Name] = &fields[i]
+	}
+	if defaultSort != "" {
+		sorts, errs := s.parseSort(defaultSort)
+		if len(errs) > 0 {
+			panic("filter: invalid default sort " + defaultSort)
+		}
+		s.defaultSort = sorts
+	}
+	return s
+}
+
+// Condition 一个过滤条件
+type Condition struct {
+	Field  string
+	Column string
+	Op     Operator
+	Values []any // 已按字段类型解析的值，只有 OpIn 会有多个
+}
+
+// Sort 一个排序字段
+type Sort struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// Query 解析后的过滤和排序条件，条件之间为 AND 关系
+type Query struct {
+	Conditions []Condition
+	Sorts      []Sort
+}
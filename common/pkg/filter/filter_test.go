@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = NewSchema("-created_at",
+	Field{Name: "name", Type: TypeString, Operators: []Operator{OpEq, OpLike}, Sortable: true},
+	Field{Name: "gender", Type: TypeInt, Operators: []Operator{OpEq, OpIn}, Enum: []string{"100", "200", "300"}},
+	Field{Name: "created_at", Type: TypeTime, Operators: []Operator{OpGte, OpLt}, Sortable: true},
+	Field{Name: "email", Column: "email_address", Type: TypeString, Operators: []Operator{OpEq}},
+)
+
+func TestSchema_Parse(t *testing.T) {
+	values, _ := url.ParseQuery("filter[name][like]=张&filter[gender][in]=100,200&filter[created_at][gte]=2026-01-01&sort=-created_at,name&page=2")
+
+	q, err := testSchema.Parse(values)
+	require.NoError(t, err)
+
+	require.Len(t, q.Conditions, 3)
+	assert.Equal(t, Condition{Field: "created_at", Column: "created_at", Op: OpGte,
+		Values: []any{time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)}}, q.Conditions[0])
+	assert.Equal(t, Condition{Field: "gender", Column: "gender", Op: OpIn, Values: []any{int64(100), int64(200)}}, q.Conditions[1])
+	assert.Equal(t, Condition{Field: "name", Column: "name", Op: OpLike, Values: []any{"张"}}, q.Conditions[2])
+	assert.Equal(t, []Sort{{Field: "created_at", Column: "created_at", Desc: true}, {Field: "name", Column: "name"}}, q.Sorts)
+}
+
+func TestSchema_ParseDefaults(t *testing.T) {
+	values, _ := url.ParseQuery("filter[email]=a@example.com")
+
+	q, err := testSchema.Parse(values)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Condition{{Field: "email", Column: "email_address", Op: OpEq, Values: []any{"a@example.com"}}}, q.Conditions)
+	assert.Equal(t, []Sort{{Field: "created_at", Column: "created_at", Desc: true}}, q.Sorts)
+}
+
+func TestSchema_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"unknown field", "filter[password]=x", "filter[password]"},
+		{"operator not allowed", "filter[name][gt]=x", "filter[name][gt]"},
+		{"malformed key", "filter[name]]=x", "filter[name]]"},
+		{"not an integer", "filter[gender]=male", "filter[gender]"},
+		{"not in enum", "filter[gender][in]=100,400", "filter[gender][in]"},
+		{"invalid time", "filter[created_at][lt]=yesterday", "filter[created_at][lt]"},
+		{"empty value", "filter[name]=", "filter[name]"},
+		{"unsortable field", "sort=gender", SortParam},
+		{"duplicate sort field", "sort=name,-name", SortParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+
+			_, err := testSchema.Parse(values)
+
+			var errs Errors
+			require.ErrorAs(t, err, &errs)
+			assert.Contains(t, errs.FieldErrors(), tt.param)
+		})
+	}
+}
+
+func TestSchema_ParseCollectsAllErrors(t *testing.T) {
+	values, _ := url.ParseQuery("filter[password]=x&filter[gender]=male&sort=gender")
+
+	_, err := testSchema.Parse(values)
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 3)
+}
+
+func TestSchema_ParseNormalize(t *testing.T) {
+	schema := NewSchema("", Field{
+		Name:      "phone",
+		Type:      TypeString,
+		Operators: []Operator{OpEq, OpIn},
+		Normalize: func(raw string) (string, error) {
+			digits := strings.ReplaceAll(strings.TrimPrefix(raw, "+86"), " ", "")
+			if len(digits) != 11 {
+				return "", errors.New("手机号格式不正确")
+			}
+			return "+86" + digits, nil
+		},
+	})
+
+	values, _ := url.ParseQuery("filter[phone][in]=138 0013 8000,%2B8613900139000")
+	q, err := schema.Parse(values)
+	require.NoError(t, err)
+	assert.Equal(t, []Condition{{Field: "phone", Column: "phone", Op: OpIn,
+		Values: []any{"+8613800138000", "+8613900139000"}}}, q.Conditions)
+
+	values, _ = url.ParseQuery("filter[phone]=12345")
+	_, err = schema.Parse(values)
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, "手机号格式不正确", errs["filter[phone]"])
+}
+
+func TestPredicatesAndOrderOptions(t *testing.T) {
+	values := url.Values{
+		"filter[name][like]": {"50%"},
+		"filter[gender][in]": {"100,200"},
+		"sort":               {"name"},
+	}
+	q, err := testSchema.Parse(values)
+	require.NoError(t, err)
+
+	selector := sql.Dialect(dialect.MySQL).Select("*").From(sql.Table("users"))
+	for _, p := range Predicates[func(*sql.Selector)](q) {
+		p(selector)
+	}
+	for _, o := range OrderOptions[func(*sql.Selector)](q) {
+		o(selector)
+	}
+
+	query, args := selector.Query()
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`gender` IN (?, ?) AND `users`.`name` LIKE ? ORDER BY `users`.`name`", query)
+	assert.Equal(t, []any{int64(100), int64(200), "%50\\%%"}, args)
+}
@@ -0,0 +1,194 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 查询参数名
+const (
+	FilterParam = "filter"
+	SortParam   = "sort"
+)
+
+// maxInValues in 操作符最多允许的取值个数
+const maxInValues = 100
+
+// filterKeyPattern 匹配 filter[field] 和 filter[field][op]
+var filterKeyPattern = regexp.MustCompile(`^filter\[([A-Za-z0-9_]+)\](?:\[([A-Za-z]+)\])?$`)
+
+// Errors 解析错误，键为出错的查询参数名，值为错误描述，结构与参数校验失败时返回的数据一致
+type Errors map[string]string
+
+func (e Errors) Error() string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+": "+e[k])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FieldErrors 按参数名返回错误描述
+func (e Errors) FieldErrors() map[string]string {
+	return e
+}
+
+// Parse 解析查询参数中的 filter[field][op]=value 和 sort=-field1,field2
+// 省略操作符时为 eq，sort 中字段前加 - 表示倒序，未指定 sort 时使用默认排序
+func (s *Schema) Parse(values url.Values) (*Query, error) {
+	query := &Query{}
+	errs := Errors{}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == FilterParam || strings.HasPrefix(key, FilterParam+"[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys) // 条件顺序稳定，便于日志比对和复用查询计划
+
+	for _, key := range keys {
+		match := filterKeyPattern.FindStringSubmatch(key)
+		if match == nil {
+			errs[key] = "格式应为filter[字段]或filter[字段][操作符]"
+			continue
+		}
+		field, ok := s.fields[match[1]]
+		if !ok || len(field.Operators) == 0 {
+			errs[key] = "不支持按该字段过滤"
+			continue
+		}
+		op := OpEq
+		if match[2] != "" {
+			op = Operator(strings.ToLower(match[2]))
+		}
+		if !field.allows(op) {
+			errs[key] = fmt.Sprintf("该字段不支持%s操作符", op)
+			continue
+		}
+
+		for _, raw := range values[key] {
+			parsed, err := field.parseValues(op, raw)
+			if err != nil {
+				errs[key] = err.Error()
+				break
+			}
+			query.Conditions = append(query.Conditions, Condition{
+				Field:  field.Name,
+				Column: field.column(),
+				Op:     op,
+				Values: parsed,
+			})
+		}
+	}
+
+	query.Sorts = s.defaultSort
+	if raw := strings.TrimSpace(values.Get(SortParam)); raw != "" {
+		sorts, sortErrs := s.parseSort(raw)
+		for k, v := range sortErrs {
+			errs[k] = v
+		}
+		query.Sorts = sorts
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return query, nil
+}
+
+// parseSort 解析逗号分隔的排序字段
+func (s *Schema) parseSort(raw string) ([]Sort, Errors) {
+	errs := Errors{}
+	seen := make(map[string]bool)
+	sorts := make([]Sort, 0)
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+
+		field, ok := s.fields[name]
+		switch {
+		case name == "":
+			errs[SortParam] = "排序字段不能为空"
+		case !ok || !field.Sortable:
+			errs[SortParam] = fmt.Sprintf("不支持按%s排序", name)
+		case seen[name]:
+			errs[SortParam] = fmt.Sprintf("排序字段%s重复", name)
+		default:
+			seen[name] = true
+			sorts = append(sorts, Sort{Field: field.Name, Column: field.column(), Desc: desc})
+		}
+	}
+	return sorts, errs
+}
+
+// parseValues 按字段类型解析参数值，in 操作符的值以逗号分隔，每个值分别转换
+func (f *Field) parseValues(op Operator, raw string) ([]any, error) {
+	raws := []string{raw}
+	if op == OpIn {
+		raws = strings.Split(raw, ",")
+		if len(raws) > maxInValues {
+			return nil, fmt.Errorf("最多支持%d个取值", maxInValues)
+		}
+	}
+
+	values := make([]any, 0, len(raws))
+	for _, r := range raws {
+		if op != OpLike {
+			r = strings.TrimSpace(r)
+		}
+		if r == "" {
+			return nil, fmt.Errorf("值不能为空")
+		}
+		if f.Normalize != nil {
+			normalized, err := f.Normalize(r)
+			if err != nil {
+				return nil, err
+			}
+			r = normalized
+		}
+		if !f.inEnum(r) {
+			return nil, fmt.Errorf("取值应为%s之一", strings.Join(f.Enum, ","))
+		}
+		v, err := f.parseValue(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func (f *Field) parseValue(raw string) (any, error) {
+	switch f.Type {
+	case TypeInt:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("值应为整数")
+		}
+		return v, nil
+	case TypeTime:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		if t, err := time.ParseInLocation(time.DateOnly, raw, time.Local); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("时间格式应为RFC3339或YYYY-MM-DD")
+	default:
+		return raw, nil
+	}
+}
@@ -1,7 +1,11 @@
 package jwt
 
 import (
+	"context"
+
 	"github.com/golang-jwt/jwt/v4"
+
+	"common/pkg/contextutil"
 )
 
 // CustomClaims 自定义声明类型并内嵌jwt.RegisteredClaims
@@ -11,5 +15,14 @@ type CustomClaims struct {
 	// 可根据需要自行添加字段
 	UserID               string `json:"user_id"`
 	Username             string `json:"username"`
+	SessionID            string `json:"sid,omitempty"`    // 登录会话ID，即刷新令牌族ID
+	TenantID             string `json:"tid,omitempty"`    // 所属租户，携带时优先于请求头
+	IssuedAtMilli        int64  `json:"iat_ms,omitempty"` // 毫秒精度的签发时间，iat只精确到秒，按用户吊销时用于区分同一秒内吊销前后签发的令牌
 	jwt.RegisteredClaims        // 内嵌标准的声明
 }
+
+// ClaimsFromContext 从context中获取认证中间件写入的JWT声明
+func ClaimsFromContext(ctx context.Context) (*CustomClaims, bool) {
+	claims, ok := ctx.Value(contextutil.ClaimsKey).(*CustomClaims)
+	return claims, ok && claims != nil
+}
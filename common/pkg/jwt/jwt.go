@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -13,33 +14,72 @@ import (
 // JWT JWT服务结构体
 type JWT struct {
 	config *config.Config
+	keys   *KeyManager
 }
 
 // NewJWT 创建JWT实例
-func NewJWT(cfg *config.Config) *JWT {
+// keys 未启用时使用 system.secret_key 进行HS256签名
+func NewJWT(cfg *config.Config, keys *KeyManager) *JWT {
 	return &JWT{
 		config: cfg,
+		keys:   keys,
+	}
+}
+
+// ExpiresIn 访问令牌有效期
+func (j *JWT) ExpiresIn() time.Duration {
+	return time.Duration(j.config.Token.ExpiredTime) * time.Minute
+}
+
+// GenerateOption 生成令牌时的声明选项
+type GenerateOption func(*CustomClaims)
+
+// WithSessionID 将令牌绑定到登录会话，会话被吊销后令牌随之失效
+func WithSessionID(sessionID string) GenerateOption {
+	return func(claims *CustomClaims) {
+		claims.SessionID = sessionID
+	}
+}
+
+// WithTenantID 将令牌绑定到租户，鉴权时以该租户为准，请求头指定其他租户时拒绝
+func WithTenantID(tenantID string) GenerateOption {
+	return func(claims *CustomClaims) {
+		claims.TenantID = tenantID
 	}
 }
 
 // Generate 生成JWT
 // @param userID 用户ID
 // @param username 用户名
+// @param opts 附加声明选项
 // @return string token
 // @return error 生成失败异常
-func (j *JWT) Generate(userID string, username string) (string, error) {
+func (j *JWT) Generate(userID string, username string, opts ...GenerateOption) (string, error) {
 	// 创建一个我们自己的声明
+	now := time.Now()
 	claims := CustomClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:        userID,
+		Username:      username,
+		IssuedAtMilli: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.NewString(),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(j.config.Token.ExpiredTime) * time.Minute)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.ExpiresIn())),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    j.config.System.ServerName,
 		},
 	}
+	for _, opt := range opts {
+		opt(&claims)
+	}
+
+	// 启用非对称签名时使用当前活动密钥签名，并在头部携带kid
+	if j.asymmetric() {
+		key := j.keys.signingKey()
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.kid
+		return token.SignedString(key.private)
+	}
 
 	// 使用指定的签名方法创建签名对象
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -55,11 +95,7 @@ func (j *JWT) Generate(userID string, username string) (string, error) {
 func (j *JWT) ParseToken(tokenString string) (*CustomClaims, error) {
 	// 解析token
 	// 如果是自定义Claim结构体则需要使用 ParseWithClaims 方法
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// 直接使用标准的Claim则可以直接使用Parse方法
-		//token, err := jwt.Parse(tokenString, func(token *jwt.Token) (i interface{}, err error) {
-		return []byte(j.config.System.SecretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, j.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -72,3 +108,37 @@ func (j *JWT) ParseToken(tokenString string) (*CustomClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// JWKS 返回用于验签的公钥集合，未启用非对称签名时为空集合
+func (j *JWT) JWKS() *JSONWebKeySet {
+	if !j.asymmetric() {
+		return &JSONWebKeySet{Keys: []JSONWebKey{}}
+	}
+	return j.keys.JWKS()
+}
+
+// asymmetric 是否启用了非对称签名
+func (j *JWT) asymmetric() bool {
+	return j.keys != nil && j.keys.Enabled()
+}
+
+// keyFunc 根据令牌头部选择验签密钥
+// 令牌声明的算法必须与密钥的算法一致，防止算法混淆攻击
+func (j *JWT) keyFunc(token *jwt.Token) (interface{}, error) {
+	if !j.asymmetric() {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+		return []byte(j.config.System.SecretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, err := j.keys.verificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+	return key.public, nil
+}
@@ -0,0 +1,387 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+
+	"common/config"
+)
+
+const (
+	// privateKeySuffix 私钥文件后缀，文件名去掉后缀即为kid
+	privateKeySuffix = ".key.pem"
+	// publicKeySuffix 公钥文件后缀，只有公钥的kid仅用于验签(已退役的密钥)
+	publicKeySuffix = ".pub.pem"
+
+	// DefaultKeyReloadInterval 默认密钥目录检查间隔
+	DefaultKeyReloadInterval = time.Minute
+)
+
+// ErrUnknownKeyID 令牌头中的kid不存在于当前密钥集合
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// signingKey 单个签名密钥
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer // 为nil时表示已退役，仅用于验签
+	public  crypto.PublicKey
+}
+
+// keySet 某一时刻从密钥目录加载的全部密钥
+type keySet struct {
+	keys   map[string]*signingKey
+	active *signingKey
+}
+
+// KeyManager 非对称签名密钥管理器
+// 从 token.signing.key_dir 目录加载PEM密钥，<kid>.key.pem 为私钥，<kid>.pub.pem 为公钥。
+// 目录内容变化后会自动重新加载，无需重启服务即可完成密钥轮换。
+// 未配置密钥目录时 Enabled 返回 false，JWT 回退为使用 system.secret_key 的 HS256 签名。
+type KeyManager struct {
+	dir            string
+	activeKID      string
+	reloadInterval time.Duration
+	logger         *zap.Logger
+
+	mu          sync.RWMutex
+	set         *keySet
+	fingerprint string
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewKeyManager 创建密钥管理器并完成首次加载
+func NewKeyManager(cfg *config.Config, logger *zap.Logger) (*KeyManager, error) {
+	signing := cfg.Token.Signing
+	interval := signing.ReloadInterval
+	if interval <= 0 {
+		interval = DefaultKeyReloadInterval
+	}
+
+	m := &KeyManager{
+		dir:            signing.KeyDir,
+		activeKID:      signing.ActiveKID,
+		reloadInterval: interval,
+		logger:         logger,
+	}
+
+	if !m.Enabled() {
+		return m, nil
+	}
+
+	if _, err := m.Reload(); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys from %s: %w", m.dir, err)
+	}
+	return m, nil
+}
+
+// Enabled 是否启用了非对称签名
+func (m *KeyManager) Enabled() bool {
+	return m.dir != ""
+}
+
+// Reload 检查密钥目录，内容有变化时重新加载
+// 加载失败时保留原有密钥集合
+// @return bool 是否发生了重新加载
+func (m *KeyManager) Reload() (bool, error) {
+	fingerprint, err := dirFingerprint(m.dir)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.RLock()
+	unchanged := m.set != nil && fingerprint == m.fingerprint
+	m.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	set, err := loadKeySet(m.dir, m.activeKID)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	m.set = set
+	m.fingerprint = fingerprint
+	m.mu.Unlock()
+
+	m.logger.Info("JWT signing keys loaded",
+		zap.String("dir", m.dir),
+		zap.String("active_kid", set.active.kid),
+		zap.Int("keys", len(set.keys)))
+	return true, nil
+}
+
+// Start 启动后台密钥目录检查
+func (m *KeyManager) Start() {
+	if !m.Enabled() || m.stopCh != nil {
+		return
+	}
+
+	m.stopCh = make(chan struct{})
+	m.doneCh = make(chan struct{})
+
+	go func() {
+		defer close(m.doneCh)
+		ticker := time.NewTicker(m.reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := m.Reload(); err != nil {
+					m.logger.Error("Failed to reload JWT signing keys, keeping previous keys", zap.Error(err))
+				}
+			case <-m.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台密钥目录检查
+func (m *KeyManager) Stop(ctx context.Context) error {
+	if m.stopCh == nil {
+		return nil
+	}
+
+	close(m.stopCh)
+	select {
+	case <-m.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	m.stopCh = nil
+	return nil
+}
+
+// signingKey 返回当前用于签名的密钥
+func (m *KeyManager) signingKey() *signingKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.set.active
+}
+
+// verificationKey 根据kid返回验签密钥
+func (m *KeyManager) verificationKey(kid string) (*signingKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.set.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
+}
+
+// JWKS 返回全部密钥(包括已退役密钥)的公钥集合
+func (m *KeyManager) JWKS() *JSONWebKeySet {
+	jwks := &JSONWebKeySet{Keys: []JSONWebKey{}}
+	if !m.Enabled() {
+		return jwks
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	kids := make([]string, 0, len(m.set.keys))
+	for kid := range m.set.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	for _, kid := range kids {
+		if jwk, err := newJSONWebKey(m.set.keys[kid]); err == nil {
+			jwks.Keys = append(jwks.Keys, *jwk)
+		}
+	}
+	return jwks
+}
+
+// loadKeySet 加载密钥目录
+func loadKeySet(dir, activeKID string) (*keySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &keySet{keys: make(map[string]*signingKey)}
+
+	// 先加载私钥，再为仅有公钥的kid加载公钥
+	for _, suffix := range []string{privateKeySuffix, publicKeySuffix} {
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, suffix) {
+				continue
+			}
+
+			kid := strings.TrimSuffix(name, suffix)
+			if _, exists := set.keys[kid]; exists {
+				continue
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+
+			var key *signingKey
+			if suffix == privateKeySuffix {
+				key, err = parsePrivateKey(kid, data)
+			} else {
+				key, err = parsePublicKey(kid, data)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			set.keys[kid] = key
+		}
+	}
+
+	set.active, err = selectActiveKey(set.keys, activeKID)
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// selectActiveKey 选择签名密钥
+// 指定了 active_kid 时使用该密钥，否则使用kid排序最大的私钥(建议以日期作为kid，如 2025-01)
+func selectActiveKey(keys map[string]*signingKey, activeKID string) (*signingKey, error) {
+	if activeKID != "" {
+		key, ok := keys[activeKID]
+		if !ok || key.private == nil {
+			return nil, fmt.Errorf("private key for active kid %q not found", activeKID)
+		}
+		return key, nil
+	}
+
+	var active *signingKey
+	for _, key := range keys {
+		if key.private != nil && (active == nil || key.kid > active.kid) {
+			active = key
+		}
+	}
+	if active == nil {
+		return nil, errors.New("no private key found")
+	}
+	return active, nil
+}
+
+// parsePrivateKey 解析PEM私钥，支持 PKCS#8、PKCS#1(RSA) 和 SEC1(EC) 格式
+func parsePrivateKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+
+	key, err := parsePublicKeyValue(kid, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.private = signer
+	return key, nil
+}
+
+// parsePublicKey 解析PEM公钥(PKIX格式)
+func parsePublicKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return parsePublicKeyValue(kid, public)
+}
+
+// parsePublicKeyValue 根据公钥类型确定签名算法
+func parsePublicKeyValue(kid string, public crypto.PublicKey) (*signingKey, error) {
+	key := &signingKey{kid: kid, public: public}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			key.method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported elliptic curve")
+		}
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported public key type")
+	}
+	return key, nil
+}
+
+// dirFingerprint 计算密钥目录的指纹，用于判断目录内容是否变化
+func dirFingerprint(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, privateKeySuffix) || strings.HasSuffix(name, publicKeySuffix)) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"common/config"
+)
+
+func writePrivateKey(t *testing.T, dir, kid string, key crypto.Signer) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+privateKeySuffix), data, 0600))
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key crypto.PublicKey) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+publicKeySuffix), data, 0644))
+}
+
+func newTestJWT(t *testing.T, dir string) (*JWT, *KeyManager) {
+	cfg := &config.Config{}
+	cfg.Token.ExpiredTime = 30
+	cfg.Token.Signing.KeyDir = dir
+
+	keys, err := NewKeyManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	return NewJWT(cfg, keys), keys
+}
+
+func TestJWT_AsymmetricAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantAlg string
+		wantKty string
+	}{
+		{name: "rsa", key: rsaKey, wantAlg: "RS256", wantKty: "RSA"},
+		{name: "ecdsa p-256", key: ecKey, wantAlg: "ES256", wantKty: "EC"},
+		{name: "ed25519", key: edKey, wantAlg: "EdDSA", wantKty: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePrivateKey(t, dir, "k1", tt.key)
+			j, _ := newTestJWT(t, dir)
+
+			token, err := j.Generate("user-1", "alice")
+			require.NoError(t, err)
+
+			claims, err := j.ParseToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+
+			jwks := j.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "k1", jwks.Keys[0].Kid)
+			assert.Equal(t, tt.wantAlg, jwks.Keys[0].Alg)
+			assert.Equal(t, tt.wantKty, jwks.Keys[0].Kty)
+		})
+	}
+}
+
+func TestKeyManager_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writePrivateKey(t, dir, "2025-01", oldKey)
+	j, keys := newTestJWT(t, dir)
+
+	oldToken, err := j.Generate("user-1", "alice")
+	require.NoError(t, err)
+
+	// 放入新私钥并将旧密钥退役为仅公钥
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+	writePrivateKey(t, dir, "2025-02", newKey)
+	require.NoError(t, os.Remove(filepath.Join(dir, "2025-01"+privateKeySuffix)))
+	writePublicKey(t, dir, "2025-01", oldKey.Public())
+
+	reloaded, err := keys.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "2025-02", keys.signingKey().kid)
+
+	newToken, err := j.Generate("user-1", "alice")
+	require.NoError(t, err)
+
+	_, err = j.ParseToken(newToken)
+	assert.NoError(t, err)
+	_, err = j.ParseToken(oldToken)
+	assert.NoError(t, err, "tokens signed by a retired key should still verify")
+	assert.Len(t, j.JWKS().Keys, 2)
+
+	// 删除公钥后旧令牌不再可验
+	require.NoError(t, os.Remove(filepath.Join(dir, "2025-01"+publicKeySuffix)))
+	_, err = keys.Reload()
+	require.NoError(t, err)
+	_, err = j.ParseToken(oldToken)
+	assert.Error(t, err)
+}
+
+func TestJWT_RejectsSymmetricTokenWhenAsymmetric(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writePrivateKey(t, dir, "k1", key)
+	asymmetric, _ := newTestJWT(t, dir)
+
+	cfg := &config.Config{}
+	cfg.Token.ExpiredTime = 30
+	cfg.System.SecretKey = "secret"
+	symmetric := NewJWT(cfg, &KeyManager{})
+
+	token, err := symmetric.Generate("user-1", "alice")
+	require.NoError(t, err)
+
+	_, err = symmetric.ParseToken(token)
+	assert.NoError(t, err)
+	_, err = asymmetric.ParseToken(token)
+	assert.Error(t, err)
+}
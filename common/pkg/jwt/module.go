@@ -1,17 +1,33 @@
 package jwt
 
 import (
+	"context"
+
 	"go.uber.org/fx"
 
 	"common/config"
 )
 
 // NewJWTService 创建JWT实例
-func NewJWTService(cfg *config.Config) *JWT {
-	return NewJWT(cfg)
+func NewJWTService(cfg *config.Config, keys *KeyManager) *JWT {
+	return NewJWT(cfg, keys)
 }
 
 // Module JWT模块
 var Module = fx.Module("jwt",
+	fx.Provide(NewKeyManager),
 	fx.Provide(NewJWTService),
+	fx.Provide(NewRefreshTokenStore),
+	fx.Provide(NewRevocationStore),
+	fx.Invoke(func(lc fx.Lifecycle, keys *KeyManager) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				keys.Start()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return keys.Stop(ctx)
+			},
+		})
+	}),
 )
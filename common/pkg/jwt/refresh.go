@@ -0,0 +1,228 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"common/config"
+	"common/databases/redis"
+)
+
+// 刷新令牌相关错误
+var (
+	// ErrRefreshTokenInvalid 刷新令牌不存在或已过期
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused 刷新令牌被重复使用，整个令牌族已被吊销
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrRefreshTokenRevoked 刷新令牌所属的令牌族已被吊销
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+)
+
+const (
+	// DefaultRefreshExpiredTime 默认刷新令牌过期时间(分钟)，即7天
+	DefaultRefreshExpiredTime = 7 * 24 * 60
+
+	refreshTokenKeyPrefix  = "jwt:refresh:token:"  // 刷新令牌记录
+	refreshUsedKeyPrefix   = "jwt:refresh:used:"   // 刷新令牌已使用标记
+	refreshFamilyKeyPrefix = "jwt:refresh:family:" // 令牌族存活标记，同时保存登录会话信息
+	refreshUserKeyPrefix   = "jwt:refresh:user:"   // 用户名下的令牌族集合
+)
+
+// TokenPair 访问令牌与刷新令牌对
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64 // 访问令牌有效期(秒)
+	RefreshExpiresIn int64 // 刷新令牌有效期(秒)
+	SessionID        string
+}
+
+// RefreshRecord 刷新令牌在服务端保存的状态
+// 同一次登录后通过轮换产生的所有刷新令牌属于同一个令牌族(FamilyID)
+type RefreshRecord struct {
+	FamilyID string `json:"family_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TenantID string `json:"tenant_id,omitempty"` // 登录的租户，轮换后签发的访问令牌沿用
+}
+
+// RefreshTokenStore 基于Redis的刷新令牌存储
+// 每个刷新令牌只能使用一次，重复使用会吊销整个令牌族
+type RefreshTokenStore struct {
+	redisClient *redis.RedisClient
+	ttl         time.Duration
+	maxSessions int
+}
+
+// NewRefreshTokenStore 创建刷新令牌存储
+func NewRefreshTokenStore(redisClient *redis.RedisClient, cfg *config.Config) *RefreshTokenStore {
+	expiredTime := cfg.Token.RefreshExpiredTime
+	if expiredTime <= 0 {
+		expiredTime = DefaultRefreshExpiredTime
+	}
+
+	return &RefreshTokenStore{
+		redisClient: redisClient,
+		ttl:         time.Duration(expiredTime) * time.Minute,
+		maxSessions: cfg.Token.MaxSessions,
+	}
+}
+
+// TTL 刷新令牌有效期
+func (s *RefreshTokenStore) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue 为一次新的登录创建令牌族，并签发该族的第一个刷新令牌
+// 令牌族即登录会话，meta 为会话的设备信息
+func (s *RefreshTokenStore) Issue(ctx context.Context, userID, username string, meta SessionMeta) (string, *RefreshRecord, error) {
+	record := &RefreshRecord{
+		FamilyID: uuid.NewString(),
+		UserID:   userID,
+		Username: username,
+		TenantID: meta.TenantID,
+	}
+
+	now := time.Now().UnixMilli()
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, refreshFamilyKeyPrefix+record.FamilyID,
+		sessionFieldUserID, userID,
+		sessionFieldDevice, meta.Device,
+		sessionFieldUserAgent, meta.UserAgent,
+		sessionFieldClientIP, meta.ClientIP,
+		sessionFieldCreatedAt, now,
+		sessionFieldLastSeenAt, now,
+	)
+	pipe.Expire(ctx, refreshFamilyKeyPrefix+record.FamilyID, s.ttl)
+	pipe.SAdd(ctx, refreshUserKeyPrefix+userID, record.FamilyID)
+	pipe.Expire(ctx, refreshUserKeyPrefix+userID, s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.store(ctx, record)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, record, nil
+}
+
+// Rotate 使用刷新令牌换取同一令牌族中的新刷新令牌
+// 旧令牌被标记为已使用；若该令牌此前已被使用过，则判定为令牌泄露并吊销整个令牌族
+// @param refreshToken 客户端提交的刷新令牌
+// @return string 新的刷新令牌
+// @return *RefreshRecord 令牌对应的用户信息
+// @return error ErrRefreshTokenInvalid / ErrRefreshTokenReused / ErrRefreshTokenRevoked 或 Redis 异常
+func (s *RefreshTokenStore) Rotate(ctx context.Context, refreshToken string) (string, *RefreshRecord, error) {
+	hash := hashRefreshToken(refreshToken)
+
+	// 1. 读取令牌记录
+	data, err := s.redisClient.Get(ctx, refreshTokenKeyPrefix+hash).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return "", nil, ErrRefreshTokenInvalid
+		}
+		return "", nil, err
+	}
+
+	var record RefreshRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return "", nil, ErrRefreshTokenInvalid
+	}
+
+	// 2. 令牌族必须仍然有效
+	alive, err := s.redisClient.Exists(ctx, refreshFamilyKeyPrefix+record.FamilyID).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	if alive == 0 {
+		return "", nil, ErrRefreshTokenRevoked
+	}
+
+	// 3. 原子地标记为已使用，标记失败说明该令牌已被使用过
+	first, err := s.redisClient.SetNX(ctx, refreshUsedKeyPrefix+hash, 1, s.ttl).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	if !first {
+		if err := s.RevokeFamily(ctx, record.FamilyID); err != nil {
+			return "", nil, err
+		}
+		return "", &record, ErrRefreshTokenReused
+	}
+
+	// 4. 延长令牌族有效期并签发新令牌
+	if err := s.redisClient.Expire(ctx, refreshFamilyKeyPrefix+record.FamilyID, s.ttl).Err(); err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.store(ctx, &record)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &record, nil
+}
+
+// RevokeFamily 吊销整个令牌族，该族中的所有刷新令牌立即失效
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.redisClient.Del(ctx, refreshFamilyKeyPrefix+familyID).Err()
+}
+
+// RevokeUser 吊销用户名下的全部令牌族
+func (s *RefreshTokenStore) RevokeUser(ctx context.Context, userID string) error {
+	familyIDs, err := s.redisClient.SMembers(ctx, refreshUserKeyPrefix+userID).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(familyIDs)+1)
+	for _, familyID := range familyIDs {
+		keys = append(keys, refreshFamilyKeyPrefix+familyID)
+	}
+	keys = append(keys, refreshUserKeyPrefix+userID)
+
+	return s.redisClient.Del(ctx, keys...).Err()
+}
+
+// store 生成新的刷新令牌并保存其记录
+func (s *RefreshTokenStore) store(ctx context.Context, record *RefreshRecord) (string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	// 只保存令牌的哈希值，避免Redis数据泄露后令牌被直接使用
+	if err := s.redisClient.Set(ctx, refreshTokenKeyPrefix+hashRefreshToken(token), data, s.ttl).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// newRefreshToken 生成随机的不透明刷新令牌
+func newRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken 计算刷新令牌的哈希值，用作Redis键
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,212 @@
+package jwt
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/databases/redis"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.RedisClient) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, &redis.RedisClient{Client: client}
+}
+
+func newTestRefreshStore(t *testing.T) (*miniredis.Miniredis, *RefreshTokenStore) {
+	mr, client := newTestRedis(t)
+	cfg := &config.Config{}
+	cfg.Token.RefreshExpiredTime = 60
+	return mr, NewRefreshTokenStore(client, cfg)
+}
+
+func TestRefreshTokenStore_RotateIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRefreshStore(t)
+
+	first, issued, err := store.Issue(ctx, "u1", "alice", SessionMeta{Device: "web", TenantID: "acme"})
+	require.NoError(t, err)
+
+	second, record, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, issued.FamilyID, record.FamilyID)
+	assert.Equal(t, "u1", record.UserID)
+	assert.Equal(t, "alice", record.Username)
+	assert.Equal(t, "acme", record.TenantID)
+
+	// 新令牌可以继续轮换
+	third, _, err := store.Rotate(ctx, second)
+	require.NoError(t, err)
+	assert.NotEmpty(t, third)
+}
+
+func TestRefreshTokenStore_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRefreshStore(t)
+
+	first, issued, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	other, _, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+
+	second, _, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+
+	// 重复使用已轮换的令牌
+	_, record, err := store.Rotate(ctx, first)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	require.NotNil(t, record)
+	assert.Equal(t, issued.FamilyID, record.FamilyID)
+
+	// 同一令牌族中轮换得到的令牌一并失效
+	_, _, err = store.Rotate(ctx, second)
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+
+	// 同一用户的其他登录会话不受影响
+	_, _, err = store.Rotate(ctx, other)
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokenStore_ExpiredFamily(t *testing.T) {
+	ctx := context.Background()
+	mr, store := newTestRefreshStore(t)
+
+	token, _, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+
+	// 轮换会延长令牌族有效期
+	mr.FastForward(store.TTL() / 2)
+	token, _, err = store.Rotate(ctx, token)
+	require.NoError(t, err)
+	mr.FastForward(store.TTL() / 2)
+	token, _, err = store.Rotate(ctx, token)
+	require.NoError(t, err)
+
+	// 超过有效期未使用，令牌与令牌族均已过期
+	mr.FastForward(store.TTL() + time.Second)
+	_, _, err = store.Rotate(ctx, token)
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+}
+
+func TestRefreshTokenStore_RevokedFamily(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRefreshStore(t)
+
+	token, record, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeFamily(ctx, record.FamilyID))
+
+	_, _, err = store.Rotate(ctx, token)
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+
+	_, _, err = store.Rotate(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+}
+
+func TestRefreshTokenStore_RevokeUser(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRefreshStore(t)
+
+	first, _, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	second, _, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	another, _, err := store.Issue(ctx, "u2", "bob", SessionMeta{})
+	require.NoError(t, err)
+
+	require.NoError(t, store.RevokeUser(ctx, "u1"))
+
+	for _, token := range []string{first, second} {
+		_, _, err = store.Rotate(ctx, token)
+		assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+	}
+	_, _, err = store.Rotate(ctx, another)
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokenStore_EnforceSessionLimit(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	cfg := &config.Config{}
+	cfg.Token.RefreshExpiredTime = 60
+	cfg.Token.MaxSessions = 2
+	store := NewRefreshTokenStore(client, cfg)
+
+	// 每次登录后按上限淘汰，创建时间依次递增，避免同一毫秒内登录导致顺序不确定
+	base := time.Now().Add(-time.Hour)
+	devices := []string{"web", "ios", "android"}
+	tokens := make([]string, len(devices))
+	families := make([]string, len(devices))
+	for i, device := range devices {
+		token, record, err := store.Issue(ctx, "u1", "alice", SessionMeta{Device: device})
+		require.NoError(t, err)
+		mr.HSet(refreshFamilyKeyPrefix+record.FamilyID, sessionFieldCreatedAt,
+			strconv.FormatInt(base.Add(time.Duration(i)*time.Minute).UnixMilli(), 10))
+		tokens[i], families[i] = token, record.FamilyID
+
+		evicted, err := store.EnforceSessionLimit(ctx, "u1")
+		require.NoError(t, err)
+		if i < cfg.Token.MaxSessions {
+			assert.Empty(t, evicted)
+			continue
+		}
+		require.Len(t, evicted, 1)
+		assert.Equal(t, families[0], evicted[0].ID)
+		assert.Equal(t, "web", evicted[0].Device)
+	}
+
+	// 被淘汰的会话整个令牌族失效
+	_, _, err := store.Rotate(ctx, tokens[0])
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+
+	sessions, err := store.ListSessions(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, families[2], sessions[0].ID)
+	assert.Equal(t, families[1], sessions[1].ID)
+
+	for _, token := range tokens[1:] {
+		_, _, err = store.Rotate(ctx, token)
+		assert.NoError(t, err)
+	}
+}
+
+func TestRefreshTokenStore_EnforceSessionLimitRevokesRotatedTokens(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	cfg := &config.Config{}
+	cfg.Token.RefreshExpiredTime = 60
+	cfg.Token.MaxSessions = 1
+	store := NewRefreshTokenStore(client, cfg)
+
+	oldest, record, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	mr.HSet(refreshFamilyKeyPrefix+record.FamilyID, sessionFieldCreatedAt,
+		strconv.FormatInt(time.Now().Add(-time.Hour).UnixMilli(), 10))
+
+	// 被淘汰前已轮换过的令牌同属一个令牌族，一并失效
+	rotated, _, err := store.Rotate(ctx, oldest)
+	require.NoError(t, err)
+
+	newest, _, err := store.Issue(ctx, "u1", "alice", SessionMeta{})
+	require.NoError(t, err)
+	evicted, err := store.EnforceSessionLimit(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	assert.Equal(t, record.FamilyID, evicted[0].ID)
+
+	_, _, err = store.Rotate(ctx, rotated)
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+	_, _, err = store.Rotate(ctx, newest)
+	assert.NoError(t, err)
+}
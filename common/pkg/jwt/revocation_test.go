@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+)
+
+func newTestRevocationStore(t *testing.T) (*miniredis.Miniredis, *RevocationStore) {
+	mr, client := newTestRedis(t)
+	cfg := &config.Config{}
+	cfg.Token.ExpiredTime = 30
+	cfg.System.SecretKey = "test-secret"
+	return mr, NewRevocationStore(client, NewJWT(cfg, nil))
+}
+
+func testClaims(jti, userID string, issuedAt time.Time) *CustomClaims {
+	return &CustomClaims{
+		UserID:        userID,
+		IssuedAtMilli: issuedAt.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       jti,
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func TestRevocationStore_Revoke(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRevocationStore(t)
+
+	require.NoError(t, store.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)))
+
+	revoked, err := store.IsRevoked(ctx, testClaims("jti-1", "u1", time.Now()))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, testClaims("jti-2", "u1", time.Now()))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestRevocationStore_RevokeUser(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRevocationStore(t)
+
+	before := time.Now()
+	require.NoError(t, store.RevokeUser(ctx, "u1"))
+	time.Sleep(2 * time.Millisecond)
+	after := time.Now()
+
+	legacy := testClaims("jti", "u1", before)
+	legacy.IssuedAtMilli = 0
+
+	tests := []struct {
+		name   string
+		claims *CustomClaims
+		want   bool
+	}{
+		{name: "issued before revocation", claims: testClaims("jti", "u1", before.Add(-time.Minute)), want: true},
+		{name: "issued just before revocation", claims: testClaims("jti", "u1", before.Add(-time.Millisecond)), want: true},
+		{name: "issued after revocation", claims: testClaims("jti", "u1", after), want: false},
+		{name: "token without iat_ms in the same second", claims: legacy, want: true},
+		{name: "other user", claims: testClaims("jti", "u2", before.Add(-time.Minute)), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := store.IsRevoked(ctx, tt.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+}
+
+// TestRevocationStore_ReloginInSameSecond 修改密码吊销全部令牌后立即重新登录，新令牌与吊销在同一秒内签发也应有效
+func TestRevocationStore_ReloginInSameSecond(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestRevocationStore(t)
+
+	// 等到一秒的开头，吊销和重新登录落在同一秒内
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	oldToken, err := store.jwtService.Generate("u1", "alice")
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeUser(ctx, "u1"))
+	time.Sleep(2 * time.Millisecond)
+	newToken, err := store.jwtService.Generate("u1", "alice")
+	require.NoError(t, err)
+
+	oldClaims, err := store.jwtService.ParseToken(oldToken)
+	require.NoError(t, err)
+	newClaims, err := store.jwtService.ParseToken(newToken)
+	require.NoError(t, err)
+	require.Equal(t, oldClaims.IssuedAt.Unix(), newClaims.IssuedAt.Unix())
+
+	revoked, err := store.IsRevoked(ctx, oldClaims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, newClaims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+// TestRevocationStore_LegacyRecord 升级前以秒记录的吊销时间点仍然有效
+func TestRevocationStore_LegacyRecord(t *testing.T) {
+	ctx := context.Background()
+	mr, store := newTestRevocationStore(t)
+
+	revokedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, mr.Set(revokedUserKeyPrefix+"u1", strconv.FormatInt(revokedAt.Unix(), 10)))
+
+	revoked, err := store.IsRevoked(ctx, testClaims("jti", "u1", revokedAt.Add(500*time.Millisecond)))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, testClaims("jti", "u1", revokedAt.Add(time.Second)))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// ErrSessionNotFound 会话不存在、已过期或不属于指定用户
+var ErrSessionNotFound = errors.New("session not found")
+
+// 会话信息在令牌族哈希中的字段，时间均为毫秒级时间戳
+const (
+	sessionFieldUserID     = "user_id"
+	sessionFieldDevice     = "device"
+	sessionFieldUserAgent  = "user_agent"
+	sessionFieldClientIP   = "client_ip"
+	sessionFieldCreatedAt  = "created_at"
+	sessionFieldLastSeenAt = "last_seen_at"
+)
+
+// sessionTouchInterval 最近活跃时间的最小更新间隔，避免每个请求都写Redis
+const sessionTouchInterval = time.Minute
+
+// touchSessionScript 会话存在时更新最近活跃时间和客户端IP
+// 返回0表示会话已不存在；使用脚本保证不会在会话被吊销后重新创建键
+var touchSessionScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local last = tonumber(redis.call('HGET', KEYS[1], 'last_seen_at') or '0')
+if tonumber(ARGV[1]) - last >= tonumber(ARGV[2]) then
+	redis.call('HSET', KEYS[1], 'last_seen_at', ARGV[1])
+	if ARGV[3] ~= '' then
+		redis.call('HSET', KEYS[1], 'client_ip', ARGV[3])
+	end
+end
+return 1
+`)
+
+// SessionMeta 登录时采集的设备信息
+type SessionMeta struct {
+	Device    string // 客户端上报的设备名称
+	UserAgent string
+	ClientIP  string
+	TenantID  string // 登录的租户，会话内签发的访问令牌均绑定该租户
+}
+
+// Session 登录会话，与刷新令牌族一一对应
+type Session struct {
+	ID         string
+	UserID     string
+	Device     string
+	UserAgent  string
+	ClientIP   string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// ListSessions 列出用户的全部有效会话，按创建时间倒序
+// 已过期或已吊销的会话会从用户的会话集合中清理
+func (s *RefreshTokenStore) ListSessions(ctx context.Context, userID string) ([]*Session, error) {
+	userKey := refreshUserKeyPrefix + userID
+	sessionIDs, err := s.redisClient.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessionIDs) == 0 {
+		return []*Session{}, nil
+	}
+
+	pipe := s.redisClient.Pipeline()
+	cmds := make([]*goredis.StringStringMapCmd, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		cmds[i] = pipe.HGetAll(ctx, refreshFamilyKeyPrefix+sessionID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(sessionIDs))
+	var stale []interface{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			stale = append(stale, sessionIDs[i])
+			continue
+		}
+		sessions = append(sessions, newSession(sessionIDs[i], fields))
+	}
+	if len(stale) > 0 {
+		if err := s.redisClient.SRem(ctx, userKey, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// TouchSession 更新会话最近活跃时间，clientIP 为空时不更新IP
+// @return bool 会话是否仍然有效
+func (s *RefreshTokenStore) TouchSession(ctx context.Context, sessionID, clientIP string) (bool, error) {
+	alive, err := touchSessionScript.Run(ctx, s.redisClient,
+		[]string{refreshFamilyKeyPrefix + sessionID},
+		time.Now().UnixMilli(), sessionTouchInterval.Milliseconds(), clientIP,
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return alive == 1, nil
+}
+
+// RevokeSession 吊销用户的指定会话，会话不属于该用户时返回 ErrSessionNotFound
+func (s *RefreshTokenStore) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	owner, err := s.redisClient.HGet(ctx, refreshFamilyKeyPrefix+sessionID, sessionFieldUserID).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if owner != userID {
+		return ErrSessionNotFound
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, refreshFamilyKeyPrefix+sessionID)
+	pipe.SRem(ctx, refreshUserKeyPrefix+userID, sessionID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// EnforceSessionLimit 用户会话数超过上限时吊销最早创建的会话
+// 未配置上限时不做处理
+// @return []*Session 被吊销的会话
+func (s *RefreshTokenStore) EnforceSessionLimit(ctx context.Context, userID string) ([]*Session, error) {
+	if s.maxSessions <= 0 {
+		return nil, nil
+	}
+
+	sessions, err := s.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) <= s.maxSessions {
+		return nil, nil
+	}
+
+	// 会话按创建时间倒序排列，超出上限的部分即为最早的会话
+	evicted := sessions[s.maxSessions:]
+	for _, session := range evicted {
+		if err := s.RevokeSession(ctx, userID, session.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+	}
+	return evicted, nil
+}
+
+// newSession 从令牌族哈希字段构造会话
+func newSession(sessionID string, fields map[string]string) *Session {
+	return &Session{
+		ID:         sessionID,
+		UserID:     fields[sessionFieldUserID],
+		Device:     fields[sessionFieldDevice],
+		UserAgent:  fields[sessionFieldUserAgent],
+		ClientIP:   fields[sessionFieldClientIP],
+		CreatedAt:  parseUnixMilli(fields[sessionFieldCreatedAt]),
+		LastSeenAt: parseUnixMilli(fields[sessionFieldLastSeenAt]),
+	}
+}
+
+// parseUnixMilli 解析毫秒级时间戳，格式错误时返回零值
+func parseUnixMilli(value string) time.Time {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
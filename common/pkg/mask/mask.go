@@ -0,0 +1,19 @@
+// Package mask 对手机号等敏感数据脱敏，用于向无权查看原文的调用方展示数据
+package mask
+
+import "strings"
+
+// Middle 保留前 prefix 个和后 suffix 个字符，其余字符替换为*
+// 字符数不超过 prefix+suffix 时全部替换，避免短值被完整暴露
+func Middle(s string, prefix, suffix int) string {
+	runes := []rune(s)
+	if len(runes) <= prefix+suffix {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:prefix]) + strings.Repeat("*", len(runes)-prefix-suffix) + string(runes[len(runes)-suffix:])
+}
+
+// Phone 保留手机号前3位和后4位，如 138****8000
+func Phone(phoneNumber string) string {
+	return Middle(phoneNumber, 3, 4)
+}
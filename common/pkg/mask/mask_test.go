@@ -0,0 +1,19 @@
+package mask
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddle(t *testing.T) {
+	assert.Equal(t, "wx_1****6789", Middle("wx_123456789", 4, 4))
+	assert.Equal(t, "张**", Middle("张三丰", 1, 0))
+	assert.Equal(t, "****", Middle("abcd", 2, 2), "short values are fully masked")
+	assert.Equal(t, "", Middle("", 3, 4))
+}
+
+func TestPhone(t *testing.T) {
+	assert.Equal(t, "138****8000", Phone("13800138000"))
+	assert.Equal(t, "+86*******8000", Phone("+8613800138000"))
+}
@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"common/pkg/contextutil"
 )
 
 const (
@@ -44,3 +46,14 @@ func GetClientIP(c *gin.Context) string {
 	// 4. 使用gin内置方法获取IP
 	return c.ClientIP()
 }
+
+// ClientIPFromContext 获取客户端IP
+// 优先使用 ExtractClientIPMiddleware 写入 contextutil.ClientIPContextKey 的值，未挂载该中间件时回退到 GetClientIP
+func ClientIPFromContext(c *gin.Context) string {
+	if value, exists := c.Get(contextutil.ClientIPContextKey); exists {
+		if ip, ok := value.(string); ok && ip != "" {
+			return ip
+		}
+	}
+	return GetClientIP(c)
+}
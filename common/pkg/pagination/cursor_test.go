@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	cursor := Cursor{CreatedAt: time.Date(2026, 10, 16, 8, 30, 0, 123456789, time.UTC), ID: "1849372615028736"}
+
+	token := codec.Encode(cursor)
+	decoded, err := codec.Decode(token)
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestCursorCodec_RejectsInvalidTokens(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	token := codec.Encode(Cursor{CreatedAt: time.Now(), ID: "1"})
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := NewCursorCodec("secret").Encode(Cursor{CreatedAt: time.Now(), ID: "2"})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"missing signature", payload},
+		{"not base64", "!!!." + signature},
+		{"tampered payload", forgedPayload + "." + signature},
+		{"truncated signature", payload + "." + signature[:10]},
+		{"signed with other secret", NewCursorCodec("other").Encode(Cursor{CreatedAt: time.Now(), ID: "1"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Decode(tt.token)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestCursorParams_CursorMode(t *testing.T) {
+	empty, token := "", "abc"
+
+	assert.False(t, (&CursorParams{}).CursorMode())
+	assert.True(t, (&CursorParams{Cursor: &empty}).CursorMode())
+	assert.Equal(t, "", (&CursorParams{Cursor: &empty}).CursorToken())
+	assert.Equal(t, "abc", (&CursorParams{Cursor: &token}).CursorToken())
+}
+
+func TestPageParams_SetDefaults(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           PageParams
+		expectedPage     int
+		expectedPageSize int
+	}{
+		{"defaults", PageParams{}, 1, DefaultPageSize},
+		{"keeps valid values", PageParams{Page: 3, PageSize: 50}, 3, 50},
+		{"clamps page size", PageParams{Page: 1, PageSize: 1000}, 1, MaxPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.SetDefaults()
+			assert.Equal(t, tt.expectedPage, tt.params.Page)
+			assert.Equal(t, tt.expectedPageSize, tt.params.PageSize)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package pagination
+
+import (
+	"go.uber.org/fx"
+
+	"common/config"
+)
+
+// newCursorCodec 使用系统密钥创建游标编解码器
+func newCursorCodec(cfg *config.Config) *CursorCodec {
+	return NewCursorCodec(cfg.System.SecretKey)
+}
+
+// Module 分页模块
+var Module = fx.Module("pagination",
+	fx.Provide(newCursorCodec),
+)
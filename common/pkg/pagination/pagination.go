@@ -4,18 +4,32 @@ import (
 	"common/pkg/validation"
 )
 
+const (
+	DefaultPageSize = 10  // 未指定每页大小时的默认值
+	MaxPageSize     = 100 // 每页大小上限
+)
+
 type PageParams struct {
-	Page     int `form:"page" binding:"omitempty,min=1" label:"页码"`        // 页码(默认1)
-	PageSize int `form:"page_size" binding:"omitempty,min=1" label:"每页大小"` // 每页大小(默认10)
+	Page     int `form:"page" binding:"omitempty,min=1" label:"页码"`                // 页码(默认1)
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100" label:"每页大小"` // 每页大小(默认10，最大100)
 }
 
 func (p *PageParams) SetDefaults() {
 	if p.Page <= 0 {
 		p.Page = 1
 	}
-	if p.PageSize <= 0 {
-		p.PageSize = 10
+	p.PageSize = normalizePageSize(p.PageSize)
+}
+
+// normalizePageSize 未指定时使用默认值，超过上限时截断
+func normalizePageSize(pageSize int) int {
+	if pageSize <= 0 {
+		return DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		return MaxPageSize
 	}
+	return pageSize
 }
 
 var _ validation.Defaultable = (*PageParams)(nil)
@@ -0,0 +1,45 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultFilePath file 驱动默认输出文件
+const DefaultFilePath = "./logs/sms.log"
+
+// FileSender 将短信追加写入文件，便于开发和测试时查看验证码
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSender 创建文件短信发送器
+func NewFileSender(path string) (*FileSender, error) {
+	if path == "" {
+		path = DefaultFilePath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return &FileSender{path: path}, nil
+}
+
+// Send 追加写入一行短信记录
+func (s *FileSender) Send(ctx context.Context, phoneNumber, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phoneNumber, content)
+	return err
+}
@@ -0,0 +1,25 @@
+package sms
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LogSender 将短信内容输出到日志，仅用于开发环境
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender 创建日志短信发送器
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send 输出短信内容
+func (s *LogSender) Send(ctx context.Context, phoneNumber, content string) error {
+	s.logger.Info("SMS sent (log driver)",
+		zap.String("phone_number", phoneNumber),
+		zap.String("content", content))
+	return nil
+}
@@ -0,0 +1,10 @@
+package sms
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module 短信发送模块
+var Module = fx.Module("sms",
+	fx.Provide(NewSender),
+)
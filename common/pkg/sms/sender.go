@@ -0,0 +1,35 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"common/config"
+)
+
+// 发送驱动
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// SMSSender 短信发送接口
+// 接入真实短信服务商时实现该接口并在 NewSender 中注册驱动即可
+type SMSSender interface {
+	// Send 向指定手机号发送短信内容
+	Send(ctx context.Context, phoneNumber, content string) error
+}
+
+// NewSender 根据配置创建短信发送器，未配置驱动时默认使用 log
+func NewSender(cfg *config.Config, logger *zap.Logger) (SMSSender, error) {
+	switch cfg.SMS.Driver {
+	case "", DriverLog:
+		return NewLogSender(logger), nil
+	case DriverFile:
+		return NewFileSender(cfg.SMS.FilePath)
+	default:
+		return nil, fmt.Errorf("unsupported sms driver: %s", cfg.SMS.Driver)
+	}
+}
@@ -0,0 +1,159 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 表格文件格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// utf8BOM Excel另存为CSV时会在文件开头写入BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// FormatFromPath 按扩展名判断文件格式
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported file %q, expected .csv or .xlsx", path)
+	}
+}
+
+// RowReader 逐行读取表格，读完后返回 io.EOF
+type RowReader interface {
+	Read() ([]string, error)
+	Close() error
+}
+
+// NewReader 创建表格读取器，XLSX读取第一个工作表
+func NewReader(r io.Reader, format string) (RowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatXLSX:
+		return newXLSXReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported file format %q", format)
+	}
+}
+
+type csvReader struct {
+	reader *csv.Reader
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return &csvReader{reader: reader}, nil
+}
+
+func (r *csvReader) Read() ([]string, error) {
+	record, err := r.reader.Read()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse csv file: %w", err)
+	}
+	return record, err
+}
+
+func (r *csvReader) Close() error { return nil }
+
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx file: %w", err)
+	}
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		_ = file.Close()
+		return nil, fmt.Errorf("xlsx file has no sheet")
+	}
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read xlsx sheet %q: %w", sheets[0], err)
+	}
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+func (r *xlsxReader) Read() ([]string, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Error(); err != nil {
+			return nil, fmt.Errorf("failed to read xlsx row: %w", err)
+		}
+		return nil, io.EOF
+	}
+	record, err := r.rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xlsx row: %w", err)
+	}
+	return record, nil
+}
+
+func (r *xlsxReader) Close() error {
+	if err := r.rows.Close(); err != nil {
+		_ = r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// Header 表头，按列名定位单元格，列名不区分大小写
+type Header map[string]int
+
+// NewHeader 由表头行创建 Header
+func NewHeader(record []string) Header {
+	header := make(Header, len(record))
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := header[name]; name != "" && !exists {
+			header[name] = i
+		}
+	}
+	return header
+}
+
+// Missing 返回表头中缺少的列
+func (h Header) Missing(columns ...string) []string {
+	missing := make([]string, 0)
+	for _, column := range columns {
+		if _, ok := h[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	return missing
+}
+
+// Get 读取一行中指定列的值，列不存在或该行较短时返回空字符串
+func (h Header) Get(record []string, column string) string {
+	i, ok := h[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
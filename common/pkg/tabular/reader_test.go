@@ -0,0 +1,76 @@
+package tabular
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func readAll(t *testing.T, r RowReader) [][]string {
+	t.Helper()
+	defer r.Close()
+
+	records := make([][]string, 0)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	format, err := FormatFromPath("users.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = FormatFromPath("/tmp/users.xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = FormatFromPath("users.xls")
+	assert.Error(t, err)
+}
+
+func TestNewReader_CSV(t *testing.T) {
+	data := "\xEF\xBB\xBFname,phone_number\n张三,13800138000\n李四\n"
+
+	r, err := NewReader(strings.NewReader(data), FormatCSV)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"name", "phone_number"}, {"张三", "13800138000"}, {"李四"}}, readAll(t, r))
+}
+
+func TestNewReader_XLSX(t *testing.T) {
+	file := excelize.NewFile()
+	require.NoError(t, file.SetSheetRow("Sheet1", "A1", &[]any{"name", "gender"}))
+	require.NoError(t, file.SetSheetRow("Sheet1", "A2", &[]any{"张三", 100}))
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	r, err := NewReader(&buf, FormatXLSX)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"name", "gender"}, {"张三", "100"}}, readAll(t, r))
+}
+
+func TestNewReader_InvalidXLSX(t *testing.T) {
+	_, err := NewReader(strings.NewReader("not a zip"), FormatXLSX)
+	assert.Error(t, err)
+}
+
+func TestHeader(t *testing.T) {
+	header := NewHeader([]string{" Name ", "PHONE_NUMBER", "name"})
+
+	assert.Equal(t, []string{"gender"}, header.Missing("name", "phone_number", "gender"))
+	assert.Equal(t, "张三", header.Get([]string{" 张三 ", "138"}, "name"))
+	assert.Equal(t, "", header.Get([]string{"张三"}, "phone_number"))
+	assert.Equal(t, "", header.Get([]string{"张三"}, "gender"))
+}
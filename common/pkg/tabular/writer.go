@@ -0,0 +1,38 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// formulaPrefixes 电子表格会把以这些字符开头的单元格当作公式执行
+const formulaPrefixes = "=+-@\t\r"
+
+// EscapeCell 防止CSV公式注入，以公式字符开头的单元格前加单引号，表格软件按文本显示
+// E.164格式的手机号以 + 开头，同样会被转义
+func EscapeCell(value string) string {
+	if value != "" && strings.IndexByte(formulaPrefixes, value[0]) >= 0 {
+		return "'" + value
+	}
+	return value
+}
+
+// CSVWriter 写入前对每个单元格执行 EscapeCell 的CSV写入器，导出给用户下载的CSV都应使用它
+type CSVWriter struct {
+	*csv.Writer
+}
+
+// NewCSVWriter 创建转义公式字符的CSV写入器
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{Writer: csv.NewWriter(w)}
+}
+
+// Write 转义后写入一行，不修改传入的 record
+func (w *CSVWriter) Write(record []string) error {
+	escaped := make([]string, len(record))
+	for i, value := range record {
+		escaped[i] = EscapeCell(value)
+	}
+	return w.Writer.Write(escaped)
+}
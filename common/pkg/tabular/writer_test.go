@@ -0,0 +1,43 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "张三", want: "张三"},
+		{value: "13800138000", want: "13800138000"},
+		{value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{value: "+8613800138000", want: "'+8613800138000"},
+		{value: "-1+1", want: "'-1+1"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\t=1", want: "'\t=1"},
+		{value: "\r=1", want: "'\r=1"},
+		{value: "a=1", want: "a=1"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, EscapeCell(tt.value), "value %q", tt.value)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCSVWriter(&buf)
+
+	record := []string{"1", "=1+2", "+8613800138000"}
+	require.NoError(t, writer.Write(record))
+	writer.Flush()
+	require.NoError(t, writer.Error())
+
+	assert.Equal(t, "1,'=1+2,'+8613800138000\n", buf.String())
+	assert.Equal(t, "=1+2", record[1], "record should not be modified")
+}
@@ -25,6 +25,12 @@ type Defaultable interface {
 	SetDefaults()
 }
 
+// FieldErrors 按参数名给出错误描述的验证错误，例如查询参数中的过滤条件解析失败
+type FieldErrors interface {
+	error
+	FieldErrors() map[string]string
+}
+
 // ValidationError 自定义验证错误
 type ValidationError struct {
 	Message string
@@ -74,6 +80,9 @@ func handleError(c *gin.Context, params interface{}, err error, trans ut.Transla
 	)
 
 	switch err := err.(type) {
+	case FieldErrors:
+		validationErr := response.CreateError(response.ErrorTypeValidationFailed, ErrValidationFailed)
+		response.HandleWith(c, nil, validationErr, response.WithData(err.FieldErrors()))
 	case ValidationError:
 		// 自定义验证错误
 		validationErr := response.NewValidationError(err.Message)
@@ -0,0 +1,194 @@
+package wechat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"common/config"
+	"common/pkg/httpclient"
+)
+
+const (
+	// DefaultBaseURL 微信API默认地址
+	DefaultBaseURL = "https://api.weixin.qq.com"
+	// DefaultTimeout 默认请求超时时间
+	DefaultTimeout = 5 * time.Second
+
+	// accessTokenRefreshAhead access_token 提前刷新时间，避免临界过期
+	accessTokenRefreshAhead = 5 * time.Minute
+)
+
+// APIError 微信接口返回的业务错误
+type APIError struct {
+	Code    int    `json:"errcode"`
+	Message string `json:"errmsg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("wechat api error %d: %s", e.Code, e.Message)
+}
+
+// IsInvalidCode 是否为客户端提交的code无效(不存在、已过期或已被使用)
+func (e *APIError) IsInvalidCode() bool {
+	switch e.Code {
+	case 40029, 40163, 40226:
+		return true
+	}
+	return false
+}
+
+// Session code2session 返回的会话信息
+type Session struct {
+	OpenID     string `json:"openid"`
+	UnionID    string `json:"unionid"`
+	SessionKey string `json:"session_key"`
+}
+
+// PhoneInfo 用户绑定的手机号信息
+type PhoneInfo struct {
+	PhoneNumber     string `json:"phoneNumber"`     // 带区号的手机号，境外手机号会有区号
+	PurePhoneNumber string `json:"purePhoneNumber"` // 不带区号的手机号
+	CountryCode     string `json:"countryCode"`     // 区号
+}
+
+// Client 微信小程序服务端API客户端
+type Client struct {
+	appID     string
+	appSecret string
+	baseURL   string
+	timeout   time.Duration
+
+	mu             sync.Mutex
+	accessToken    string
+	tokenExpiresAt time.Time
+}
+
+// NewClient 创建微信客户端
+func NewClient(cfg *config.Config) *Client {
+	baseURL := strings.TrimRight(cfg.WeChat.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	timeout := cfg.WeChat.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		appID:     cfg.WeChat.AppID,
+		appSecret: cfg.WeChat.AppSecret,
+		baseURL:   baseURL,
+		timeout:   timeout,
+	}
+}
+
+// Code2Session 使用 wx.login 获取的临时登录凭证换取 openid 和 session_key
+// @param code 小程序端 wx.login 返回的 code
+// @return *Session 会话信息
+// @return error 请求失败或微信返回错误(*APIError)
+func (c *Client) Code2Session(ctx context.Context, code string) (*Session, error) {
+	req := c.newRequest("/sns/jscode2session", httpclient.MethodGet)
+	req.QueryParams["appid"] = c.appID
+	req.QueryParams["secret"] = c.appSecret
+	req.QueryParams["js_code"] = code
+	req.QueryParams["grant_type"] = "authorization_code"
+
+	var result struct {
+		APIError
+		Session
+	}
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != 0 {
+		return nil, &result.APIError
+	}
+	if result.OpenID == "" {
+		return nil, &APIError{Code: -1, Message: "empty openid"}
+	}
+
+	return &result.Session, nil
+}
+
+// GetPhoneNumber 使用 getPhoneNumber 按钮返回的 code 换取用户手机号
+// @param code 小程序端手机号快速验证组件返回的 code
+// @return *PhoneInfo 手机号信息
+// @return error 请求失败或微信返回错误(*APIError)
+func (c *Client) GetPhoneNumber(ctx context.Context, code string) (*PhoneInfo, error) {
+	accessToken, err := c.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := c.newRequest("/wxa/business/getuserphonenumber", httpclient.MethodPost)
+	req.QueryParams["access_token"] = accessToken
+	req.JSONData["code"] = code
+
+	var result struct {
+		APIError
+		PhoneInfo PhoneInfo `json:"phone_info"`
+	}
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != 0 {
+		return nil, &result.APIError
+	}
+
+	return &result.PhoneInfo, nil
+}
+
+// getAccessToken 获取接口调用凭证，过期前复用缓存的凭证
+func (c *Client) getAccessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.tokenExpiresAt) {
+		return c.accessToken, nil
+	}
+
+	req := c.newRequest("/cgi-bin/token", httpclient.MethodGet)
+	req.QueryParams["grant_type"] = "client_credential"
+	req.QueryParams["appid"] = c.appID
+	req.QueryParams["secret"] = c.appSecret
+
+	var result struct {
+		APIError
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := c.do(req, &result); err != nil {
+		return "", err
+	}
+	if result.Code != 0 {
+		return "", &result.APIError
+	}
+
+	c.accessToken = result.AccessToken
+	c.tokenExpiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - accessTokenRefreshAhead)
+	return c.accessToken, nil
+}
+
+// newRequest 创建指向微信API的请求
+func (c *Client) newRequest(path string, method httpclient.RequestMethod) *httpclient.SendRequest {
+	req := httpclient.NewRequest(c.baseURL+path, method)
+	req.Timeout = c.timeout
+	return req
+}
+
+// do 发送请求并解析JSON响应
+func (c *Client) do(req *httpclient.SendRequest, out any) error {
+	_, body, err := req.Send()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode wechat response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package wechat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.WeChat.AppID = "wx-app"
+	cfg.WeChat.AppSecret = "wx-secret"
+	cfg.WeChat.BaseURL = server.URL
+	return NewClient(cfg)
+}
+
+func TestClient_Code2Session(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sns/jscode2session", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "wx-app", q.Get("appid"))
+		assert.Equal(t, "wx-secret", q.Get("secret"))
+		assert.Equal(t, "authorization_code", q.Get("grant_type"))
+
+		switch q.Get("js_code") {
+		case "good":
+			w.Write([]byte(`{"openid":"o-123","session_key":"sk","unionid":"u-1"}`))
+		default:
+			w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+		}
+	})
+	client := newTestClient(t, mux)
+
+	session, err := client.Code2Session(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, "o-123", session.OpenID)
+	assert.Equal(t, "u-1", session.UnionID)
+
+	_, err = client.Code2Session(context.Background(), "bad")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 40029, apiErr.Code)
+}
+
+func TestClient_GetPhoneNumber(t *testing.T) {
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		w.Write([]byte(`{"access_token":"at-1","expires_in":7200}`))
+	})
+	mux.HandleFunc("/wxa/business/getuserphonenumber", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "at-1", r.URL.Query().Get("access_token"))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "phone-code", body["code"])
+
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","phone_info":{"phoneNumber":"+86 13800138000","purePhoneNumber":"13800138000","countryCode":"86"}}`))
+	})
+	client := newTestClient(t, mux)
+
+	for i := 0; i < 2; i++ {
+		info, err := client.GetPhoneNumber(context.Background(), "phone-code")
+		require.NoError(t, err)
+		assert.Equal(t, "13800138000", info.PurePhoneNumber)
+		assert.Equal(t, "86", info.CountryCode)
+	}
+	assert.Equal(t, 1, tokenRequests, "access token should be cached")
+}
@@ -0,0 +1,10 @@
+package wechat
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module 微信客户端模块
+var Module = fx.Module("wechat",
+	fx.Provide(NewClient),
+)
@@ -16,10 +16,10 @@ func newCodeRegistry() CodeRegistry {
 	registry := &codeRegistry{
 		codes: make(map[int]*CodeInfo),
 	}
-	
+
 	// 初始化默认业务码
 	registry.initDefaultCodes()
-	
+
 	return registry
 }
 
@@ -103,4 +103,4 @@ func (cr *codeRegistry) RegisterCodes(codes map[int]*CodeInfo) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
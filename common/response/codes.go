@@ -18,6 +18,7 @@ const (
 	CodeBusinessError = 2000
 	CodeAlreadyExists = 2001
 	CodeConflict      = 2002
+	CodeAccountLocked = 2003
 
 	// 系统错误 (5000-5999)
 	CodeInternalError = 5000
@@ -95,6 +96,12 @@ var CodeInfoMap = map[int]*CodeInfo{
 		HTTPStatus: http.StatusConflict,
 		Category:   "business_error",
 	},
+	CodeAccountLocked: {
+		Code:       CodeAccountLocked,
+		Message:    "账号已被临时锁定",
+		HTTPStatus: http.StatusTooManyRequests,
+		Category:   "business_error",
+	},
 
 	// 系统错误
 	CodeInternalError: {
@@ -8,12 +8,12 @@ import (
 // 集成所有功能组件，提供统一的响应处理能力
 type ResponseEngine struct {
 	// 内置组件
-	codeRegistry   CodeRegistry
-	responsePool   ResponsePool
-	errorMapper    ErrorMapper
+	codeRegistry    CodeRegistry
+	responsePool    ResponsePool
+	errorMapper     ErrorMapper
 	responseHandler ResponseHandler
-	contextManager *ContextManager
-	errorFactory   ErrorFactory // 错误工厂依赖
+	contextManager  *ContextManager
+	errorFactory    ErrorFactory // 错误工厂依赖
 }
 
 // NewResponseEngine 创建新的响应引擎
@@ -26,7 +26,7 @@ func NewResponseEngineWithFactory(factory ErrorFactory) *ResponseEngine {
 	codeRegistry := newCodeRegistry()
 	responsePool := newResponsePool()
 	errorMapper := newErrorMapper()
-	
+
 	engine := &ResponseEngine{
 		codeRegistry:   codeRegistry,
 		responsePool:   responsePool,
@@ -34,15 +34,13 @@ func NewResponseEngineWithFactory(factory ErrorFactory) *ResponseEngine {
 		contextManager: NewContextManager(),
 		errorFactory:   factory,
 	}
-	
+
 	// 创建响应处理器
 	engine.responseHandler = newResponseHandler(codeRegistry, responsePool, errorMapper)
 
 	return engine
 }
 
-
-
 // === 委托给组件的方法 ===
 
 // GetCodeInfo 获取业务码信息
@@ -110,6 +108,11 @@ func (re *ResponseEngine) HandleSuccessWithPaging(c *gin.Context, data any, page
 	re.responseHandler.HandleSuccessWithPaging(c, data, page, pageSize, total)
 }
 
+// HandleSuccessWithCursor 处理游标分页成功响应
+func (re *ResponseEngine) HandleSuccessWithCursor(c *gin.Context, data any, pagination *CursorPagination) {
+	re.responseHandler.HandleSuccessWithCursor(c, data, pagination)
+}
+
 // HandleError 处理错误响应
 func (re *ResponseEngine) HandleError(c *gin.Context, err error) {
 	re.responseHandler.HandleError(c, err)
@@ -159,6 +162,15 @@ func (re *ResponseEngine) HandlePaging(c *gin.Context, data any, page, pageSize
 	re.HandleSuccessWithPaging(c, data, page, pageSize, total)
 }
 
+// HandleCursorPaging 统一游标分页处理方法
+func (re *ResponseEngine) HandleCursorPaging(c *gin.Context, data any, pagination *CursorPagination, err error) {
+	if err != nil {
+		re.HandleError(c, err)
+		return
+	}
+	re.HandleSuccessWithCursor(c, data, pagination)
+}
+
 // GetContextManager 获取上下文管理器
 func (re *ResponseEngine) GetContextManager() *ContextManager {
 	return re.contextManager
@@ -181,6 +193,11 @@ func HandlePaging(c *gin.Context, data any, page, pageSize int, total int64, err
 	GetDefaultEngine().HandlePaging(c, data, page, pageSize, total, err)
 }
 
+// HandleCursorPaging 统一游标分页处理函数
+func HandleCursorPaging(c *gin.Context, data any, pagination *CursorPagination, err error) {
+	GetDefaultEngine().HandleCursorPaging(c, data, pagination, err)
+}
+
 // === 引擎管理全局函数 ===
 
 // GetDefaultEngine 获取默认响应引擎
@@ -29,7 +29,7 @@ func (em *EngineManager) SetDefaultEngine(engine Engine) {
 	if engine == nil {
 		return
 	}
-	
+
 	em.mu.Lock()
 	defer em.mu.Unlock()
 	em.defaultEngine = engine
@@ -48,4 +48,4 @@ func SetGlobalEngineManager(manager *EngineManager) {
 	if manager != nil {
 		globalEngineManager = manager
 	}
-}
\ No newline at end of file
+}
@@ -110,4 +110,14 @@ func NewResourceLockedError(message string, cause ...error) *DomainError {
 // NewExternalServiceUnavailableError 创建外部服务不可用错误
 func NewExternalServiceUnavailableError(message string, cause ...error) *DomainError {
 	return CreateError(ErrorTypeExternalServiceUnavailable, message, cause...)
-}
\ No newline at end of file
+}
+
+// NewTooManyRequestsError 创建请求过于频繁错误
+func NewTooManyRequestsError(message string, cause ...error) *DomainError {
+	return CreateError(ErrorTypeTooManyRequests, message, cause...)
+}
+
+// NewAccountLockedError 创建账号锁定错误
+func NewAccountLockedError(message string, cause ...error) *DomainError {
+	return CreateError(ErrorTypeAccountLocked, message, cause...)
+}
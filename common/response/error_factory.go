@@ -43,12 +43,12 @@ func NewErrorFactoryWithContextManager(cm *ContextManager) ErrorFactory {
 // Create 创建领域错误
 func (f *errorFactory) Create(errorType ErrorType, message string, cause ...error) *DomainError {
 	err := f.errorPool.Get().(*DomainError)
-	
+
 	// 重置错误对象状态
 	err.Type = errorType
 	err.Message = message
 	err.Context = nil // 延迟分配，只在需要时创建
-	
+
 	// 处理 cause 参数
 	if len(cause) > 0 {
 		err.Cause = cause[0]
@@ -62,11 +62,11 @@ func (f *errorFactory) Create(errorType ErrorType, message string, cause ...erro
 // CreateWithContext 创建带有上下文的领域错误
 func (f *errorFactory) CreateWithContext(errorType ErrorType, message string, context map[string]any, cause ...error) *DomainError {
 	err := f.errorPool.Get().(*DomainError)
-	
+
 	// 重置错误对象状态
 	err.Type = errorType
 	err.Message = message
-	
+
 	// 处理 cause 参数
 	if len(cause) > 0 {
 		err.Cause = cause[0]
@@ -109,7 +109,7 @@ func (fm *FactoryManager) SetDefaultFactory(factory ErrorFactory) {
 	if factory == nil {
 		return
 	}
-	
+
 	fm.mu.Lock()
 	defer fm.mu.Unlock()
 	fm.defaultFactory = factory
@@ -16,10 +16,10 @@ func newErrorMapper() ErrorMapper {
 	mapper := &errorMapper{
 		mappings: make(map[ErrorType]*ErrorMapping),
 	}
-	
+
 	// 初始化默认错误映射
 	mapper.initDefaultMappings()
-	
+
 	return mapper
 }
 
@@ -126,8 +126,18 @@ func (em *errorMapper) initDefaultMappings() {
 			HTTPStatus:     http.StatusBadGateway,
 			DefaultMessage: "网络错误",
 		},
+		ErrorTypeTooManyRequests: {
+			BusinessCode:   CodeRateLimit,
+			HTTPStatus:     http.StatusTooManyRequests,
+			DefaultMessage: "请求过于频繁",
+		},
+		ErrorTypeAccountLocked: {
+			BusinessCode:   CodeAccountLocked,
+			HTTPStatus:     http.StatusTooManyRequests,
+			DefaultMessage: "账号已被临时锁定",
+		},
 	}
-	
+
 	for errorType, mapping := range defaultMappings {
 		em.mappings[errorType] = mapping
 	}
@@ -152,4 +162,4 @@ func (em *errorMapper) RegisterErrorMapping(errorType ErrorType, mapping *ErrorM
 	defer em.mu.Unlock()
 
 	em.mappings[errorType] = mapping
-}
\ No newline at end of file
+}
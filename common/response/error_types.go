@@ -1,7 +1,9 @@
 package response
 
 import (
+	"errors"
 	"fmt"
+	"time"
 )
 
 // ErrorType 错误类型枚举
@@ -28,8 +30,13 @@ const (
 	ErrorTypeExternalServiceUnavailable
 	ErrorTypeTimeout
 	ErrorTypeNetworkError
+	ErrorTypeTooManyRequests
+	ErrorTypeAccountLocked
 )
 
+// ContextKeyRetryAfter 错误上下文中的重试等待时间(秒)
+const ContextKeyRetryAfter = "retry_after"
+
 // ErrorMapping 错误映射结构
 type ErrorMapping struct {
 	BusinessCode   int
@@ -131,3 +138,32 @@ func (e *DomainError) WithContextMap(contextMap map[string]any) *DomainError {
 		Context: newContext,
 	}
 }
+
+// WithRetryAfter 设置建议的重试等待时间，响应时写入 Retry-After 头
+// 不足1秒按1秒计算
+func (e *DomainError) WithRetryAfter(d time.Duration) *DomainError {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return e.WithContext(ContextKeyRetryAfter, seconds)
+}
+
+// RetryAfter 获取重试等待时间(秒)，未设置时返回0
+func (e *DomainError) RetryAfter() int64 {
+	value, ok := e.GetContextValue(ContextKeyRetryAfter)
+	if !ok {
+		return 0
+	}
+	seconds, _ := value.(int64)
+	return seconds
+}
+
+// IsErrorType 判断错误链中是否包含指定类型的领域错误
+func IsErrorType(err error, errorType ErrorType) bool {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Type == errorType
+	}
+	return false
+}
@@ -42,6 +42,8 @@ type ResponseHandler interface {
 	HandleSuccess(c *gin.Context, data any)
 	// HandleSuccessWithPaging 处理分页成功响应
 	HandleSuccessWithPaging(c *gin.Context, data any, page, pageSize int, total int64)
+	// HandleSuccessWithCursor 处理游标分页成功响应
+	HandleSuccessWithCursor(c *gin.Context, data any, pagination *CursorPagination)
 	// HandleError 处理错误响应
 	HandleError(c *gin.Context, err error)
 	// HandleErrorWithCode 使用指定业务码处理错误响应
@@ -60,6 +62,8 @@ type UnifiedAPI interface {
 	HandleWith(c *gin.Context, data any, err error, options ...ErrorHandleOption)
 	// HandlePaging 统一分页处理函数
 	HandlePaging(c *gin.Context, data any, page, pageSize int, total int64, err error)
+	// HandleCursorPaging 统一游标分页处理函数
+	HandleCursorPaging(c *gin.Context, data any, pagination *CursorPagination, err error)
 }
 
 // Engine 响应引擎接口
@@ -69,7 +73,7 @@ type Engine interface {
 	ErrorMapper
 	ResponseHandler
 	UnifiedAPI
-	
+
 	// GetContextManager 获取上下文管理器
 	GetContextManager() *ContextManager
-}
\ No newline at end of file
+}
@@ -2,6 +2,7 @@ package response
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -65,6 +66,25 @@ func (rh *responseHandler) HandleSuccessWithPaging(c *gin.Context, data any, pag
 	c.JSON(http.StatusOK, resp)
 }
 
+// HandleSuccessWithCursor 处理游标分页成功响应
+func (rh *responseHandler) HandleSuccessWithCursor(c *gin.Context, data any, pagination *CursorPagination) {
+	resp := rh.responsePool.GetResponse()
+	defer rh.responsePool.PutResponse(resp)
+
+	if pagination == nil {
+		pagination = &CursorPagination{}
+	}
+
+	resp.Code = CodeSuccess
+	resp.Message = rh.codeRegistry.GetCodeMessage(CodeSuccess)
+	resp.Data = &CursorPageData{
+		Items:            data,
+		CursorPagination: pagination,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // HandleError 处理错误响应
 func (rh *responseHandler) HandleError(c *gin.Context, err error) {
 	result := rh.handleError(err, nil)
@@ -201,6 +221,7 @@ func (rh *responseHandler) handleDomainError(err *DomainError, config *ErrorHand
 			Message:    message,
 			HTTPStatus: mapping.HTTPStatus,
 			Data:       config.Data,
+			RetryAfter: err.RetryAfter(),
 		}
 	}
 
@@ -230,5 +251,8 @@ func (rh *responseHandler) sendErrorResponse(c *gin.Context, result *ErrorResult
 	resp.Message = result.Message
 	resp.Data = result.Data
 
+	if result.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.FormatInt(result.RetryAfter, 10))
+	}
 	c.JSON(result.HTTPStatus, resp)
-}
\ No newline at end of file
+}
@@ -64,4 +64,4 @@ func (rp *responsePool) PutPageData(pageData *PageData) {
 		pageData.Pagination = nil
 		rp.pageDataPool.Put(pageData)
 	}
-}
\ No newline at end of file
+}
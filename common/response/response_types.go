@@ -21,12 +21,27 @@ type PageData struct {
 	*Pagination
 }
 
+// CursorPagination 游标分页信息结构
+type CursorPagination struct {
+	PageSize   int    `json:"page_size"`             // 每页大小
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标，没有下一页时为空
+	HasMore    bool   `json:"has_more"`              // 是否还有下一页
+	Total      *int64 `json:"total,omitempty"`       // 总数量，仅在请求时返回
+}
+
+// CursorPageData 游标分页数据结构
+type CursorPageData struct {
+	Items any `json:"items"`
+	*CursorPagination
+}
+
 // ErrorResult 错误处理结果
 type ErrorResult struct {
 	Code       int
 	Message    string
 	HTTPStatus int
 	Data       any
+	RetryAfter int64 // 建议客户端重试等待时间(秒)，大于0时写入 Retry-After 响应头
 }
 
 // === 错误处理选项类型 ===
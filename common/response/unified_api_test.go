@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -215,6 +216,62 @@ func TestUnifiedAPI_HandlePaging(t *testing.T) {
 	}
 }
 
+func TestUnifiedAPI_HandleCursorPaging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	total := int64(42)
+	tests := []struct {
+		name           string
+		pagination     *CursorPagination
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "has more with total",
+			pagination:     &CursorPagination{PageSize: 2, NextCursor: "abc", HasMore: true, Total: &total},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":["a","b"],"page_size":2,"next_cursor":"abc","has_more":true,"total":42}`,
+		},
+		{
+			name:           "last page without total",
+			pagination:     &CursorPagination{PageSize: 2},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":["a","b"],"page_size":2,"has_more":false}`,
+		},
+		{
+			name:           "error",
+			err:            NewInternalServerError("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				HandleCursorPaging(c, []string{"a", "b"}, tt.pagination, tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response struct {
+				Code int             `json:"code"`
+				Data json.RawMessage `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.err == nil {
+				assert.Equal(t, CodeSuccess, response.Code)
+				assert.JSONEq(t, tt.expectedBody, string(response.Data))
+			}
+		})
+	}
+}
+
 func TestErrorHandleOptions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -337,6 +394,61 @@ func TestDomainError_Unwrap(t *testing.T) {
 	assert.Nil(t, unwrappedNil)
 }
 
+func TestUnifiedAPI_RetryAfterHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   int
+		expectedHeader string
+	}{
+		{
+			name:           "rate limited",
+			err:            NewTooManyRequestsError("too many requests").WithRetryAfter(1500 * time.Millisecond),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeRateLimit,
+			expectedHeader: "2",
+		},
+		{
+			name:           "account locked",
+			err:            NewAccountLockedError("locked").WithRetryAfter(15 * time.Minute),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeAccountLocked,
+			expectedHeader: "900",
+		},
+		{
+			name:           "without retry after",
+			err:            NewTooManyRequestsError("too many requests"),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeRateLimit,
+			expectedHeader: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				Handle(c, nil, tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedHeader, w.Header().Get("Retry-After"))
+
+			var response Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, response.Code)
+		})
+	}
+}
+
 func TestGlobalErrorCreators(t *testing.T) {
 	// Test global error creation functions
 	notFoundErr := NewNotFoundError("global not found")
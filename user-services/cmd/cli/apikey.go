@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"user-services/internal/application/service"
+	"user-services/internal/domain/apikey/entity"
+)
+
+// newAPIKeyCommand API Key管理命令
+func newAPIKeyCommand(apiKeyService service.APIKeyServiceInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "管理服务间调用使用的API Key",
+	}
+
+	cmd.AddCommand(
+		newAPIKeyCreateCommand(apiKeyService),
+		newAPIKeyListCommand(apiKeyService),
+		newAPIKeyRevokeCommand(apiKeyService),
+	)
+	return cmd
+}
+
+func newAPIKeyCreateCommand(apiKeyService service.APIKeyServiceInterface) *cobra.Command {
+	var input service.CreateAPIKeyInput
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "创建API Key，明文密钥只显示一次",
+		Example: `  services-cli apikey create --name billing-job --owner svc:billing --scope users:read --ttl 2160h
+  services-cli apikey create --name partner-a --owner partner:a --scope "*"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			created, err := apiKeyService.Create(context.Background(), input)
+			if err != nil {
+				return err
+			}
+
+			key := created.Key
+			fmt.Printf("ID:      %s\n", key.ID())
+			fmt.Printf("Prefix:  %s\n", key.Prefix())
+			fmt.Printf("Owner:   %s\n", key.OwnerID())
+			fmt.Printf("Scopes:  %s\n", formatScopes(key.Scopes()))
+			fmt.Printf("Expires: %s\n", formatOptionalTime(key.ExpiresAt(), "never"))
+			fmt.Printf("\nAPI Key: %s\n", created.PlainKey)
+			fmt.Println("请立即妥善保存，该密钥不会再次显示。调用时通过 X-API-Key 请求头携带。")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&input.Name, "name", "", "名称，说明用途(必填)")
+	cmd.Flags().StringVar(&input.OwnerID, "owner", "", "所有者，作为调用方身份参与权限校验(必填)")
+	cmd.Flags().StringSliceVar(&input.Scopes, "scope", nil, `授权范围，可重复指定，"*" 表示全部`)
+	cmd.Flags().DurationVar(&input.TTL, "ttl", 0, "有效期，如 720h，0表示永不过期")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("owner")
+	return cmd
+}
+
+func newAPIKeyListCommand(apiKeyService service.APIKeyServiceInterface) *cobra.Command {
+	var ownerID string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "列出API Key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keys, err := apiKeyService.List(context.Background(), ownerID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tPREFIX\tNAME\tOWNER\tSCOPES\tSTATUS\tEXPIRES\tLAST USED\tCREATED")
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					key.ID(),
+					key.Prefix(),
+					key.Name(),
+					key.OwnerID(),
+					formatScopes(key.Scopes()),
+					apiKeyStatus(key),
+					formatOptionalTime(key.ExpiresAt(), "never"),
+					formatOptionalTime(key.LastUsedAt(), "-"),
+					key.CreatedAt().Format(time.RFC3339),
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&ownerID, "owner", "", "只列出指定所有者的API Key")
+	return cmd
+}
+
+func newAPIKeyRevokeCommand(apiKeyService service.APIKeyServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <id|prefix>",
+		Short: "吊销API Key，吊销后立即失效",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := apiKeyService.Revoke(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("API Key %s (%s) revoked at %s\n", key.Prefix(), key.Name(), key.RevokedAt().Format(time.RFC3339))
+			return nil
+		},
+	}
+}
+
+// apiKeyStatus API Key当前状态
+func apiKeyStatus(key *entity.APIKey) string {
+	switch {
+	case key.IsRevoked():
+		return "revoked"
+	case key.IsExpired(time.Now()):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+func formatScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "-"
+	}
+	return strings.Join(scopes, ",")
+}
+
+func formatOptionalTime(t *time.Time, empty string) string {
+	if t == nil {
+		return empty
+	}
+	return t.Format(time.RFC3339)
+}
@@ -9,7 +9,13 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"common/config"
 	commonDI "common/di"
+	"user-services/internal/application/service"
+	"user-services/internal/domain/apikey"
+	"user-services/internal/domain/audit"
+	"user-services/internal/domain/user"
+	userservice "user-services/internal/domain/user/service"
 	"user-services/internal/infrastructure/persistence/ent"
 	"user-services/internal/infrastructure/persistence/ent/gen"
 	"user-services/internal/infrastructure/persistence/ent/gen/migrate"
@@ -22,8 +28,19 @@ func main() {
 			commonDI.ConfigModule,
 			commonDI.LoggerModule,
 			commonDI.DatabasesModule,
+			commonDI.CasbinModule,
 
 			ent.Module,
+			apikey.DomainModule,
+			audit.DomainModule,
+			user.DomainModule,
+		),
+
+		fx.Provide(
+			service.NewAPIKeyService,
+			service.NewAuditService,
+			service.NewPermissionService,
+			service.NewUserImporter,
 		),
 
 		// CLI入口点
@@ -43,7 +60,15 @@ func main() {
 }
 
 // runCLI 运行CLI命令
-func runCLI(logger *zap.Logger, client *gen.Client) error {
+func runCLI(
+	logger *zap.Logger,
+	cfg *config.Config,
+	client *gen.Client,
+	apiKeyService service.APIKeyServiceInterface,
+	permissionService service.PermissionServiceInterface,
+	userService *userservice.UserDomainService,
+	userImporter service.UserImporterInterface,
+) error {
 	// 创建根命令
 	rootCmd := &cobra.Command{
 		Use:   "services-cli",
@@ -71,6 +96,15 @@ func runCLI(logger *zap.Logger, client *gen.Client) error {
 		},
 	})
 
+	// 添加API Key管理命令
+	rootCmd.AddCommand(newAPIKeyCommand(apiKeyService))
+
+	// 添加权限策略管理命令
+	rootCmd.AddCommand(newPolicyCommand(permissionService))
+
+	// 添加用户数据维护命令
+	rootCmd.AddCommand(newUserCommand(userService, userImporter, cfg))
+
 	// 执行命令
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error("CLI command execution failed", zap.Error(err))
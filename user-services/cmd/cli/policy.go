@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	pkgcasbin "common/pkg/casbin"
+	"user-services/internal/application/service"
+)
+
+// newPolicyCommand 权限策略管理命令
+func newPolicyCommand(permissionService service.PermissionServiceInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "以策略文件管理Casbin策略和角色分配",
+	}
+
+	cmd.AddCommand(
+		newPolicyApplyCommand(permissionService),
+		newPolicyExportCommand(permissionService),
+	)
+	return cmd
+}
+
+func newPolicyApplyCommand(permissionService service.PermissionServiceInterface) *cobra.Command {
+	var (
+		path   string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "以策略文件为准同步全部租户的策略和角色分配，文件中没有的规则会被删除",
+		Example: `  services-cli policy apply -f deploy/policies.yaml --dry-run
+  services-cli policy apply -f deploy/policies.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := pkgcasbin.FormatFromPath(path)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			file, err := pkgcasbin.ReadPolicyFile(f, format)
+			if err != nil {
+				return err
+			}
+			diff, err := permissionService.ApplyPolicies(context.Background(), file, dryRun)
+			if err != nil {
+				return err
+			}
+
+			printPolicyDiff(os.Stdout, diff)
+			switch {
+			case diff.Empty():
+				fmt.Println("策略已是最新，无需变更")
+			case dryRun:
+				fmt.Println("dry-run: 以上变更未执行")
+			default:
+				fmt.Println("策略已同步，其他实例将通过策略变更通知自动更新")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", "", "策略文件，支持 .yaml/.yml/.csv (必填)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只输出差异，不修改策略")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func newPolicyExportCommand(permissionService service.PermissionServiceInterface) *cobra.Command {
+	var (
+		output string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "导出当前全部租户的策略和角色分配",
+		Example: `  services-cli policy export > deploy/policies.yaml
+  services-cli policy export -o deploy/policies.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = pkgcasbin.FormatYAML
+				if output != "" {
+					detected, err := pkgcasbin.FormatFromPath(output)
+					if err != nil {
+						return err
+					}
+					format = detected
+				}
+			}
+
+			file, err := permissionService.ExportPolicies(context.Background())
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				return pkgcasbin.WritePolicyFile(os.Stdout, file, format)
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			if err := pkgcasbin.WritePolicyFile(f, file, format); err != nil {
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d policies and %d role assignments to %s\n", len(file.Policies), len(file.Roles), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "输出文件，默认输出到标准输出")
+	cmd.Flags().StringVar(&format, "format", "", "输出格式 yaml|csv，默认按输出文件扩展名判断，标准输出时为yaml")
+	return cmd
+}
+
+// printPolicyDiff 以 +/- 前缀逐行输出差异
+func printPolicyDiff(w io.Writer, diff *service.PolicyDiff) {
+	for _, rule := range diff.RemovedPolicies {
+		fmt.Fprintf(w, "- %s\n", rule)
+	}
+	for _, rule := range diff.RemovedRoles {
+		fmt.Fprintf(w, "- %s\n", rule)
+	}
+	for _, rule := range diff.AddedPolicies {
+		fmt.Fprintf(w, "+ %s\n", rule)
+	}
+	for _, rule := range diff.AddedRoles {
+		fmt.Fprintf(w, "+ %s\n", rule)
+	}
+	if !diff.Empty() {
+		fmt.Fprintf(w, "\npolicies: +%d -%d, roles: +%d -%d\n",
+			len(diff.AddedPolicies), len(diff.RemovedPolicies), len(diff.AddedRoles), len(diff.RemovedRoles))
+	}
+}
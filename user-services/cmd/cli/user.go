@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"common/config"
+	"common/pkg/tabular"
+	appservice "user-services/internal/application/service"
+	"user-services/internal/domain/user/service"
+)
+
+// maxPrintedImportErrors 未指定 --report 时最多打印的失败行数
+const maxPrintedImportErrors = 20
+
+// defaultPurgeRetention 未配置 user.purge_retention 时软删除用户的保留时长
+const defaultPurgeRetention = 30 * 24 * time.Hour
+
+// newUserCommand 用户管理命令
+func newUserCommand(userService *service.UserDomainService, importer appservice.UserImporterInterface, cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "user",
+		Aliases: []string{"users"},
+		Short:   "用户数据维护",
+	}
+
+	cmd.AddCommand(newUserImportCommand(importer))
+	cmd.AddCommand(newUserPurgeCommand(userService, cfg))
+	return cmd
+}
+
+func newUserPurgeCommand(userService *service.UserDomainService, cfg *config.Config) *cobra.Command {
+	var (
+		retention time.Duration
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "彻底删除超过保留期的已软删除用户，删除后无法恢复",
+		Example: `  services-cli user purge --dry-run
+  services-cli user purge --retention 2160h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("retention") {
+				retention = cfg.User.PurgeRetention
+				if retention <= 0 {
+					retention = defaultPurgeRetention
+				}
+			}
+			if retention <= 0 {
+				return fmt.Errorf("retention must be positive")
+			}
+
+			before := time.Now().Add(-retention)
+			count, err := userService.PurgeDeletedUsers(context.Background(), before, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				fmt.Printf("dry-run: %d users deleted before %s would be purged\n", count, before.Format(time.RFC3339))
+				return nil
+			}
+			fmt.Printf("Purged %d users deleted before %s\n", count, before.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&retention, "retention", 0, "保留时长，默认使用配置 user.purge_retention")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只统计将被删除的用户数，不删除")
+	return cmd
+}
+
+func newUserImportCommand(importer appservice.UserImporterInterface) *cobra.Command {
+	var (
+		dryRun     bool
+		batchSize  int
+		reportPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "从CSV或XLSX文件批量创建用户",
+		Long: `从CSV或XLSX文件批量创建用户，表头为 open_id,name,gender,phone_number,password(顺序任意)。
+每行按创建用户的规则校验，失败的行不影响其他行，失败原因在结束后输出或写入 --report 指定的文件。`,
+		Example: `  services-cli users import users.csv --dry-run
+  services-cli users import users.xlsx --report errors.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := tabular.FormatFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			rows, err := importer.ReadRows(file, format)
+			if err != nil {
+				return err
+			}
+
+			opts := appservice.UserImportOptions{DryRun: dryRun, BatchSize: batchSize}
+			result, err := importer.Import(context.Background(), rows, opts, func(p appservice.UserImportProgress) {
+				fmt.Fprintf(os.Stderr, "\rProcessed %d/%d, created %d, failed %d", p.Processed, p.Total, p.Created, p.Failed)
+			})
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				fmt.Printf("dry-run: %d rows, %d users would be created, %d failed\n", result.Total, result.Created, result.Failed)
+			} else {
+				fmt.Printf("Imported %d rows, %d users created, %d failed\n", result.Total, result.Created, result.Failed)
+			}
+
+			if reportPath != "" {
+				if err := writeImportReport(reportPath, result.Errors); err != nil {
+					return err
+				}
+				fmt.Printf("Error report written to %s\n", reportPath)
+				return nil
+			}
+			for idx, rowErr := range result.Errors {
+				if idx == maxPrintedImportErrors {
+					fmt.Printf("... %d more, use --report to save all errors\n", len(result.Errors)-idx)
+					break
+				}
+				fmt.Printf("  line %d (%s): %s\n", rowErr.Line, rowErr.OpenID, rowErr.Message)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只校验，不创建用户")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "每批插入的用户数，默认使用配置 user.import.batch_size")
+	cmd.Flags().StringVar(&reportPath, "report", "", "把失败的行及原因写入CSV文件")
+	return cmd
+}
+
+// writeImportReport 把失败的行写入CSV，列与 HTTP 接口下载的错误报告一致
+func writeImportReport(path string, rowErrors []appservice.UserImportRowError) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := tabular.NewCSVWriter(file)
+	_ = writer.Write([]string{"line", "open_id", "column", "message"})
+	for _, rowErr := range rowErrors {
+		_ = writer.Write([]string{strconv.Itoa(rowErr.Line), rowErr.OpenID, rowErr.Column, rowErr.Message})
+	}
+	writer.Flush()
+	return writer.Error()
+}
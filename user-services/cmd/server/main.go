@@ -25,6 +25,11 @@
 //	@name						Authorization
 //	@description				JWT Token，格式：Bearer {token}
 //
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				服务间调用使用的API Key，通过CLI创建
+//
 //	@schemes					http https
 //	@produce					json
 //	@consumes					json
@@ -40,6 +45,8 @@ import (
 
 	commonDI "common/di"
 	"user-services/internal/application"
+	"user-services/internal/domain/apikey"
+	"user-services/internal/domain/audit"
 	"user-services/internal/domain/user"
 	"user-services/internal/infrastructure"
 	"user-services/internal/interfaces/http"
@@ -60,6 +67,8 @@ func main() {
 
 		// 领域模块
 		user.DomainModule,
+		apikey.DomainModule,
+		audit.DomainModule,
 
 		// 应用模块
 		application.ApplicationModule,
@@ -103,6 +112,8 @@ func generateDependencyGraph(app *fx.App, outputFile string) {
 
 		// 领域模块
 		user.DomainModule,
+		apikey.DomainModule,
+		audit.DomainModule,
 
 		// 应用模块
 		application.ApplicationModule,
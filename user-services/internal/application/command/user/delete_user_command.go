@@ -0,0 +1,6 @@
+package command
+
+// DeleteUserCommand 删除用户命令
+type DeleteUserCommand struct {
+	UserID string
+}
@@ -0,0 +1,6 @@
+package command
+
+// DisableUserCommand 停用账号命令
+type DisableUserCommand struct {
+	UserID string
+}
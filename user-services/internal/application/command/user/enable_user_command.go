@@ -0,0 +1,6 @@
+package command
+
+// EnableUserCommand 启用账号命令
+type EnableUserCommand struct {
+	UserID string
+}
@@ -0,0 +1,6 @@
+package command
+
+// RestoreUserCommand 恢复已删除用户命令
+type RestoreUserCommand struct {
+	UserID string
+}
@@ -0,0 +1,8 @@
+package command
+
+// UpdateUserCommand 更新用户命令
+// Updates 按JSON合并语义给出：未出现的字段保持不变，值为nil表示清空该字段
+type UpdateUserCommand struct {
+	UserID  string
+	Updates map[string]interface{}
+}
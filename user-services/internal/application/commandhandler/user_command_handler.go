@@ -3,7 +3,11 @@ package commandhandler
 import (
 	"context"
 
+	"go.uber.org/zap"
+
+	"common/logger"
 	command "user-services/internal/application/command/user"
+	appservice "user-services/internal/application/service"
 	"user-services/internal/domain/user/entity"
 	"user-services/internal/domain/user/repository"
 	"user-services/internal/domain/user/service"
@@ -15,6 +19,7 @@ type UserCommandHandler struct {
 	userRepo          repository.UserRepository
 	userDomainService *service.UserDomainService
 	eventPublisher    messaging.EventPublisher
+	authService       appservice.AuthServiceInterface
 }
 
 // NewUserCommandHandler 创建用户命令处理器
@@ -22,11 +27,13 @@ func NewUserCommandHandler(
 	userRepo repository.UserRepository,
 	userDomainService *service.UserDomainService,
 	eventPublisher messaging.EventPublisher,
+	authService appservice.AuthServiceInterface,
 ) *UserCommandHandler {
 	return &UserCommandHandler{
 		userRepo:          userRepo,
 		userDomainService: userDomainService,
 		eventPublisher:    eventPublisher,
+		authService:       authService,
 	}
 }
 
@@ -46,3 +53,66 @@ func (h *UserCommandHandler) HandleCreateUser(ctx context.Context, cmd *command.
 
 	return user, nil
 }
+
+// HandleUpdateUser 处理更新用户命令，有字段变化时发布用户更新事件
+func (h *UserCommandHandler) HandleUpdateUser(ctx context.Context, cmd *command.UpdateUserCommand) (*entity.User, error) {
+	user, changes, err := h.userDomainService.UpdateUser(ctx, cmd.UserID, cmd.Updates)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	if err := h.eventPublisher.PublishUserUpdated(ctx, user, changes); err != nil {
+		logger.Warn(ctx, "Failed to publish user updated event", zap.String("user_id", user.ID()), zap.Error(err))
+	}
+
+	return user, nil
+}
+
+// HandleDeleteUser 处理删除用户命令，软删除后吊销该用户的全部令牌
+func (h *UserCommandHandler) HandleDeleteUser(ctx context.Context, cmd *command.DeleteUserCommand) error {
+	if err := h.userDomainService.DeleteUser(ctx, cmd.UserID); err != nil {
+		return err
+	}
+
+	logger.Info(ctx, "User deleted", zap.String("user_id", cmd.UserID))
+	return h.authService.RevokeUserTokens(ctx, cmd.UserID)
+}
+
+// HandleRestoreUser 处理恢复用户命令
+func (h *UserCommandHandler) HandleRestoreUser(ctx context.Context, cmd *command.RestoreUserCommand) (*entity.User, error) {
+	user, err := h.userDomainService.RestoreUser(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "User restored", zap.String("user_id", cmd.UserID))
+	return user, nil
+}
+
+// HandleEnableUser 处理启用账号命令
+func (h *UserCommandHandler) HandleEnableUser(ctx context.Context, cmd *command.EnableUserCommand) (*entity.User, error) {
+	user, err := h.userDomainService.EnableUser(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "User enabled", zap.String("user_id", cmd.UserID))
+	return user, nil
+}
+
+// HandleDisableUser 处理停用账号命令，停用后吊销该用户的全部令牌
+func (h *UserCommandHandler) HandleDisableUser(ctx context.Context, cmd *command.DisableUserCommand) (*entity.User, error) {
+	user, err := h.userDomainService.DisableUser(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "User disabled", zap.String("user_id", cmd.UserID))
+	if err := h.authService.RevokeUserTokens(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
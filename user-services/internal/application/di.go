@@ -20,5 +20,21 @@ var ApplicationModule = fx.Module("application",
 		// 应用服务
 		service.NewPermissionService,
 		service.NewAuthService,
+		service.NewVerificationCodeService,
+		service.NewLoginGuard,
+		service.NewMFAService,
+		service.NewPasswordService,
+		service.NewPasswordResetNotifier,
+		service.NewAPIKeyService,
+		service.NewAuditService,
+		service.NewUserImporter,
+		service.NewUserImportJobService,
 	),
+
+	// 停止服务时中断执行中的导入任务
+	fx.Invoke(func(lc fx.Lifecycle, jobService service.UserImportJobServiceInterface) {
+		lc.Append(fx.Hook{
+			OnStop: jobService.Stop,
+		})
+	}),
 )
@@ -0,0 +1,8 @@
+package user
+
+import "common/pkg/filter"
+
+// ExportUsersQuery 导出用户查询
+type ExportUsersQuery struct {
+	Filter *filter.Query `json:"-"` // 过滤条件，导出固定按创建时间倒序，忽略排序条件
+}
@@ -1,13 +1,12 @@
 package user
 
-import "time"
+import "common/pkg/filter"
 
 // ListUsersQuery 用户列表查询
 type ListUsersQuery struct {
-	Page      int        `json:"page" validate:"min=1"`
-	PageSize  int        `json:"page_size" validate:"min=1,max=100"`
-	Name      *string    `json:"name,omitempty"`       // 姓名模糊查询
-	Gender    *int       `json:"gender,omitempty"`     // 性别过滤
-	StartTime *time.Time `json:"start_time,omitempty"` // 创建时间开始
-	EndTime   *time.Time `json:"end_time,omitempty"`   // 创建时间结束
+	Page      int           `json:"page" validate:"min=1"`
+	PageSize  int           `json:"page_size" validate:"min=1,max=100"`
+	Filter    *filter.Query `json:"-"`                    // 过滤和排序条件
+	Cursor    string        `json:"cursor,omitempty"`     // 游标分页时上一页返回的游标，第一页为空
+	WithTotal bool          `json:"with_total,omitempty"` // 游标分页时是否统计总数
 }
@@ -4,25 +4,41 @@ import (
 	"context"
 
 	"common/databases/redis"
+	"common/pkg/pagination"
 	"user-services/internal/application/query/user"
 	"user-services/internal/domain/user/entity"
+	domainuser "user-services/internal/domain/user/errors"
 	"user-services/internal/domain/user/repository"
 )
 
+// exportBatchSize 导出时每次从数据库读取的用户数
+const exportBatchSize = 500
+
 // UserQueryHandler 用户查询处理器
 type UserQueryHandler struct {
 	userRepo    repository.UserRepository
 	redisClient *redis.RedisClient
+	cursorCodec *pagination.CursorCodec
+}
+
+// UserCursorPage 游标分页查询结果
+type UserCursorPage struct {
+	Users      []*entity.User
+	NextCursor string // 下一页游标，没有下一页时为空
+	HasMore    bool
+	Total      *int64 // 仅在查询要求时统计
 }
 
 // NewUserQueryHandler 创建用户查询处理器
 func NewUserQueryHandler(
 	userRepo repository.UserRepository,
 	redisClient *redis.RedisClient,
+	cursorCodec *pagination.CursorCodec,
 ) *UserQueryHandler {
 	return &UserQueryHandler{
 		userRepo:    userRepo,
 		redisClient: redisClient,
+		cursorCodec: cursorCodec,
 	}
 }
 
@@ -31,16 +47,8 @@ func (h *UserQueryHandler) HandleListUsers(ctx context.Context, query *user.List
 	// 计算偏移量
 	offset := (query.Page - 1) * query.PageSize
 
-	// 构建过滤条件
-	filter := &repository.UserListFilter{
-		Name:      query.Name,
-		Gender:    query.Gender,
-		StartTime: query.StartTime,
-		EndTime:   query.EndTime,
-	}
-
 	// 调用仓储层查询
-	users, total, err := h.userRepo.ListWithFilter(ctx, filter, offset, query.PageSize)
+	users, total, err := h.userRepo.ListWithFilter(ctx, query.Filter, offset, query.PageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -48,9 +56,50 @@ func (h *UserQueryHandler) HandleListUsers(ctx context.Context, query *user.List
 	return users, total, nil
 }
 
+// HandleListUsersByCursor 处理游标分页的用户列表查询
+// 多查询一条记录判断是否还有下一页，避免每次翻页都执行COUNT
+func (h *UserQueryHandler) HandleListUsersByCursor(ctx context.Context, query *user.ListUsersQuery) (*UserCursorPage, error) {
+	var after *pagination.Cursor
+	if query.Cursor != "" {
+		cursor, err := h.cursorCodec.Decode(query.Cursor)
+		if err != nil {
+			return nil, domainuser.ErrInvalidCursor
+		}
+		after = cursor
+	}
+
+	users, err := h.userRepo.ListWithCursor(ctx, query.Filter, after, query.PageSize+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserCursorPage{Users: users}
+	if len(users) > query.PageSize {
+		page.Users = users[:query.PageSize]
+		page.HasMore = true
+		last := page.Users[len(page.Users)-1]
+		page.NextCursor = h.cursorCodec.Encode(pagination.Cursor{CreatedAt: last.CreatedAt(), ID: last.ID()})
+	}
+
+	if query.WithTotal {
+		total, err := h.userRepo.CountWithFilter(ctx, query.Filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// HandleExportUsers 处理导出用户查询，按创建时间倒序把符合条件的用户逐个交给 fn
+// 用户分批从数据库读取，不会一次加载全部结果；fn 返回错误时停止导出
+func (h *UserQueryHandler) HandleExportUsers(ctx context.Context, query *user.ExportUsersQuery, fn func(*entity.User) error) error {
+	return h.userRepo.IterateWithFilter(ctx, query.Filter, exportBatchSize, fn)
+}
+
 // HandleGetUser 处理获取用户查询
 func (h *UserQueryHandler) HandleGetUser(ctx context.Context, query *user.GetUserQuery) (*entity.User, error) {
 
 	return h.userRepo.GetByID(ctx, query.ID)
 }
-
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/response"
+	"user-services/internal/domain/apikey/entity"
+	"user-services/internal/domain/apikey/repository"
+	userRepository "user-services/internal/domain/user/repository"
+)
+
+const (
+	// apiKeyMarker 明文密钥的固定开头，便于在日志和代码仓库扫描中识别泄露的密钥
+	apiKeyMarker = "ak"
+	// apiKeyPrefixBytes 前缀随机字节数，前缀以十六进制保存并用于查找密钥
+	apiKeyPrefixBytes = 6
+	// apiKeyLastUsedInterval 最近使用时间的最小更新间隔，避免每次请求都写库
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// CreateAPIKeyInput 创建API Key参数
+type CreateAPIKeyInput struct {
+	Name    string
+	OwnerID string        // 调用方身份，认证通过后作为用户ID参与权限校验
+	Scopes  []string      // 授权范围，"*" 表示全部
+	TTL     time.Duration // 有效期，0表示永不过期
+}
+
+// CreatedAPIKey 新建的API Key，明文密钥只在创建时返回一次
+type CreatedAPIKey struct {
+	Key      *entity.APIKey
+	PlainKey string
+}
+
+// APIKeyServiceInterface API Key服务接口
+type APIKeyServiceInterface interface {
+	Create(ctx context.Context, input CreateAPIKeyInput) (*CreatedAPIKey, error)
+	List(ctx context.Context, ownerID string) ([]*entity.APIKey, error)
+	Revoke(ctx context.Context, idOrPrefix string) (*entity.APIKey, error)
+	Authenticate(ctx context.Context, plainKey string) (*entity.APIKey, error)
+}
+
+// APIKeyService 服务间调用的API Key管理与认证
+// 明文格式为 ak_<prefix>_<secret>，库中只保存前缀和完整密钥的SHA-256哈希
+type APIKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   userRepository.UserRepository
+}
+
+// NewAPIKeyService 创建API Key服务
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo userRepository.UserRepository) APIKeyServiceInterface {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Create 生成新的API Key
+func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (*CreatedAPIKey, error) {
+	name := strings.TrimSpace(input.Name)
+	ownerID := strings.TrimSpace(input.OwnerID)
+	if name == "" {
+		return nil, response.NewValidationError("API Key名称不能为空")
+	}
+	if ownerID == "" {
+		return nil, response.NewValidationError("API Key所有者不能为空")
+	}
+	if input.TTL < 0 {
+		return nil, response.NewValidationError("API Key有效期不能为负数")
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, response.NewInternalServerError("生成API Key失败", err)
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	secret, err := newRandomToken()
+	if err != nil {
+		return nil, response.NewInternalServerError("生成API Key失败", err)
+	}
+	plainKey := apiKeyMarker + "_" + prefix + "_" + secret
+
+	var expiresAt *time.Time
+	if input.TTL > 0 {
+		t := time.Now().Add(input.TTL)
+		expiresAt = &t
+	}
+
+	key := entity.NewAPIKey(name, prefix, hashCode(plainKey), ownerID, normalizeScopes(input.Scopes), expiresAt)
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "API key created",
+		zap.String("key_id", key.ID()),
+		zap.String("prefix", prefix),
+		zap.String("owner_id", ownerID))
+	return &CreatedAPIKey{Key: key, PlainKey: plainKey}, nil
+}
+
+// List 列出API Key，ownerID为空时列出全部
+func (s *APIKeyService) List(ctx context.Context, ownerID string) ([]*entity.APIKey, error) {
+	return s.apiKeyRepo.List(ctx, ownerID)
+}
+
+// Revoke 按ID或前缀吊销API Key，吊销后立即失效
+func (s *APIKeyService) Revoke(ctx context.Context, idOrPrefix string) (*entity.APIKey, error) {
+	var (
+		key *entity.APIKey
+		err error
+	)
+	if _, parseErr := uuid.Parse(idOrPrefix); parseErr == nil {
+		key, err = s.apiKeyRepo.GetByID(ctx, idOrPrefix)
+	} else {
+		key, err = s.apiKeyRepo.FindByPrefix(ctx, idOrPrefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if key.IsRevoked() {
+		return key, nil
+	}
+	key.Revoke(time.Now())
+	if err := s.apiKeyRepo.UpdateRevokedAt(ctx, key); err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "API key revoked", zap.String("key_id", key.ID()), zap.String("prefix", key.Prefix()))
+	return key, nil
+}
+
+// Authenticate 校验明文密钥，返回有效的API Key
+// 所有者为用户时还要求该用户未被删除且状态正常，停用或删除用户后其API Key随之失效
+func (s *APIKeyService) Authenticate(ctx context.Context, plainKey string) (*entity.APIKey, error) {
+	parts := strings.SplitN(plainKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyMarker || parts[1] == "" || parts[2] == "" {
+		return nil, response.NewUnauthorizedError("API Key无效")
+	}
+
+	key, err := s.apiKeyRepo.FindByPrefix(ctx, parts[1])
+	if err != nil {
+		if response.IsErrorType(err, response.ErrorTypeNotFound) {
+			return nil, response.NewUnauthorizedError("API Key无效")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashCode(plainKey)), []byte(key.KeyHash())) != 1 {
+		return nil, response.NewUnauthorizedError("API Key无效")
+	}
+
+	now := time.Now()
+	if key.IsRevoked() {
+		return nil, response.NewUnauthorizedError("API Key已被吊销")
+	}
+	if key.IsExpired(now) {
+		return nil, response.NewUnauthorizedError("API Key已过期")
+	}
+	if err := s.checkOwner(ctx, key); err != nil {
+		return nil, err
+	}
+
+	if last := key.LastUsedAt(); last == nil || now.Sub(*last) >= apiKeyLastUsedInterval {
+		key.MarkUsed(now)
+		if err := s.apiKeyRepo.UpdateLastUsedAt(ctx, key.ID(), now); err != nil {
+			logger.Warn(ctx, "Failed to update API key last used time", zap.String("key_id", key.ID()), zap.Error(err))
+		}
+	}
+	return key, nil
+}
+
+// checkOwner 检查API Key的所有者是否仍然可用
+// 所有者为用户ID时用户必须存在且可以登录；服务身份(如 svc:billing)不对应用户，不做检查
+func (s *APIKeyService) checkOwner(ctx context.Context, key *entity.APIKey) error {
+	if _, err := uuid.Parse(key.OwnerID()); err != nil {
+		return nil
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, key.OwnerID())
+	if err != nil {
+		if response.IsErrorType(err, response.ErrorTypeNotFound) {
+			return response.NewUnauthorizedError("API Key所有者不存在或已被删除")
+		}
+		return err
+	}
+	if err := owner.EnsureCanLogin(); err != nil {
+		return response.NewUnauthorizedError("API Key所有者账号不可用", err)
+	}
+	return nil
+}
+
+// normalizeScopes 去除空白和重复的授权范围
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		result = append(result, scope)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/response"
+	"user-services/internal/domain/apikey/entity"
+	apiKeyRepository "user-services/internal/domain/apikey/repository"
+	userEntity "user-services/internal/domain/user/entity"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// fakeAPIKeyRepository 内存中的API Key仓储
+type fakeAPIKeyRepository struct {
+	apiKeyRepository.APIKeyRepository
+	keys map[string]*entity.APIKey // 按前缀索引
+}
+
+func (r *fakeAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	key.SetID(uuid.NewString())
+	r.keys[key.Prefix()] = key
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	key, ok := r.keys[prefix]
+	if !ok {
+		return nil, response.NewNotFoundError("API Key不存在")
+	}
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepository) UpdateLastUsedAt(ctx context.Context, id string, usedAt time.Time) error {
+	return nil
+}
+
+func TestAPIKeyService_AuthenticateChecksOwner(t *testing.T) {
+	activeID, disabledID, lockedID, deletedID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	users := map[string]*userEntity.User{}
+	for id, status := range map[string]valueobject.UserStatus{
+		activeID:   valueobject.UserStatusActive,
+		disabledID: valueobject.UserStatusDisabled,
+		lockedID:   valueobject.UserStatusLocked,
+	} {
+		user := userEntity.NewUser("", "张三", "", "", valueobject.GenderMale.Int())
+		user.SetID(id)
+		user.SetStatus(status)
+		users[id] = user
+	}
+
+	tests := []struct {
+		name    string
+		ownerID string
+		wantErr bool
+	}{
+		{name: "active user", ownerID: activeID},
+		{name: "service identity", ownerID: "svc:billing"},
+		{name: "disabled user", ownerID: disabledID, wantErr: true},
+		{name: "locked user", ownerID: lockedID, wantErr: true},
+		{name: "deleted user", ownerID: deletedID, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := NewAPIKeyService(
+				&fakeAPIKeyRepository{keys: map[string]*entity.APIKey{}},
+				&fakeUserRepository{users: users},
+			)
+
+			created, err := svc.Create(ctx, CreateAPIKeyInput{Name: "job", OwnerID: tt.ownerID, Scopes: []string{"users:read"}})
+			require.NoError(t, err)
+
+			key, err := svc.Authenticate(ctx, created.PlainKey)
+			if tt.wantErr {
+				assert.True(t, response.IsErrorType(err, response.ErrorTypeUnauthorized), "got %v", err)
+				assert.Nil(t, key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ownerID, key.OwnerID())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/pkg/contextutil"
+	"user-services/internal/domain/audit/entity"
+	"user-services/internal/domain/audit/repository"
+)
+
+// AuditServiceInterface 审计日志服务接口
+type AuditServiceInterface interface {
+	// Record 记录一次管理操作，操作者和客户端IP从上下文中获取
+	Record(ctx context.Context, domain, action, resource string, detail map[string]any) error
+	// List 按时间倒序分页查询审计记录
+	List(ctx context.Context, filter repository.AuditLogFilter, page, pageSize int) ([]*entity.AuditLog, int64, error)
+}
+
+// AuditService 审计日志服务
+type AuditService struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+// NewAuditService 创建审计日志服务
+func NewAuditService(auditLogRepo repository.AuditLogRepository) AuditServiceInterface {
+	return &AuditService{auditLogRepo: auditLogRepo}
+}
+
+// Record 记录一次管理操作
+func (s *AuditService) Record(ctx context.Context, domain, action, resource string, detail map[string]any) error {
+	actorID, ok := contextutil.GetUserIDFromContext(ctx)
+	if !ok || actorID == "" {
+		actorID = entity.ActorSystem
+	}
+	clientIP, _ := ctx.Value(contextutil.ClientIPContextKey).(string)
+
+	log := entity.NewAuditLog(actorID, domain, action, resource, detail, clientIP)
+	if err := s.auditLogRepo.Create(ctx, log); err != nil {
+		logger.Error(ctx, "Failed to write audit log",
+			zap.String("actor_id", actorID),
+			zap.String("domain", domain),
+			zap.String("action", action),
+			zap.String("resource", resource),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// List 按时间倒序分页查询审计记录
+func (s *AuditService) List(ctx context.Context, filter repository.AuditLogFilter, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.auditLogRepo.List(ctx, filter, offset, pageSize)
+}
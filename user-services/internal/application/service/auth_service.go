@@ -1,65 +1,384 @@
 package service
 
 import (
-	"common/response"
 	"context"
-	"user-services/internal/domain/user/repository"
-	"time"
+	"errors"
 
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+
+	"common/logger"
+	"common/pkg/jwt"
+	"common/pkg/wechat"
+	"common/response"
+	"user-services/internal/domain/user/entity"
+	"user-services/internal/domain/user/repository"
+	domainservice "user-services/internal/domain/user/service"
 )
 
 // AuthServiceInterface 认证服务接口
 type AuthServiceInterface interface {
-	LoginByPassword(ctx context.Context, phoneNumber, password string) (string, string, error)
-	LoginByWeChat(ctx context.Context, code string) (string, string, error)
-	Logout(ctx context.Context, tokenID string, expiresAt time.Time) error
+	LoginByPassword(ctx context.Context, phoneNumber, password, clientIP string) (*LoginResult, error)
+	LoginByMFA(ctx context.Context, mfaToken, code string) (string, string, error)
+	LoginByWeChat(ctx context.Context, code string) (*LoginResult, error)
+	LoginBySMS(ctx context.Context, phoneNumber, code string) (*LoginResult, error)
+	BindWeChatPhone(ctx context.Context, userID, code string) (*entity.User, error)
+	IssueTokenPair(ctx context.Context, userID, username string, meta jwt.SessionMeta) (*jwt.TokenPair, error)
+	RefreshToken(ctx context.Context, refreshToken, clientIP string) (*jwt.TokenPair, error)
+	Logout(ctx context.Context, claims *jwt.CustomClaims) error
+	RevokeUserTokens(ctx context.Context, userID string) error
+	IsTokenRevoked(ctx context.Context, claims *jwt.CustomClaims) (bool, error)
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	ListSessions(ctx context.Context, userID string) ([]*jwt.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+}
+
+// LoginResult 登录结果
+type LoginResult struct {
+	UserID   string
+	Username string
+	// MFAChallenge 非空表示用户已启用两步验证，需调用 LoginByMFA 完成登录，所有登录方式都是如此
+	MFAChallenge *MFAChallenge
 }
 
 // AuthService 认证服务
 type AuthService struct {
-	userRepo repository.UserRepository
-	// redisClient *redis.Client // for blocklist
+	userRepo     repository.UserRepository
+	userService  *domainservice.UserDomainService
+	wechatClient *wechat.Client
+	codeService  VerificationCodeServiceInterface
+	loginGuard   LoginGuardInterface
+	mfaService   MFAServiceInterface
+	jwtService   *jwt.JWT
+	refreshStore *jwt.RefreshTokenStore
+	revocation   *jwt.RevocationStore
 }
 
 // NewAuthService 创建认证服务
-func NewAuthService(userRepo repository.UserRepository) AuthServiceInterface {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	userService *domainservice.UserDomainService,
+	wechatClient *wechat.Client,
+	codeService VerificationCodeServiceInterface,
+	loginGuard LoginGuardInterface,
+	mfaService MFAServiceInterface,
+	jwtService *jwt.JWT,
+	refreshStore *jwt.RefreshTokenStore,
+	revocation *jwt.RevocationStore,
+) AuthServiceInterface {
 	return &AuthService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		userService:  userService,
+		wechatClient: wechatClient,
+		codeService:  codeService,
+		loginGuard:   loginGuard,
+		mfaService:   mfaService,
+		jwtService:   jwtService,
+		refreshStore: refreshStore,
+		revocation:   revocation,
 	}
 }
 
 // LoginByPassword 账号密码登录
-func (s *AuthService) LoginByPassword(ctx context.Context, phoneNumber, password string) (string, string, error) {
-	// 1. 查找用户
+// 按手机号和IP统计连续失败次数，失败过多时需要等待或被临时锁定
+// 已启用两步验证的用户返回待完成的两步验证，而不是直接登录成功
+func (s *AuthService) LoginByPassword(ctx context.Context, phoneNumber, password, clientIP string) (*LoginResult, error) {
+	// 同一号码的不同写法共用失败计数；无法识别的号码不可能已注册，按原样计入失败次数
+	if normalized, err := s.userService.NormalizePhoneNumber(phoneNumber); err == nil {
+		phoneNumber = normalized
+	}
+
+	// 1. 检查是否处于等待或锁定状态
+	if err := s.loginGuard.Check(ctx, phoneNumber, clientIP); err != nil {
+		return nil, err
+	}
+
+	// 2. 查找用户，手机号不存在与密码错误返回相同的错误并同样计入失败次数
 	user, err := s.userRepo.FindByPhoneNumber(ctx, phoneNumber)
 	if err != nil {
-		return "", "", err // 可能是用户不存在或数据库错误
+		if !response.IsErrorType(err, response.ErrorTypeNotFound) {
+			return nil, err
+		}
+		return nil, s.loginFailed(ctx, phoneNumber, clientIP)
 	}
 
-	// 2. 验证密码
+	// 3. 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password()), []byte(password)); err != nil {
-		return "", "", response.NewUnauthorizedError("手机号或密码错误")
+		return nil, s.loginFailed(ctx, phoneNumber, clientIP)
+	}
+
+	// 4. 密码正确，清除失败计数
+	s.loginGuard.Reset(ctx, phoneNumber)
+
+	// 5. 只有正常状态的账号可以登录
+	if err := user.EnsureCanLogin(); err != nil {
+		return nil, err
 	}
 
-	// 3. 登录成功，返回用户ID和用户名
+	// 6. 已启用两步验证时需要继续校验验证码
+	return s.loginResult(ctx, user)
+}
+
+// loginResult 第一步验证通过后的登录结果
+// 已启用两步验证的用户无论使用哪种登录方式都只得到两步验证令牌，否则持有短信验证码或微信账号即可绕过第二步
+func (s *AuthService) loginResult(ctx context.Context, user *entity.User) (*LoginResult, error) {
+	result := &LoginResult{UserID: user.ID(), Username: user.Name()}
+	if user.TOTPEnabled() {
+		challenge, err := s.mfaService.CreateChallenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		result.MFAChallenge = challenge
+	}
+	return result, nil
+}
+
+// LoginByMFA 使用登录返回的两步验证令牌和验证码(TOTP或恢复码)完成登录
+func (s *AuthService) LoginByMFA(ctx context.Context, mfaToken, code string) (string, string, error) {
+	user, err := s.mfaService.VerifyChallenge(ctx, mfaToken, code)
+	if err != nil {
+		return "", "", err
+	}
+	// 两步验证期间账号可能已被停用
+	if err := user.EnsureCanLogin(); err != nil {
+		return "", "", err
+	}
 	return user.ID(), user.Name(), nil
 }
 
-// LoginByWeChat 微信登录
-func (s *AuthService) LoginByWeChat(ctx context.Context, code string) (string, string, error) {
-	// 1. Use the code to get user info from WeChat's API (e.g., openID).
-	// 2. Check if a user with this openID exists in the database.
-	//    - If not, create a new user.
-	//    - If yes, retrieve the user.
-	// 3. Return user ID and name.
-	return "wechat_user_id", "wechat_user_name", nil
+// loginFailed 记录密码登录失败，本次失败触发锁定时返回锁定错误
+func (s *AuthService) loginFailed(ctx context.Context, phoneNumber, clientIP string) error {
+	if err := s.loginGuard.RecordFailure(ctx, phoneNumber, clientIP); err != nil {
+		return err
+	}
+	return response.NewUnauthorizedError("手机号或密码错误")
 }
 
-// Logout 登出
-func (s *AuthService) Logout(ctx context.Context, tokenID string, expiresAt time.Time) error {
-	// In a real implementation, add the tokenID to a blocklist in Redis
-	// with an expiration equal to the token's remaining validity.
-	// e.g., redisClient.Set(ctx, tokenID, "", time.Until(expiresAt))
+// LoginBySMS 短信验证码登录
+// 先校验验证码再查找用户，避免通过登录接口探测手机号是否已注册
+func (s *AuthService) LoginBySMS(ctx context.Context, phoneNumber, code string) (*LoginResult, error) {
+	phoneNumber, err := s.userService.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.codeService.VerifyCode(ctx, CodePurposeLogin, phoneNumber, code); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if response.IsErrorType(err, response.ErrorTypeNotFound) {
+			return nil, response.NewUnauthorizedError("手机号未注册")
+		}
+		return nil, err
+	}
+	if err := user.EnsureCanLogin(); err != nil {
+		return nil, err
+	}
+
+	return s.loginResult(ctx, user)
+}
+
+// LoginByWeChat 微信小程序登录
+// 使用 wx.login 的 code 换取 open_id，用户不存在时自动注册
+func (s *AuthService) LoginByWeChat(ctx context.Context, code string) (*LoginResult, error) {
+	// 1. 使用code换取open_id
+	session, err := s.wechatClient.Code2Session(ctx, code)
+	if err != nil {
+		return nil, wrapWeChatError(err)
+	}
+
+	// 2. 按open_id查找用户
+	user, err := s.userRepo.FindByOpenID(ctx, session.OpenID)
+	if err == nil {
+		if err := user.EnsureCanLogin(); err != nil {
+			return nil, err
+		}
+		return s.loginResult(ctx, user)
+	}
+	if !response.IsErrorType(err, response.ErrorTypeNotFound) {
+		return nil, err
+	}
+
+	// 3. 首次登录，创建用户
+	user, err = s.userService.RegisterByWeChat(ctx, session.OpenID)
+	if err != nil {
+		// 并发的首次登录可能已经创建了该用户
+		if response.IsErrorType(err, response.ErrorTypeAlreadyExists) {
+			if user, findErr := s.userRepo.FindByOpenID(ctx, session.OpenID); findErr == nil {
+				if err := user.EnsureCanLogin(); err != nil {
+					return nil, err
+				}
+				return s.loginResult(ctx, user)
+			}
+		}
+		return nil, err
+	}
+
+	logger.Info(ctx, "User registered via WeChat", zap.String("user_id", user.ID()))
+	return s.loginResult(ctx, user)
+}
+
+// BindWeChatPhone 使用微信手机号快速验证组件返回的code为用户绑定手机号
+func (s *AuthService) BindWeChatPhone(ctx context.Context, userID, code string) (*entity.User, error) {
+	phoneInfo, err := s.wechatClient.GetPhoneNumber(ctx, code)
+	if err != nil {
+		return nil, wrapWeChatError(err)
+	}
+
+	// 带上区号，境外手机号不会被按默认地区解析
+	phoneNumber := phoneInfo.PurePhoneNumber
+	if phoneInfo.CountryCode != "" {
+		phoneNumber = "+" + phoneInfo.CountryCode + phoneInfo.PurePhoneNumber
+	}
+	return s.userService.BindPhoneNumber(ctx, userID, phoneNumber)
+}
+
+// IssueTokenPair 为登录成功的用户创建登录会话，并签发访问令牌和刷新令牌
+// 配置了最大会话数时，超出上限的最早会话将被吊销
+func (s *AuthService) IssueTokenPair(ctx context.Context, userID, username string, meta jwt.SessionMeta) (*jwt.TokenPair, error) {
+	refreshToken, record, err := s.refreshStore.Issue(ctx, userID, username, meta)
+	if err != nil {
+		return nil, response.NewInternalServerError("签发刷新令牌失败", err)
+	}
+
+	evicted, err := s.refreshStore.EnforceSessionLimit(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "Failed to enforce session limit", zap.String("user_id", userID), zap.Error(err))
+	}
+	for _, session := range evicted {
+		logger.Info(ctx, "Session evicted by concurrent session limit",
+			zap.String("user_id", userID),
+			zap.String("session_id", session.ID),
+			zap.String("device", session.Device))
+	}
+
+	return s.newTokenPair(record, refreshToken)
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对
+// 刷新令牌只能使用一次，重复使用将吊销该次登录产生的所有刷新令牌
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, clientIP string) (*jwt.TokenPair, error) {
+	newRefreshToken, record, err := s.refreshStore.Rotate(ctx, refreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrRefreshTokenReused):
+			logger.Warn(ctx, "Refresh token reuse detected, token family revoked",
+				zap.String("user_id", record.UserID),
+				zap.String("family_id", record.FamilyID))
+			return nil, response.NewUnauthorizedError("刷新令牌已失效，请重新登录", err)
+		case errors.Is(err, jwt.ErrRefreshTokenInvalid), errors.Is(err, jwt.ErrRefreshTokenRevoked):
+			return nil, response.NewUnauthorizedError("刷新令牌无效或已过期", err)
+		default:
+			return nil, response.NewInternalServerError("刷新令牌失败", err)
+		}
+	}
+
+	// 确认用户仍然存在且可以登录，并使用最新的用户名签发令牌
+	user, err := s.userRepo.GetByID(ctx, record.UserID)
+	if err == nil {
+		err = user.EnsureCanLogin()
+	}
+	if err != nil {
+		if revokeErr := s.refreshStore.RevokeFamily(ctx, record.FamilyID); revokeErr != nil {
+			logger.Error(ctx, "Failed to revoke refresh token family", zap.Error(revokeErr))
+		}
+		return nil, err
+	}
+
+	// 刷新视为会话活跃，同时记录最新的客户端IP
+	if _, err := s.refreshStore.TouchSession(ctx, record.FamilyID, clientIP); err != nil {
+		logger.Warn(ctx, "Failed to update session last seen time", zap.Error(err))
+	}
+
+	record.Username = user.Name()
+	return s.newTokenPair(record, newRefreshToken)
+}
+
+// newTokenPair 生成绑定到会话及其登录租户的访问令牌，并与刷新令牌组装为令牌对
+func (s *AuthService) newTokenPair(record *jwt.RefreshRecord, refreshToken string) (*jwt.TokenPair, error) {
+	accessToken, err := s.jwtService.Generate(record.UserID, record.Username,
+		jwt.WithSessionID(record.FamilyID), jwt.WithTenantID(record.TenantID))
+	if err != nil {
+		return nil, response.NewInternalServerError("Failed to generate token", err)
+	}
+
+	return &jwt.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.jwtService.ExpiresIn().Seconds()),
+		RefreshExpiresIn: int64(s.refreshStore.TTL().Seconds()),
+		SessionID:        record.FamilyID,
+	}, nil
+}
+
+// Logout 登出，将当前访问令牌加入吊销列表直至其过期，并结束令牌所属的会话
+func (s *AuthService) Logout(ctx context.Context, claims *jwt.CustomClaims) error {
+	if err := s.revocation.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return response.NewInternalServerError("登出失败", err)
+	}
+	if claims.SessionID != "" {
+		err := s.refreshStore.RevokeSession(ctx, claims.UserID, claims.SessionID)
+		if err != nil && !errors.Is(err, jwt.ErrSessionNotFound) {
+			return response.NewInternalServerError("登出失败", err)
+		}
+	}
 	return nil
 }
+
+// RevokeUserTokens 吊销用户的全部令牌，包括已签发的访问令牌和刷新令牌
+// 用于修改密码、禁用账号等需要强制用户重新登录的场景
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userID string) error {
+	if err := s.revocation.RevokeUser(ctx, userID); err != nil {
+		return response.NewInternalServerError("吊销用户令牌失败", err)
+	}
+	if err := s.refreshStore.RevokeUser(ctx, userID); err != nil {
+		return response.NewInternalServerError("吊销用户刷新令牌失败", err)
+	}
+
+	logger.Info(ctx, "All tokens revoked for user", zap.String("user_id", userID))
+	return nil
+}
+
+// IsTokenRevoked 判断访问令牌是否已被吊销
+func (s *AuthService) IsTokenRevoked(ctx context.Context, claims *jwt.CustomClaims) (bool, error) {
+	return s.revocation.IsRevoked(ctx, claims)
+}
+
+// IsSessionActive 判断会话是否仍然有效，有效时顺带更新会话的最近活跃时间
+func (s *AuthService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s.refreshStore.TouchSession(ctx, sessionID, "")
+}
+
+// ListSessions 列出用户当前的登录会话
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]*jwt.Session, error) {
+	sessions, err := s.refreshStore.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, response.NewInternalServerError("获取会话列表失败", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession 吊销用户的指定会话，该会话的刷新令牌和访问令牌立即失效
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.refreshStore.RevokeSession(ctx, userID, sessionID); err != nil {
+		if errors.Is(err, jwt.ErrSessionNotFound) {
+			return response.NewNotFoundError("会话不存在或已失效")
+		}
+		return response.NewInternalServerError("吊销会话失败", err)
+	}
+
+	logger.Info(ctx, "Session revoked", zap.String("user_id", userID), zap.String("session_id", sessionID))
+	return nil
+}
+
+// wrapWeChatError 将微信接口错误转换为领域错误
+// code无效属于客户端错误，其余错误(如appid配置错误、网络异常)视为外部服务不可用
+func wrapWeChatError(err error) error {
+	var apiErr *wechat.APIError
+	if errors.As(err, &apiErr) && apiErr.IsInvalidCode() {
+		return response.NewInvalidRequestError("微信授权码无效或已过期", err).WithContext("errcode", apiErr.Code)
+	}
+	return response.NewExternalServiceUnavailableError("微信服务暂不可用", err)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"common/config"
+	"common/pkg/wechat"
+	"common/response"
+	userEntity "user-services/internal/domain/user/entity"
+	domainservice "user-services/internal/domain/user/service"
+	"user-services/internal/domain/user/validator"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// fakeCodeService 只接受验证码 123456
+type fakeCodeService struct {
+	VerificationCodeServiceInterface
+}
+
+func (s *fakeCodeService) VerifyCode(ctx context.Context, purpose CodePurpose, phoneNumber, code string) error {
+	if code != "123456" {
+		return response.NewUnauthorizedError("验证码错误")
+	}
+	return nil
+}
+
+// fakeMFAService 记录为哪些用户创建了两步验证
+type fakeMFAService struct {
+	MFAServiceInterface
+	challenged []string
+}
+
+func (s *fakeMFAService) CreateChallenge(ctx context.Context, user *userEntity.User) (*MFAChallenge, error) {
+	s.challenged = append(s.challenged, user.ID())
+	return &MFAChallenge{Token: "mfa-" + user.ID(), ExpiresIn: 300}, nil
+}
+
+// newTestWeChatClient 创建请求本地服务的微信客户端，code 即返回的 open_id
+func newTestWeChatClient(t *testing.T) *wechat.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"openid":"` + r.URL.Query().Get("js_code") + `","session_key":"sk"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.WeChat.BaseURL = server.URL
+	return wechat.NewClient(cfg)
+}
+
+func TestAuthService_LoginRequiresMFA(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	plain := userEntity.NewUser("wx-plain", "张三", "+8613800138000", string(hash), valueobject.GenderMale.Int())
+	plain.SetID("u-plain")
+	protected := userEntity.NewUser("wx-mfa", "李四", "+8613900139000", string(hash), valueobject.GenderMale.Int())
+	protected.SetID("u-mfa")
+	protected.SetTOTP("encrypted-secret", true, nil)
+
+	repo := newFakeUserRepository(plain, protected)
+	passwordPolicy := validator.PasswordPolicy{MinLength: 8, MaxLength: 72}
+	userService := domainservice.NewUserDomainService(repo,
+		validator.NewUserValidator(repo, passwordPolicy, validator.PhonePolicy{DefaultRegion: "CN"}), passwordPolicy)
+
+	logins := map[string]func(AuthServiceInterface, *userEntity.User) (*LoginResult, error){
+		"password": func(svc AuthServiceInterface, user *userEntity.User) (*LoginResult, error) {
+			return svc.LoginByPassword(context.Background(), user.PhoneNumber(), "password123", "127.0.0.1")
+		},
+		"sms": func(svc AuthServiceInterface, user *userEntity.User) (*LoginResult, error) {
+			return svc.LoginBySMS(context.Background(), user.PhoneNumber(), "123456")
+		},
+		"wechat": func(svc AuthServiceInterface, user *userEntity.User) (*LoginResult, error) {
+			return svc.LoginByWeChat(context.Background(), user.OpenID())
+		},
+	}
+
+	for method, login := range logins {
+		for _, user := range []*userEntity.User{plain, protected} {
+			t.Run(method+" "+user.ID(), func(t *testing.T) {
+				mfa := &fakeMFAService{}
+				svc := NewAuthService(repo, userService, newTestWeChatClient(t), &fakeCodeService{},
+					&fakeLoginGuard{}, mfa, nil, nil, nil)
+
+				result, err := login(svc, user)
+				require.NoError(t, err)
+				assert.Equal(t, user.ID(), result.UserID)
+
+				if !user.TOTPEnabled() {
+					assert.Nil(t, result.MFAChallenge)
+					assert.Empty(t, mfa.challenged)
+					return
+				}
+				require.NotNil(t, result.MFAChallenge, "login without the second factor")
+				assert.Equal(t, "mfa-"+user.ID(), result.MFAChallenge.Token)
+				assert.Equal(t, []string{user.ID()}, mfa.challenged)
+			})
+		}
+	}
+}
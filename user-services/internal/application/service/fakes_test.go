@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+
+	"common/databases/redis"
+	"common/response"
+	userEntity "user-services/internal/domain/user/entity"
+	userErrors "user-services/internal/domain/user/errors"
+	"user-services/internal/domain/user/repository"
+)
+
+// fakeUserRepository 内存中的用户仓储，已删除的用户不在其中
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[string]*userEntity.User
+
+	mu              sync.Mutex
+	passwordUpdates int
+	batches         int                          // CreateBatch 调用次数
+	existsErr       error                        // 非空时 ExistsBy* 返回该错误
+	createErr       func(*userEntity.User) error // 非空时按用户注入写入失败，整批不写入
+}
+
+func newFakeUserRepository(users ...*userEntity.User) *fakeUserRepository {
+	repo := &fakeUserRepository{users: make(map[string]*userEntity.User)}
+	for _, user := range users {
+		repo.users[user.ID()] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id string) (*userEntity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, response.NewNotFoundError(userErrors.MsgUserNotFound)
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*userEntity.User, error) {
+	for _, user := range r.users {
+		if user.PhoneNumber() == phoneNumber {
+			return user, nil
+		}
+	}
+	return nil, response.NewNotFoundError(userErrors.MsgUserNotFound)
+}
+
+func (r *fakeUserRepository) FindByOpenID(ctx context.Context, openID string) (*userEntity.User, error) {
+	for _, user := range r.users {
+		if openID != "" && user.OpenID() == openID {
+			return user, nil
+		}
+	}
+	return nil, response.NewNotFoundError(userErrors.MsgUserNotFound)
+}
+
+func (r *fakeUserRepository) ExistsByOpenID(ctx context.Context, openID string) (bool, error) {
+	if r.existsErr != nil {
+		return false, r.existsErr
+	}
+	_, err := r.FindByOpenID(ctx, openID)
+	return err == nil, nil
+}
+
+func (r *fakeUserRepository) ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error) {
+	if r.existsErr != nil {
+		return false, r.existsErr
+	}
+	_, err := r.FindByPhoneNumber(ctx, phoneNumber)
+	return err == nil, nil
+}
+
+func (r *fakeUserRepository) CreateBatch(ctx context.Context, users []*userEntity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches++
+	if r.createErr != nil {
+		for _, user := range users {
+			if err := r.createErr(user); err != nil {
+				return err
+			}
+		}
+	}
+	for _, user := range users {
+		user.SetID(fmt.Sprintf("u%d", len(r.users)+1))
+		r.users[user.ID()] = user
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) UpdatePassword(ctx context.Context, user *userEntity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.passwordUpdates++
+	return nil
+}
+
+// fakeLoginGuard 不限制登录，记录被清除失败计数的手机号
+type fakeLoginGuard struct {
+	resets []string
+}
+
+func (g *fakeLoginGuard) Check(ctx context.Context, phoneNumber, clientIP string) error { return nil }
+
+func (g *fakeLoginGuard) RecordFailure(ctx context.Context, phoneNumber, clientIP string) error {
+	return nil
+}
+
+func (g *fakeLoginGuard) Reset(ctx context.Context, phoneNumber string) {
+	g.resets = append(g.resets, phoneNumber)
+}
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.RedisClient) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, &redis.RedisClient{Client: client}
+}
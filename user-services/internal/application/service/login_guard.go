@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+	"common/logger"
+	"common/response"
+	"user-services/internal/infrastructure/messaging"
+)
+
+// 登录防护默认配置
+const (
+	defaultLoginMaxFailures   = 10
+	defaultLoginIPMaxFailures = 50
+	defaultLoginDelayAfter    = 3
+	defaultLoginBaseDelay     = 2 * time.Second
+	defaultLoginMaxDelay      = time.Minute
+	defaultLoginLockDuration  = 15 * time.Minute
+	defaultLoginFailureWindow = time.Hour
+)
+
+const (
+	loginFailKeyPrefix = "login:fail:" // 连续失败计数，按维度区分 phone / ip
+	loginLockKeyPrefix = "login:lock:" // 等待或锁定标记，值为 delay / lock
+)
+
+// 登录防护统计维度
+const (
+	loginScopePhone = "phone"
+	loginScopeIP    = "ip"
+)
+
+// 锁定标记的取值
+const (
+	loginLockDelay = "delay" // 递增等待中
+	loginLockFull  = "lock"  // 临时锁定
+)
+
+// LoginGuardInterface 密码登录防暴力破解接口
+type LoginGuardInterface interface {
+	// Check 登录前检查手机号和IP是否处于等待或锁定状态
+	Check(ctx context.Context, phoneNumber, clientIP string) error
+	// RecordFailure 记录一次登录失败，本次失败触发锁定时返回锁定错误
+	RecordFailure(ctx context.Context, phoneNumber, clientIP string) error
+	// Reset 登录成功后清除手机号维度的失败计数
+	Reset(ctx context.Context, phoneNumber string)
+}
+
+// LoginGuard 基于Redis的登录失败计数与锁定
+type LoginGuard struct {
+	redisClient    *redis.RedisClient
+	eventPublisher messaging.EventPublisher
+	cfg            config.LoginGuardConfig
+}
+
+// NewLoginGuard 创建登录防护服务
+func NewLoginGuard(
+	redisClient *redis.RedisClient,
+	eventPublisher messaging.EventPublisher,
+	cfg *config.Config,
+) LoginGuardInterface {
+	guardCfg := cfg.Token.LoginGuard
+	if guardCfg.MaxFailures <= 0 {
+		guardCfg.MaxFailures = defaultLoginMaxFailures
+	}
+	if guardCfg.IPMaxFailures <= 0 {
+		guardCfg.IPMaxFailures = defaultLoginIPMaxFailures
+	}
+	if guardCfg.DelayAfter <= 0 {
+		guardCfg.DelayAfter = defaultLoginDelayAfter
+	}
+	if guardCfg.BaseDelay <= 0 {
+		guardCfg.BaseDelay = defaultLoginBaseDelay
+	}
+	if guardCfg.MaxDelay <= 0 {
+		guardCfg.MaxDelay = defaultLoginMaxDelay
+	}
+	if guardCfg.LockDuration <= 0 {
+		guardCfg.LockDuration = defaultLoginLockDuration
+	}
+	if guardCfg.FailureWindow <= 0 {
+		guardCfg.FailureWindow = defaultLoginFailureWindow
+	}
+
+	return &LoginGuard{
+		redisClient:    redisClient,
+		eventPublisher: eventPublisher,
+		cfg:            guardCfg,
+	}
+}
+
+// Check 登录前检查手机号和IP是否处于等待或锁定状态
+func (g *LoginGuard) Check(ctx context.Context, phoneNumber, clientIP string) error {
+	if err := g.checkScope(ctx, loginScopePhone, phoneNumber); err != nil {
+		return err
+	}
+	if clientIP != "" {
+		return g.checkScope(ctx, loginScopeIP, clientIP)
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败
+// 手机号维度达到 DelayAfter 后按失败次数翻倍等待，手机号或IP达到上限后临时锁定
+func (g *LoginGuard) RecordFailure(ctx context.Context, phoneNumber, clientIP string) error {
+	if err := g.recordScope(ctx, loginScopePhone, phoneNumber, phoneNumber, clientIP); err != nil {
+		return err
+	}
+	if clientIP != "" {
+		return g.recordScope(ctx, loginScopeIP, clientIP, phoneNumber, clientIP)
+	}
+	return nil
+}
+
+// Reset 登录成功后清除手机号维度的失败计数
+// IP维度的计数不清除，否则攻击者用自己的账号穿插一次成功登录即可绕过IP限制，该计数在统计窗口结束后自然过期
+func (g *LoginGuard) Reset(ctx context.Context, phoneNumber string) {
+	if err := g.redisClient.Del(ctx, g.failKey(loginScopePhone, phoneNumber)).Err(); err != nil {
+		logger.Warn(ctx, "Failed to reset login failures", zap.Error(err))
+	}
+}
+
+// checkScope 检查单个维度的等待或锁定标记
+func (g *LoginGuard) checkScope(ctx context.Context, scope, subject string) error {
+	lockKey := g.lockKey(scope, subject)
+
+	pipe := g.redisClient.Pipeline()
+	stateCmd := pipe.Get(ctx, lockKey)
+	ttlCmd := pipe.PTTL(ctx, lockKey)
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return response.NewInternalServerError("登录检查失败", err)
+	}
+
+	switch stateCmd.Val() {
+	case loginLockDelay:
+		return response.NewTooManyRequestsError("登录失败次数过多，请稍后再试").
+			WithRetryAfter(ttlCmd.Val())
+	case loginLockFull:
+		return g.lockedError(scope, ttlCmd.Val())
+	}
+	return nil
+}
+
+// recordScope 累加单个维度的失败次数并按需设置等待或锁定
+func (g *LoginGuard) recordScope(ctx context.Context, scope, subject, phoneNumber, clientIP string) error {
+	failKey := g.failKey(scope, subject)
+
+	// 每次失败都刷新过期时间，统计窗口内无新的失败则计数清零
+	pipe := g.redisClient.TxPipeline()
+	countCmd := pipe.Incr(ctx, failKey)
+	pipe.Expire(ctx, failKey, g.cfg.FailureWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error(ctx, "Failed to record login failure", zap.String("scope", scope), zap.Error(err))
+		return nil
+	}
+	failures := countCmd.Val()
+
+	maxFailures := g.cfg.MaxFailures
+	if scope == loginScopeIP {
+		maxFailures = g.cfg.IPMaxFailures
+	}
+
+	// 达到上限，锁定并清零计数，解锁后重新计数
+	if failures >= int64(maxFailures) {
+		lockedUntil := time.Now().Add(g.cfg.LockDuration)
+		lockPipe := g.redisClient.TxPipeline()
+		lockPipe.Set(ctx, g.lockKey(scope, subject), loginLockFull, g.cfg.LockDuration)
+		lockPipe.Del(ctx, failKey)
+		if _, err := lockPipe.Exec(ctx); err != nil {
+			logger.Error(ctx, "Failed to lock login", zap.String("scope", scope), zap.Error(err))
+			return nil
+		}
+
+		logger.Warn(ctx, "Password login locked",
+			zap.String("scope", scope),
+			zap.String("phone_number", phoneNumber),
+			zap.String("client_ip", clientIP),
+			zap.Int64("failures", failures),
+			zap.Time("locked_until", lockedUntil))
+
+		event := messaging.LoginLockedEvent{
+			Scope:       scope,
+			Subject:     subject,
+			PhoneNumber: phoneNumber,
+			ClientIP:    clientIP,
+			Failures:    failures,
+			LockedUntil: lockedUntil,
+		}
+		if err := g.eventPublisher.PublishLoginLocked(ctx, event); err != nil {
+			logger.Error(ctx, "Failed to publish login locked event", zap.Error(err))
+		}
+
+		return g.lockedError(scope, g.cfg.LockDuration)
+	}
+
+	// 递增等待只作用于手机号，避免同一出口IP下的正常用户被拖慢
+	if scope == loginScopePhone && failures >= int64(g.cfg.DelayAfter) {
+		delay := g.delayFor(failures)
+		if err := g.redisClient.Set(ctx, g.lockKey(scope, subject), loginLockDelay, delay).Err(); err != nil {
+			logger.Error(ctx, "Failed to set login delay", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// delayFor 计算第 failures 次失败后的等待时间，从 BaseDelay 开始翻倍，不超过 MaxDelay
+func (g *LoginGuard) delayFor(failures int64) time.Duration {
+	delay := g.cfg.BaseDelay
+	for i := int64(g.cfg.DelayAfter); i < failures; i++ {
+		delay *= 2
+		if delay >= g.cfg.MaxDelay {
+			return g.cfg.MaxDelay
+		}
+	}
+	return delay
+}
+
+// lockedError 创建锁定错误
+func (g *LoginGuard) lockedError(scope string, retryAfter time.Duration) *response.DomainError {
+	message := "登录失败次数过多，账号已被临时锁定"
+	if scope == loginScopeIP {
+		message = "当前网络登录失败次数过多，请稍后再试"
+	}
+	return response.NewAccountLockedError(message).WithRetryAfter(retryAfter)
+}
+
+func (g *LoginGuard) failKey(scope, subject string) string {
+	return loginFailKeyPrefix + scope + ":" + subject
+}
+
+func (g *LoginGuard) lockKey(scope, subject string) string {
+	return loginLockKeyPrefix + scope + ":" + subject
+}
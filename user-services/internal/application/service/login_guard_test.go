@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/response"
+	"user-services/internal/infrastructure/messaging"
+)
+
+// fakeEventPublisher 记录发布的登录锁定事件
+type fakeEventPublisher struct {
+	messaging.EventPublisher
+	locked []messaging.LoginLockedEvent
+}
+
+func (p *fakeEventPublisher) PublishLoginLocked(ctx context.Context, event messaging.LoginLockedEvent) error {
+	p.locked = append(p.locked, event)
+	return nil
+}
+
+// testLoginGuardConfig 手机号连续失败3次后开始等待、5次锁定，IP连续失败8次锁定
+var testLoginGuardConfig = config.LoginGuardConfig{
+	MaxFailures:   5,
+	IPMaxFailures: 8,
+	DelayAfter:    3,
+	BaseDelay:     2 * time.Second,
+	MaxDelay:      5 * time.Second,
+	LockDuration:  15 * time.Minute,
+	FailureWindow: time.Hour,
+}
+
+func newTestLoginGuard(t *testing.T) (*miniredis.Miniredis, *fakeEventPublisher, LoginGuardInterface) {
+	mr, redisClient := newTestRedis(t)
+	publisher := &fakeEventPublisher{}
+	cfg := &config.Config{}
+	cfg.Token.LoginGuard = testLoginGuardConfig
+	return mr, publisher, NewLoginGuard(redisClient, publisher, cfg)
+}
+
+// assertRetryAfterError 断言限流或锁定错误的类型和建议的重试等待时间
+func assertRetryAfterError(t *testing.T, err error, wantType *response.ErrorType, wantRetryAfter int64) {
+	t.Helper()
+	if wantType == nil {
+		assert.NoError(t, err)
+		return
+	}
+	var domainErr *response.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, *wantType, domainErr.Type)
+	assert.Equal(t, wantRetryAfter, domainErr.RetryAfter())
+}
+
+func errorType(t response.ErrorType) *response.ErrorType { return &t }
+
+func TestLoginGuard_PhoneThreshold(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       int
+		wantRecordErr  *response.ErrorType // 最后一次 RecordFailure 的结果
+		wantCheckErr   *response.ErrorType
+		wantRetryAfter int64
+	}{
+		{name: "below delay threshold", failures: 2},
+		{name: "delay starts", failures: 3, wantCheckErr: errorType(response.ErrorTypeTooManyRequests), wantRetryAfter: 2},
+		{name: "delay doubles", failures: 4, wantCheckErr: errorType(response.ErrorTypeTooManyRequests), wantRetryAfter: 4},
+		{
+			name:           "locked at max failures",
+			failures:       5,
+			wantRecordErr:  errorType(response.ErrorTypeAccountLocked),
+			wantCheckErr:   errorType(response.ErrorTypeAccountLocked),
+			wantRetryAfter: 900,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			_, publisher, guard := newTestLoginGuard(t)
+
+			var err error
+			for i := 0; i < tt.failures; i++ {
+				err = guard.RecordFailure(ctx, "+8613800138000", "10.0.0.1")
+			}
+			assertRetryAfterError(t, err, tt.wantRecordErr, tt.wantRetryAfter)
+			assertRetryAfterError(t, guard.Check(ctx, "+8613800138000", "10.0.0.1"), tt.wantCheckErr, tt.wantRetryAfter)
+
+			// 其他手机号不受影响
+			assert.NoError(t, guard.Check(ctx, "+8613900139000", "10.0.0.1"))
+
+			if tt.wantRecordErr == nil {
+				assert.Empty(t, publisher.locked)
+				return
+			}
+			require.Len(t, publisher.locked, 1)
+			assert.Equal(t, loginScopePhone, publisher.locked[0].Scope)
+			assert.Equal(t, "+8613800138000", publisher.locked[0].Subject)
+			assert.Equal(t, int64(5), publisher.locked[0].Failures)
+		})
+	}
+}
+
+func TestLoginGuard_DelayCappedAtMaxDelay(t *testing.T) {
+	ctx := context.Background()
+	mr, _, guard := newTestLoginGuard(t)
+
+	// 第3次失败等待2秒，第4次4秒，之后不超过 MaxDelay
+	for i := 0; i < 4; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", ""))
+	}
+	mr.FastForward(4 * time.Second)
+	require.NoError(t, guard.Check(ctx, "+8613800138000", ""))
+
+	guardImpl := guard.(*LoginGuard)
+	assert.Equal(t, 5*time.Second, guardImpl.delayFor(5))
+	assert.Equal(t, 5*time.Second, guardImpl.delayFor(9))
+}
+
+func TestLoginGuard_IPThreshold(t *testing.T) {
+	ctx := context.Background()
+	_, publisher, guard := newTestLoginGuard(t)
+
+	// 同一IP下轮换手机号，每个手机号都未达到等待阈值
+	var err error
+	for i := 0; i < testLoginGuardConfig.IPMaxFailures; i++ {
+		phoneNumber := fmt.Sprintf("+861380013800%d", i)
+		require.NoError(t, guard.Check(ctx, phoneNumber, "10.0.0.1"), "IP scope must not delay before the lock")
+		err = guard.RecordFailure(ctx, phoneNumber, "10.0.0.1")
+	}
+	lockedType := errorType(response.ErrorTypeAccountLocked)
+	assertRetryAfterError(t, err, lockedType, 900)
+	assertErrorMessage(t, response.NewAccountLockedError("当前网络登录失败次数过多，请稍后再试"), err)
+
+	// 该IP下任何手机号都被拒绝，同一手机号换IP不受影响
+	assertRetryAfterError(t, guard.Check(ctx, "+8613900139000", "10.0.0.1"), lockedType, 900)
+	assert.NoError(t, guard.Check(ctx, "+8613900139000", "10.0.0.2"))
+	assert.NoError(t, guard.Check(ctx, "+8613800138000", "10.0.0.2"))
+
+	require.Len(t, publisher.locked, 1)
+	assert.Equal(t, loginScopeIP, publisher.locked[0].Scope)
+	assert.Equal(t, "10.0.0.1", publisher.locked[0].Subject)
+}
+
+func TestLoginGuard_LockWindow(t *testing.T) {
+	ctx := context.Background()
+	mr, _, guard := newTestLoginGuard(t)
+
+	for i := 0; i < testLoginGuardConfig.MaxFailures; i++ {
+		_ = guard.RecordFailure(ctx, "+8613800138000", "")
+	}
+
+	// 锁定期内一直拒绝
+	mr.FastForward(testLoginGuardConfig.LockDuration - time.Second)
+	err := guard.Check(ctx, "+8613800138000", "")
+	assertRetryAfterError(t, err, errorType(response.ErrorTypeAccountLocked), 1)
+
+	// 锁定到期后可以登录，且失败计数已在锁定时清零
+	mr.FastForward(2 * time.Second)
+	require.NoError(t, guard.Check(ctx, "+8613800138000", ""))
+	require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", ""))
+	assert.NoError(t, guard.Check(ctx, "+8613800138000", ""))
+}
+
+func TestLoginGuard_FailureWindow(t *testing.T) {
+	ctx := context.Background()
+	mr, _, guard := newTestLoginGuard(t)
+
+	require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", ""))
+	require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", ""))
+
+	// 统计窗口内没有新的失败，计数过期后重新开始
+	mr.FastForward(testLoginGuardConfig.FailureWindow + time.Second)
+	require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", ""))
+	assert.NoError(t, guard.Check(ctx, "+8613800138000", ""))
+}
+
+func TestLoginGuard_ResetClearsPhoneOnly(t *testing.T) {
+	ctx := context.Background()
+	_, _, guard := newTestLoginGuard(t)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", "10.0.0.1"))
+	}
+	guard.Reset(ctx, "+8613800138000")
+
+	// 手机号计数已清零，再失败2次仍未达到等待阈值
+	for i := 0; i < 2; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "+8613800138000", "10.0.0.1"))
+	}
+	require.NoError(t, guard.Check(ctx, "+8613800138000", "10.0.0.1"))
+
+	// IP计数不随登录成功清除，累计4次后再失败4次即被锁定
+	var err error
+	for i := 0; i < 4; i++ {
+		err = guard.RecordFailure(ctx, fmt.Sprintf("+861390013900%d", i), "10.0.0.1")
+	}
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeAccountLocked), "got %v", err)
+	assert.True(t, response.IsErrorType(guard.Check(ctx, "+8613800138000", "10.0.0.1"), response.ErrorTypeAccountLocked))
+}
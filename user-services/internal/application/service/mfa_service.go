@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+	"common/logger"
+	"common/pkg/cryptoutil"
+	"common/pkg/totp"
+	"common/response"
+	"user-services/internal/domain/user/entity"
+	"user-services/internal/domain/user/repository"
+)
+
+// 两步验证默认配置
+const (
+	defaultMFAChallengeTTL  = 5 * time.Minute
+	defaultMFAMaxAttempts   = 5
+	defaultMFAMaxFailures   = 10
+	defaultMFALockDuration  = 15 * time.Minute
+	defaultMFARecoveryCodes = 10
+
+	// recoveryCodeRetries 恢复码被并发修改时重新读取并重试的次数
+	recoveryCodeRetries = 3
+)
+
+const (
+	mfaChallengeKeyPrefix = "mfa:challenge:" // 待完成的两步验证
+	mfaTOTPUsedKeyPrefix  = "mfa:totp:used:" // 已使用的TOTP时间步，防止验证码重放
+	mfaFailKeyPrefix      = "mfa:fail:"      // 用户连续验证失败次数
+	mfaLockKeyPrefix      = "mfa:lock:"      // 用户两步验证锁定标记
+
+	// recoveryCodeAlphabet 恢复码字符集，去掉了易混淆的 0/O、1/I/L
+	recoveryCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	recoveryCodeLength   = 10
+)
+
+// TOTPSetup 绑定身份验证器所需的信息
+type TOTPSetup struct {
+	Secret          string // base32密钥，供无法扫码时手动输入
+	ProvisioningURI string // otpauth:// 链接，客户端渲染为二维码
+}
+
+// MFAChallenge 密码验证通过后待完成的两步验证
+type MFAChallenge struct {
+	Token     string // 一次性的两步验证令牌
+	ExpiresIn int64  // 有效期(秒)
+}
+
+// MFAServiceInterface 两步验证服务接口
+type MFAServiceInterface interface {
+	SetupTOTP(ctx context.Context, userID string) (*TOTPSetup, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID, code string) error
+	CreateChallenge(ctx context.Context, user *entity.User) (*MFAChallenge, error)
+	VerifyChallenge(ctx context.Context, mfaToken, code string) (*entity.User, error)
+}
+
+// MFAService 基于TOTP(RFC 6238)的两步验证服务
+type MFAService struct {
+	userRepo    repository.UserRepository
+	redisClient *redis.RedisClient
+	cipher      *cryptoutil.Cipher
+	issuer      string
+	cfg         config.MFAConfig
+}
+
+// NewMFAService 创建两步验证服务
+func NewMFAService(
+	userRepo repository.UserRepository,
+	redisClient *redis.RedisClient,
+	cfg *config.Config,
+) (MFAServiceInterface, error) {
+	mfaCfg := cfg.MFA
+	if mfaCfg.ChallengeTTL <= 0 {
+		mfaCfg.ChallengeTTL = defaultMFAChallengeTTL
+	}
+	if mfaCfg.MaxAttempts <= 0 {
+		mfaCfg.MaxAttempts = defaultMFAMaxAttempts
+	}
+	if mfaCfg.MaxFailures <= 0 {
+		mfaCfg.MaxFailures = defaultMFAMaxFailures
+	}
+	if mfaCfg.LockDuration <= 0 {
+		mfaCfg.LockDuration = defaultMFALockDuration
+	}
+	if mfaCfg.RecoveryCodes <= 0 {
+		mfaCfg.RecoveryCodes = defaultMFARecoveryCodes
+	}
+
+	encryptionKey := mfaCfg.EncryptionKey
+	if encryptionKey == "" {
+		encryptionKey = cfg.System.SecretKey
+	}
+	cipher, err := cryptoutil.NewCipherFromSecret(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := mfaCfg.Issuer
+	if issuer == "" {
+		issuer = cfg.System.ServerName
+	}
+
+	return &MFAService{
+		userRepo:    userRepo,
+		redisClient: redisClient,
+		cipher:      cipher,
+		issuer:      issuer,
+		cfg:         mfaCfg,
+	}, nil
+}
+
+// SetupTOTP 生成新的TOTP密钥，确认前不会启用
+// 重复调用会覆盖尚未确认的密钥
+func (s *MFAService) SetupTOTP(ctx context.Context, userID string) (*TOTPSetup, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled() {
+		return nil, response.NewBusinessRuleViolationError("已启用两步验证，请先关闭后再重新绑定")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, response.NewInternalServerError("生成两步验证密钥失败", err)
+	}
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, response.NewInternalServerError("生成两步验证密钥失败", err)
+	}
+
+	user.SetupTOTP(encrypted)
+	if err := s.userRepo.UpdateTOTP(ctx, user); err != nil {
+		return nil, err
+	}
+
+	account := user.PhoneNumber()
+	if account == "" {
+		account = user.Name()
+	}
+	return &TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, account, secret),
+	}, nil
+}
+
+// ConfirmTOTP 使用身份验证器生成的第一个验证码确认绑定并启用两步验证
+// @return []string 恢复码明文，仅在此时返回一次
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled() {
+		return nil, response.NewBusinessRuleViolationError("已启用两步验证")
+	}
+	if user.TOTPSecret() == "" {
+		return nil, response.NewBusinessRuleViolationError("请先获取两步验证密钥")
+	}
+
+	ok, err := s.verifyTOTP(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, response.NewValidationError("验证码错误")
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.cfg.RecoveryCodes)
+	if err != nil {
+		return nil, response.NewInternalServerError("生成恢复码失败", err)
+	}
+
+	user.EnableTOTP(hashes)
+	if err := s.userRepo.UpdateTOTP(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "TOTP enabled", zap.String("user_id", userID))
+	return codes, nil
+}
+
+// DisableTOTP 关闭两步验证，需要提供当前的验证码或恢复码
+func (s *MFAService) DisableTOTP(ctx context.Context, userID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled() {
+		return response.NewBusinessRuleViolationError("未启用两步验证")
+	}
+	if err := s.checkLocked(ctx, userID); err != nil {
+		return err
+	}
+
+	ok, err := s.verifyCode(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := s.recordFailure(ctx, userID); err != nil {
+			return err
+		}
+		return response.NewValidationError("验证码错误")
+	}
+	s.resetFailures(ctx, userID)
+
+	user.DisableTOTP()
+	if err := s.userRepo.UpdateTOTP(ctx, user); err != nil {
+		return err
+	}
+
+	logger.Info(ctx, "TOTP disabled", zap.String("user_id", userID))
+	return nil
+}
+
+// CreateChallenge 为已通过密码验证的用户创建两步验证令牌
+func (s *MFAService) CreateChallenge(ctx context.Context, user *entity.User) (*MFAChallenge, error) {
+	token, err := newRandomToken()
+	if err != nil {
+		return nil, response.NewInternalServerError("创建两步验证失败", err)
+	}
+
+	key := mfaChallengeKeyPrefix + hashCode(token)
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", user.ID(), "attempts", 0)
+	pipe.Expire(ctx, key, s.cfg.ChallengeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, response.NewInternalServerError("创建两步验证失败", err)
+	}
+
+	return &MFAChallenge{
+		Token:     token,
+		ExpiresIn: int64(s.cfg.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// VerifyChallenge 校验两步验证令牌和验证码(TOTP或恢复码)，通过后令牌立即失效
+// 超过最大尝试次数后令牌作废，需要重新使用密码登录；重新登录不会清零用户的失败次数，达到上限后锁定
+func (s *MFAService) VerifyChallenge(ctx context.Context, mfaToken, code string) (*entity.User, error) {
+	key := mfaChallengeKeyPrefix + hashCode(mfaToken)
+
+	pipe := s.redisClient.TxPipeline()
+	attemptsCmd := pipe.HIncrBy(ctx, key, "attempts", 1)
+	userIDCmd := pipe.HGet(ctx, key, "user_id")
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, response.NewInternalServerError("两步验证失败", err)
+	}
+
+	userID := userIDCmd.Val()
+	if userID == "" {
+		// 令牌不存在或已过期，清理 HIncrBy 新建的无过期时间的键
+		s.redisClient.Del(ctx, key)
+		return nil, response.NewUnauthorizedError("两步验证已过期，请重新登录")
+	}
+	if attemptsCmd.Val() > int64(s.cfg.MaxAttempts) {
+		s.redisClient.Del(ctx, key)
+		return nil, response.NewUnauthorizedError("验证次数过多，请重新登录")
+	}
+
+	if err := s.checkLocked(ctx, userID); err != nil {
+		s.redisClient.Del(ctx, key)
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled() {
+		// 创建令牌后用户关闭了两步验证，要求重新登录
+		s.redisClient.Del(ctx, key)
+		return nil, response.NewUnauthorizedError("两步验证已过期，请重新登录")
+	}
+
+	ok, err := s.verifyCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.recordFailure(ctx, userID); err != nil {
+			s.redisClient.Del(ctx, key)
+			return nil, err
+		}
+		return nil, response.NewUnauthorizedError("验证码错误").
+			WithContext("remaining_attempts", int64(s.cfg.MaxAttempts)-attemptsCmd.Val())
+	}
+
+	s.redisClient.Del(ctx, key)
+	s.resetFailures(ctx, userID)
+	return user, nil
+}
+
+// checkLocked 检查用户的两步验证是否因连续失败被锁定
+func (s *MFAService) checkLocked(ctx context.Context, userID string) error {
+	ttl, err := s.redisClient.PTTL(ctx, mfaLockKeyPrefix+userID).Result()
+	if err != nil {
+		return response.NewInternalServerError("两步验证失败", err)
+	}
+	// 键不存在时PTTL返回负值
+	if ttl > 0 {
+		return s.lockedError(ttl)
+	}
+	return nil
+}
+
+// recordFailure 累加用户的验证失败次数，达到上限时锁定并返回锁定错误
+// 失败次数按用户统计，每次登录创建新的两步验证令牌不会清零
+func (s *MFAService) recordFailure(ctx context.Context, userID string) error {
+	failKey := mfaFailKeyPrefix + userID
+
+	pipe := s.redisClient.TxPipeline()
+	countCmd := pipe.Incr(ctx, failKey)
+	pipe.Expire(ctx, failKey, s.cfg.LockDuration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return response.NewInternalServerError("两步验证失败", err)
+	}
+	if countCmd.Val() < int64(s.cfg.MaxFailures) {
+		return nil
+	}
+
+	lockPipe := s.redisClient.TxPipeline()
+	lockPipe.Set(ctx, mfaLockKeyPrefix+userID, 1, s.cfg.LockDuration)
+	lockPipe.Del(ctx, failKey)
+	if _, err := lockPipe.Exec(ctx); err != nil {
+		return response.NewInternalServerError("两步验证失败", err)
+	}
+
+	logger.Warn(ctx, "MFA locked",
+		zap.String("user_id", userID),
+		zap.Int64("failures", countCmd.Val()),
+		zap.Duration("lock_duration", s.cfg.LockDuration))
+	return s.lockedError(s.cfg.LockDuration)
+}
+
+// resetFailures 验证通过后清除用户的失败次数
+func (s *MFAService) resetFailures(ctx context.Context, userID string) {
+	if err := s.redisClient.Del(ctx, mfaFailKeyPrefix+userID).Err(); err != nil {
+		logger.Warn(ctx, "Failed to reset MFA failures", zap.Error(err))
+	}
+}
+
+// lockedError 创建两步验证锁定错误
+func (s *MFAService) lockedError(retryAfter time.Duration) *response.DomainError {
+	return response.NewAccountLockedError("两步验证失败次数过多，请稍后再试").WithRetryAfter(retryAfter)
+}
+
+// verifyCode 校验TOTP验证码，6位数字以外的输入按恢复码处理
+func (s *MFAService) verifyCode(ctx context.Context, user *entity.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		return s.verifyTOTP(ctx, user, code)
+	}
+	return s.useRecoveryCode(ctx, user, code)
+}
+
+// verifyTOTP 校验TOTP验证码，同一时间步的验证码只能使用一次
+func (s *MFAService) verifyTOTP(ctx context.Context, user *entity.User, code string) (bool, error) {
+	secret, err := s.cipher.Decrypt(user.TOTPSecret())
+	if err != nil {
+		return false, response.NewInternalServerError("读取两步验证密钥失败", err)
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	// 验证码在允许的时钟偏差范围内均有效，标记保留到该范围结束
+	usedKey := mfaTOTPUsedKeyPrefix + user.ID() + ":" + strconv.FormatInt(step, 10)
+	first, err := s.redisClient.SetNX(ctx, usedKey, 1, (2*totp.Skew+1)*totp.Period).Result()
+	if err != nil {
+		return false, response.NewInternalServerError("两步验证失败", err)
+	}
+	return first, nil
+}
+
+// useRecoveryCode 消耗一个恢复码
+// 以读取到的恢复码未被修改为条件写入，同一恢复码被并发使用时只有一个请求成功
+func (s *MFAService) useRecoveryCode(ctx context.Context, user *entity.User, code string) (bool, error) {
+	codeHash := hashRecoveryCode(code)
+	for i := 0; i < recoveryCodeRetries; i++ {
+		expected := user.RecoveryCodeHashes()
+		if !user.UseRecoveryCode(codeHash) {
+			return false, nil
+		}
+
+		updated, err := s.userRepo.UpdateRecoveryCodes(ctx, user, expected)
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			logger.Info(ctx, "Recovery code used",
+				zap.String("user_id", user.ID()),
+				zap.Int("remaining", len(user.RecoveryCodeHashes())))
+			return true, nil
+		}
+
+		// 其间有其他恢复码被使用，重新读取后再判断该恢复码是否仍然可用
+		if user, err = s.userRepo.GetByID(ctx, user.ID()); err != nil {
+			return false, err
+		}
+		if !user.TOTPEnabled() {
+			return false, nil
+		}
+	}
+	return false, response.NewConcurrencyConflictError("恢复码正在被使用，请重试")
+}
+
+// generateRecoveryCodes 生成恢复码及其哈希，格式为 XXXXX-XXXXX
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < count; i++ {
+		chars := make([]byte, recoveryCodeLength)
+		for j := range chars {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			chars[j] = recoveryCodeAlphabet[n.Int64()]
+		}
+		code := string(chars[:recoveryCodeLength/2]) + "-" + string(chars[recoveryCodeLength/2:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// newRandomToken 生成32字节的URL安全随机令牌，用于两步验证、重置密码等一次性令牌
+func newRandomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRecoveryCode 计算恢复码哈希，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// isDigits 是否全部为数字
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"common/config"
+	"common/pkg/sms"
+	"user-services/internal/domain/user/entity"
+)
+
+// PasswordResetNotifier 找回密码通知接口
+// 默认通过短信发送，接入邮件等其他渠道时实现该接口并替换DI中的 NewPasswordResetNotifier 即可
+type PasswordResetNotifier interface {
+	// NotifyPasswordReset 将重置令牌发送给用户
+	NotifyPasswordReset(ctx context.Context, user *entity.User, token string, expiresIn time.Duration) error
+}
+
+// SMSPasswordResetNotifier 通过短信发送重置链接或令牌
+type SMSPasswordResetNotifier struct {
+	sender   sms.SMSSender
+	resetURL string
+}
+
+// NewPasswordResetNotifier 创建默认的短信找回密码通知
+func NewPasswordResetNotifier(sender sms.SMSSender, cfg *config.Config) PasswordResetNotifier {
+	return &SMSPasswordResetNotifier{
+		sender:   sender,
+		resetURL: cfg.Password.Reset.URL,
+	}
+}
+
+// NotifyPasswordReset 发送重置短信，配置了重置页面地址时发送链接，否则发送令牌
+func (n *SMSPasswordResetNotifier) NotifyPasswordReset(ctx context.Context, user *entity.User, token string, expiresIn time.Duration) error {
+	minutes := int(expiresIn.Minutes())
+
+	var content string
+	if n.resetURL != "" {
+		link, err := appendTokenParam(n.resetURL, token)
+		if err != nil {
+			return err
+		}
+		content = fmt.Sprintf("您正在重置密码，请在%d分钟内打开链接完成操作：%s。如非本人操作请忽略。", minutes, link)
+	} else {
+		content = fmt.Sprintf("您正在重置密码，重置令牌为%s，%d分钟内有效。如非本人操作请忽略。", token, minutes)
+	}
+
+	return n.sender.Send(ctx, user.PhoneNumber(), content)
+}
+
+// appendTokenParam 在重置页面地址上附加 token 查询参数
+func appendTokenParam(rawURL, token string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid password reset url: %w", err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
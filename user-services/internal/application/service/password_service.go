@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+	"common/logger"
+	"common/response"
+	"user-services/internal/domain/user/repository"
+	domainservice "user-services/internal/domain/user/service"
+)
+
+// 找回密码默认配置
+const (
+	defaultResetTokenTTL     = 30 * time.Minute
+	defaultResetSendInterval = time.Minute
+)
+
+const (
+	passwordResetTokenKeyPrefix    = "password:reset:token:"    // 令牌哈希 -> 用户ID
+	passwordResetUserKeyPrefix     = "password:reset:user:"     // 用户ID -> 当前有效的令牌哈希
+	passwordResetIntervalKeyPrefix = "password:reset:interval:" // 手机号申请间隔
+)
+
+// PasswordServiceInterface 密码管理服务接口
+type PasswordServiceInterface interface {
+	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
+	RequestReset(ctx context.Context, phoneNumber string) error
+	ConfirmReset(ctx context.Context, token, newPassword string) error
+}
+
+// PasswordService 修改密码和找回密码服务
+// 密码变更后吊销用户的全部令牌，所有设备需要重新登录
+type PasswordService struct {
+	userRepo    repository.UserRepository
+	userService *domainservice.UserDomainService
+	authService AuthServiceInterface
+	loginGuard  LoginGuardInterface
+	notifier    PasswordResetNotifier
+	redisClient *redis.RedisClient
+	cfg         config.PasswordResetConfig
+}
+
+// NewPasswordService 创建密码管理服务
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	userService *domainservice.UserDomainService,
+	authService AuthServiceInterface,
+	loginGuard LoginGuardInterface,
+	notifier PasswordResetNotifier,
+	redisClient *redis.RedisClient,
+	cfg *config.Config,
+) PasswordServiceInterface {
+	resetCfg := cfg.Password.Reset
+	if resetCfg.TokenTTL <= 0 {
+		resetCfg.TokenTTL = defaultResetTokenTTL
+	}
+	if resetCfg.SendInterval <= 0 {
+		resetCfg.SendInterval = defaultResetSendInterval
+	}
+
+	return &PasswordService{
+		userRepo:    userRepo,
+		userService: userService,
+		authService: authService,
+		loginGuard:  loginGuard,
+		notifier:    notifier,
+		redisClient: redisClient,
+		cfg:         resetCfg,
+	}
+}
+
+// ChangePassword 校验原密码后修改密码
+func (s *PasswordService) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userService.VerifyPassword(user, oldPassword); err != nil {
+		return err
+	}
+	if err := s.userService.ChangePassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	return s.afterPasswordChanged(ctx, user.ID(), user.PhoneNumber())
+}
+
+// RequestReset 申请重置密码，向手机号对应的用户发送重置令牌
+// 手机号未注册时同样返回成功，避免通过该接口探测手机号是否已注册
+func (s *PasswordService) RequestReset(ctx context.Context, phoneNumber string) error {
+	phoneNumber, err := s.userService.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.redisClient.SetNX(ctx, passwordResetIntervalKeyPrefix+phoneNumber, 1, s.cfg.SendInterval).Result()
+	if err != nil {
+		return response.NewInternalServerError("申请重置密码失败", err)
+	}
+	if !ok {
+		ttl, _ := s.redisClient.TTL(ctx, passwordResetIntervalKeyPrefix+phoneNumber).Result()
+		return tooManyRequests("申请过于频繁，请稍后再试", ttl)
+	}
+
+	user, err := s.userRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if response.IsErrorType(err, response.ErrorTypeNotFound) {
+			logger.Info(ctx, "Password reset requested for unknown phone number")
+			return nil
+		}
+		return err
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return response.NewInternalServerError("申请重置密码失败", err)
+	}
+	tokenHash := hashCode(token)
+
+	// 每个用户只保留最近一次申请的令牌
+	userKey := passwordResetUserKeyPrefix + user.ID()
+	previous, err := s.redisClient.Get(ctx, userKey).Result()
+	if err != nil && err != goredis.Nil {
+		return response.NewInternalServerError("申请重置密码失败", err)
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, passwordResetTokenKeyPrefix+previous)
+	}
+	pipe.Set(ctx, passwordResetTokenKeyPrefix+tokenHash, user.ID(), s.cfg.TokenTTL)
+	pipe.Set(ctx, userKey, tokenHash, s.cfg.TokenTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return response.NewInternalServerError("申请重置密码失败", err)
+	}
+
+	if err := s.notifier.NotifyPasswordReset(ctx, user, token, s.cfg.TokenTTL); err != nil {
+		return response.NewInternalServerError("发送重置通知失败", err)
+	}
+
+	logger.Info(ctx, "Password reset token issued", zap.String("user_id", user.ID()))
+	return nil
+}
+
+// ConfirmReset 使用重置令牌设置新密码
+// 新密码不符合策略时令牌不会失效，用户可以修改后重试；校验通过后先原子地取出并删除令牌再修改密码，同一令牌只能成功使用一次
+func (s *PasswordService) ConfirmReset(ctx context.Context, token, newPassword string) error {
+	tokenKey := passwordResetTokenKeyPrefix + hashCode(token)
+	userID, err := s.redisClient.Get(ctx, tokenKey).Result()
+	if err == goredis.Nil {
+		return response.NewValidationError("重置令牌无效或已过期")
+	}
+	if err != nil {
+		return response.NewInternalServerError("重置密码失败", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.userService.ValidatePasswordChange(user, newPassword); err != nil {
+		return err
+	}
+
+	// 并发使用同一令牌的请求中只有一个能取到令牌
+	consumedBy, err := s.redisClient.GetDel(ctx, tokenKey).Result()
+	if err == goredis.Nil || (err == nil && consumedBy != userID) {
+		return response.NewValidationError("重置令牌无效或已过期")
+	}
+	if err != nil {
+		return response.NewInternalServerError("重置密码失败", err)
+	}
+
+	if err := s.userService.ChangePassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	return s.afterPasswordChanged(ctx, user.ID(), user.PhoneNumber())
+}
+
+// afterPasswordChanged 清除未使用的重置令牌和登录失败计数，并吊销用户的全部令牌
+func (s *PasswordService) afterPasswordChanged(ctx context.Context, userID, phoneNumber string) error {
+	userKey := passwordResetUserKeyPrefix + userID
+	if tokenHash, err := s.redisClient.Get(ctx, userKey).Result(); err == nil {
+		s.redisClient.Del(ctx, passwordResetTokenKeyPrefix+tokenHash, userKey)
+	}
+	if phoneNumber != "" {
+		s.loginGuard.Reset(ctx, phoneNumber)
+	}
+
+	logger.Info(ctx, "Password changed", zap.String("user_id", userID))
+	return s.authService.RevokeUserTokens(ctx, userID)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"common/config"
+	"common/response"
+	userEntity "user-services/internal/domain/user/entity"
+	userErrors "user-services/internal/domain/user/errors"
+	domainservice "user-services/internal/domain/user/service"
+	"user-services/internal/domain/user/validator"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// fakeAuthService 记录被吊销全部令牌的用户
+type fakeAuthService struct {
+	AuthServiceInterface
+	mu      sync.Mutex
+	revoked []string
+}
+
+func (s *fakeAuthService) RevokeUserTokens(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+// fakeResetNotifier 记录发给每个用户的最新重置令牌
+type fakeResetNotifier struct {
+	tokens map[string]string
+}
+
+func (n *fakeResetNotifier) NotifyPasswordReset(ctx context.Context, user *userEntity.User, token string, expiresIn time.Duration) error {
+	n.tokens[user.ID()] = token
+	return nil
+}
+
+// testPasswordPolicy 至少8位，包含字母和数字，不能与最近3次的密码相同
+var testPasswordPolicy = validator.PasswordPolicy{
+	MinLength:     8,
+	MaxLength:     72,
+	RequireLetter: true,
+	RequireDigit:  true,
+	HistorySize:   3,
+}
+
+type passwordServiceFixture struct {
+	svc      PasswordServiceInterface
+	mr       *miniredis.Miniredis
+	user     *userEntity.User
+	repo     *fakeUserRepository
+	auth     *fakeAuthService
+	guard    *fakeLoginGuard
+	notifier *fakeResetNotifier
+}
+
+// newPasswordServiceFixture 创建密码服务，用户依次使用过 password1、password2，当前密码为 password3
+func newPasswordServiceFixture(t *testing.T) *passwordServiceFixture {
+	user := userEntity.NewUser("", "张三", "+8613800138000", "", valueobject.GenderMale.Int())
+	user.SetID("u1")
+	for _, password := range []string{"password1", "password2", "password3"} {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+		user.ChangePassword(string(hash), testPasswordPolicy.HistorySize)
+	}
+
+	repo := newFakeUserRepository(user)
+	userService := domainservice.NewUserDomainService(repo,
+		validator.NewUserValidator(repo, testPasswordPolicy, validator.PhonePolicy{DefaultRegion: "CN"}), testPasswordPolicy)
+	mr, redisClient := newTestRedis(t)
+
+	f := &passwordServiceFixture{
+		mr:       mr,
+		user:     user,
+		repo:     repo,
+		auth:     &fakeAuthService{},
+		guard:    &fakeLoginGuard{},
+		notifier: &fakeResetNotifier{tokens: make(map[string]string)},
+	}
+	f.svc = NewPasswordService(repo, userService, f.auth, f.guard, f.notifier, redisClient, &config.Config{})
+	return f
+}
+
+// assertErrorMessage 断言返回的领域错误，领域错误附加上下文后是新实例，按消息比较
+func assertErrorMessage(t *testing.T, want *response.DomainError, err error) {
+	t.Helper()
+	var domainErr *response.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, want.Message, domainErr.Message)
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldPassword string
+		newPassword string
+		wantErr     *response.DomainError
+	}{
+		{name: "changed", oldPassword: "password3", newPassword: "password4"},
+		{name: "wrong old password", oldPassword: "password2", newPassword: "password4", wantErr: userErrors.ErrPasswordIncorrect},
+		{name: "too short", oldPassword: "password3", newPassword: "pass4", wantErr: userErrors.NewPasswordTooShortError(8)},
+		{name: "missing digit", oldPassword: "password3", newPassword: "passwordfour", wantErr: userErrors.NewPasswordTooWeakError([]string{"字母", "数字"})},
+		{name: "same as current", oldPassword: "password3", newPassword: "password3", wantErr: userErrors.NewPasswordReusedError(3)},
+		{name: "reuses recent password", oldPassword: "password3", newPassword: "password1", wantErr: userErrors.NewPasswordReusedError(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordServiceFixture(t)
+
+			err := f.svc.ChangePassword(context.Background(), "u1", tt.oldPassword, tt.newPassword)
+			if tt.wantErr != nil {
+				assertErrorMessage(t, tt.wantErr, err)
+				assert.Zero(t, f.repo.passwordUpdates)
+				assert.Empty(t, f.auth.revoked)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, f.repo.passwordUpdates)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(f.user.Password()), []byte(tt.newPassword)))
+			assert.Equal(t, []string{"u1"}, f.auth.revoked)
+			assert.Equal(t, []string{"+8613800138000"}, f.guard.resets)
+		})
+	}
+}
+
+func TestPasswordService_ConfirmReset(t *testing.T) {
+	ctx := context.Background()
+	f := newPasswordServiceFixture(t)
+
+	require.NoError(t, f.svc.RequestReset(ctx, "138 0013 8000"))
+	token := f.notifier.tokens["u1"]
+	require.NotEmpty(t, token)
+
+	// 新密码不符合策略时令牌不失效
+	err := f.svc.ConfirmReset(ctx, token, "password2")
+	assertErrorMessage(t, userErrors.NewPasswordReusedError(3), err)
+	err = f.svc.ConfirmReset(ctx, token, "short1")
+	assertErrorMessage(t, userErrors.NewPasswordTooShortError(8), err)
+	assert.Zero(t, f.repo.passwordUpdates)
+
+	require.NoError(t, f.svc.ConfirmReset(ctx, token, "password4"))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(f.user.Password()), []byte("password4")))
+	assert.Equal(t, []string{"u1"}, f.auth.revoked)
+
+	// 令牌只能使用一次
+	err = f.svc.ConfirmReset(ctx, token, "password5")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeValidationFailed), "got %v", err)
+	assert.Equal(t, 1, f.repo.passwordUpdates)
+
+	err = f.svc.ConfirmReset(ctx, "unknown", "password5")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeValidationFailed), "got %v", err)
+}
+
+func TestPasswordService_ConfirmResetConcurrent(t *testing.T) {
+	ctx := context.Background()
+	f := newPasswordServiceFixture(t)
+
+	require.NoError(t, f.svc.RequestReset(ctx, "13800138000"))
+	token := f.notifier.tokens["u1"]
+
+	const requests = 5
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.svc.ConfirmReset(ctx, token, "password4")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, response.IsErrorType(err, response.ErrorTypeValidationFailed), "got %v", err)
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, f.repo.passwordUpdates)
+	assert.Equal(t, []string{"u1"}, f.auth.revoked)
+}
+
+func TestPasswordService_RequestResetReplacesToken(t *testing.T) {
+	ctx := context.Background()
+	f := newPasswordServiceFixture(t)
+
+	require.NoError(t, f.svc.RequestReset(ctx, "13800138000"))
+	first := f.notifier.tokens["u1"]
+
+	// 申请间隔内再次申请被拒绝
+	err := f.svc.RequestReset(ctx, "13800138000")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeTooManyRequests), "got %v", err)
+
+	// 未注册的手机号同样返回成功，但不发送令牌
+	require.NoError(t, f.svc.RequestReset(ctx, "13900139000"))
+	assert.Len(t, f.notifier.tokens, 1)
+
+	// 间隔过后重新申请，旧令牌随之失效
+	f.mr.FastForward(time.Minute + time.Second)
+	require.NoError(t, f.svc.RequestReset(ctx, "13800138000"))
+	second := f.notifier.tokens["u1"]
+	require.NotEqual(t, first, second)
+
+	err = f.svc.ConfirmReset(ctx, first, "password4")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeValidationFailed), "got %v", err)
+	require.NoError(t, f.svc.ConfirmReset(ctx, second, "password4"))
+}
@@ -2,43 +2,470 @@ package service
 
 import (
 	"context"
+	"strings"
 
 	"github.com/casbin/casbin/v2"
+	"go.uber.org/zap"
+
+	"common/logger"
+	pkgcasbin "common/pkg/casbin"
+	"common/response"
+	auditentity "user-services/internal/domain/audit/entity"
 )
 
+// Policy 权限策略，对应casbin中的 p 规则
+type Policy struct {
+	Subject string // 用户ID或角色
+	Domain  string // 所属租户，"*" 表示全部租户
+	Object  string // 资源路径，支持keyMatch通配
+	Action  string // HTTP方法，"*" 表示全部
+}
+
+// Role 租户内的角色及直接拥有该角色的用户(或子角色)
+type Role struct {
+	Name  string
+	Users []string
+}
+
+// UserPermissions 用户在某个租户内的有效权限
+type UserPermissions struct {
+	UserID        string
+	Domain        string
+	Roles         []string // 直接分配的角色
+	ImplicitRoles []string // 包含通过角色继承获得的全部角色
+	Permissions   []Policy // 用户自身及全部角色的策略
+}
+
+// PolicyDiff 策略文件与当前策略的差异
+type PolicyDiff struct {
+	AddedPolicies   []pkgcasbin.PolicyRule
+	RemovedPolicies []pkgcasbin.PolicyRule
+	AddedRoles      []pkgcasbin.RoleRule
+	RemovedRoles    []pkgcasbin.RoleRule
+}
+
+// Empty 是否没有差异
+func (d *PolicyDiff) Empty() bool {
+	return len(d.AddedPolicies) == 0 && len(d.RemovedPolicies) == 0 && len(d.AddedRoles) == 0 && len(d.RemovedRoles) == 0
+}
+
 // PermissionServiceInterface 权限服务接口
+// 角色和策略均按租户(Casbin域)隔离，dom 为 "*" 的规则对全部租户生效
 type PermissionServiceInterface interface {
-	// Enforce 检查权限
-	Enforce(ctx context.Context, sub, obj, act string) (bool, error)
-	// AddPolicy 添加策略
-	AddPolicy(ctx context.Context, sub, obj, act string) (bool, error)
-	// AddRoleForUser 为用户添加角色
-	AddRoleForUser(ctx context.Context, user, role string) (bool, error)
+	// Enforce 检查用户在租户内的权限
+	Enforce(ctx context.Context, sub, dom, obj, act string) (bool, error)
+	// EnforceWithOwner 检查权限时携带资源所有者，主体为 $owner 的策略放行所有者本人
+	EnforceWithOwner(ctx context.Context, sub, dom, obj, act, owner string) (bool, error)
+	// ListPolicies 查询租户内的策略，sub/obj/act 为空表示不过滤该字段
+	ListPolicies(ctx context.Context, dom, sub, obj, act string) ([]Policy, error)
+	// AddPolicy 添加策略，策略已存在时返回false
+	AddPolicy(ctx context.Context, sub, dom, obj, act string) (bool, error)
+	// RemovePolicy 删除策略，策略不存在时返回false
+	RemovePolicy(ctx context.Context, sub, dom, obj, act string) (bool, error)
+	// ListRoles 列出租户内的角色及其成员
+	ListRoles(ctx context.Context, dom string) ([]Role, error)
+	// AddRoleForUser 在租户内为用户添加角色，已拥有该角色时返回false
+	AddRoleForUser(ctx context.Context, user, role, dom string) (bool, error)
+	// DeleteRoleForUser 撤销用户在租户内的角色，未拥有该角色时返回false
+	DeleteRoleForUser(ctx context.Context, user, role, dom string) (bool, error)
+	// GetUserPermissions 获取用户在租户内的有效权限，包含通过角色继承获得的权限
+	GetUserPermissions(ctx context.Context, user, dom string) (*UserPermissions, error)
+	// UncoveredRoutes 找出没有任何策略能放行的路由，用于启动时检查授权配置
+	UncoveredRoutes(ctx context.Context, routes []pkgcasbin.Route) ([]pkgcasbin.Route, error)
+	// ExportPolicies 导出全部租户的策略和角色分配
+	ExportPolicies(ctx context.Context) (*pkgcasbin.PolicyFile, error)
+	// ApplyPolicies 以策略文件为准同步全部租户的策略和角色分配，dryRun 时只计算差异
+	ApplyPolicies(ctx context.Context, file *pkgcasbin.PolicyFile, dryRun bool) (*PolicyDiff, error)
 }
 
 // PermissionService 权限服务
+// 策略和角色的每次变更都会写入审计日志，审计写入失败时撤销本次变更
 type PermissionService struct {
-	enforcer *casbin.SyncedCachedEnforcer
+	enforcer     *casbin.SyncedCachedEnforcer
+	auditService AuditServiceInterface
 }
 
 // NewPermissionService 创建权限服务
-func NewPermissionService(enforcer *casbin.SyncedCachedEnforcer) PermissionServiceInterface {
+func NewPermissionService(enforcer *casbin.SyncedCachedEnforcer, auditService AuditServiceInterface) PermissionServiceInterface {
 	return &PermissionService{
-		enforcer: enforcer,
+		enforcer:     enforcer,
+		auditService: auditService,
 	}
 }
 
-// Enforce 检查权限
-func (s *PermissionService) Enforce(ctx context.Context, sub, obj, act string) (bool, error) {
-	return s.enforcer.Enforce(sub, obj, act)
+// Enforce 检查用户在租户内的权限
+func (s *PermissionService) Enforce(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+	return s.enforcer.Enforce(sub, dom, obj, act)
+}
+
+// EnforceWithOwner 检查权限时携带资源所有者
+func (s *PermissionService) EnforceWithOwner(ctx context.Context, sub, dom, obj, act, owner string) (bool, error) {
+	return pkgcasbin.EnforceWithOwner(s.enforcer, sub, dom, obj, act, owner)
+}
+
+// ListPolicies 查询租户内的策略
+func (s *PermissionService) ListPolicies(ctx context.Context, dom, sub, obj, act string) ([]Policy, error) {
+	if strings.TrimSpace(dom) == "" {
+		return nil, response.NewValidationError("租户不能为空")
+	}
+	rules, err := s.enforcer.GetFilteredPolicy(0, sub, dom, obj, act)
+	if err != nil {
+		return nil, response.NewInternalServerError("查询权限策略失败", err)
+	}
+	return toPolicies(rules), nil
 }
 
 // AddPolicy 添加策略
-func (s *PermissionService) AddPolicy(ctx context.Context, sub, obj, act string) (bool, error) {
-	return s.enforcer.AddPolicy(sub, obj, act)
+func (s *PermissionService) AddPolicy(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+	if err := validatePolicy(sub, dom, obj, act); err != nil {
+		return false, err
+	}
+
+	added, err := s.enforcer.AddPolicy(sub, dom, obj, act)
+	if err != nil {
+		return false, response.NewInternalServerError("添加权限策略失败", err)
+	}
+	if !added {
+		return false, nil
+	}
+
+	if err := s.audit(ctx, dom, auditentity.ActionPolicyAdd, sub, policyDetail(sub, dom, obj, act), func() error {
+		_, err := s.enforcer.RemovePolicy(sub, dom, obj, act)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemovePolicy 删除策略
+func (s *PermissionService) RemovePolicy(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+	if err := validatePolicy(sub, dom, obj, act); err != nil {
+		return false, err
+	}
+
+	removed, err := s.enforcer.RemovePolicy(sub, dom, obj, act)
+	if err != nil {
+		return false, response.NewInternalServerError("删除权限策略失败", err)
+	}
+	if !removed {
+		return false, nil
+	}
+
+	if err := s.audit(ctx, dom, auditentity.ActionPolicyRemove, sub, policyDetail(sub, dom, obj, act), func() error {
+		_, err := s.enforcer.AddPolicy(sub, dom, obj, act)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListRoles 列出租户内的角色及其成员，只包含分配在该租户下的角色
+func (s *PermissionService) ListRoles(ctx context.Context, dom string) ([]Role, error) {
+	if strings.TrimSpace(dom) == "" {
+		return nil, response.NewValidationError("租户不能为空")
+	}
+	rules, err := s.enforcer.GetFilteredGroupingPolicy(2, dom)
+	if err != nil {
+		return nil, response.NewInternalServerError("查询角色失败", err)
+	}
+
+	roles := make([]Role, 0)
+	index := make(map[string]int)
+	for _, rule := range rules {
+		if len(rule) < 2 {
+			continue
+		}
+		user, role := rule[0], rule[1]
+		i, ok := index[role]
+		if !ok {
+			i = len(roles)
+			index[role] = i
+			roles = append(roles, Role{Name: role})
+		}
+		roles[i].Users = append(roles[i].Users, user)
+	}
+	return roles, nil
+}
+
+// AddRoleForUser 在租户内为用户添加角色
+func (s *PermissionService) AddRoleForUser(ctx context.Context, user, role, dom string) (bool, error) {
+	if err := validateRoleAssignment(user, role, dom); err != nil {
+		return false, err
+	}
+
+	added, err := s.enforcer.AddRoleForUserInDomain(user, role, dom)
+	if err != nil {
+		return false, response.NewInternalServerError("分配角色失败", err)
+	}
+	if !added {
+		return false, nil
+	}
+
+	if err := s.audit(ctx, dom, auditentity.ActionRoleAssign, user, roleDetail(user, role, dom), func() error {
+		_, err := s.enforcer.DeleteRoleForUserInDomain(user, role, dom)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteRoleForUser 撤销用户在租户内的角色
+func (s *PermissionService) DeleteRoleForUser(ctx context.Context, user, role, dom string) (bool, error) {
+	if err := validateRoleAssignment(user, role, dom); err != nil {
+		return false, err
+	}
+
+	deleted, err := s.enforcer.DeleteRoleForUserInDomain(user, role, dom)
+	if err != nil {
+		return false, response.NewInternalServerError("撤销角色失败", err)
+	}
+	if !deleted {
+		return false, nil
+	}
+
+	if err := s.audit(ctx, dom, auditentity.ActionRoleUnassign, user, roleDetail(user, role, dom), func() error {
+		_, err := s.enforcer.AddRoleForUserInDomain(user, role, dom)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetUserPermissions 获取用户在租户内的有效权限
+func (s *PermissionService) GetUserPermissions(ctx context.Context, user, dom string) (*UserPermissions, error) {
+	if strings.TrimSpace(dom) == "" {
+		return nil, response.NewValidationError("租户不能为空")
+	}
+	roles := s.enforcer.GetRolesForUserInDomain(user, dom)
+	implicitRoles, err := s.enforcer.GetImplicitRolesForUser(user, dom)
+	if err != nil {
+		return nil, response.NewInternalServerError("查询用户角色失败", err)
+	}
+	rules, err := s.enforcer.GetImplicitPermissionsForUser(user, dom)
+	if err != nil {
+		return nil, response.NewInternalServerError("查询用户权限失败", err)
+	}
+
+	return &UserPermissions{
+		UserID:        user,
+		Domain:        dom,
+		Roles:         roles,
+		ImplicitRoles: implicitRoles,
+		Permissions:   toPolicies(rules),
+	}, nil
+}
+
+// UncoveredRoutes 找出没有任何策略能放行的路由
+func (s *PermissionService) UncoveredRoutes(ctx context.Context, routes []pkgcasbin.Route) ([]pkgcasbin.Route, error) {
+	uncovered, err := pkgcasbin.UncoveredRoutes(s.enforcer, routes)
+	if err != nil {
+		return nil, response.NewInternalServerError("检查路由授权覆盖失败", err)
+	}
+	return uncovered, nil
+}
+
+// ExportPolicies 导出全部租户的策略和角色分配
+func (s *PermissionService) ExportPolicies(ctx context.Context) (*pkgcasbin.PolicyFile, error) {
+	policies, err := s.enforcer.GetPolicy()
+	if err != nil {
+		return nil, response.NewInternalServerError("查询权限策略失败", err)
+	}
+	roles, err := s.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return nil, response.NewInternalServerError("查询角色失败", err)
+	}
+	return pkgcasbin.NewPolicyFile(policies, roles), nil
+}
+
+// ApplyPolicies 以策略文件为准同步策略和角色分配
+// 文件中没有的规则会被删除，因此拒绝同步空文件，避免误删全部策略
+func (s *PermissionService) ApplyPolicies(ctx context.Context, file *pkgcasbin.PolicyFile, dryRun bool) (*PolicyDiff, error) {
+	if err := file.Validate(); err != nil {
+		return nil, response.NewValidationError("策略文件无效", err)
+	}
+	if len(file.Policies) == 0 && len(file.Roles) == 0 {
+		return nil, response.NewValidationError("策略文件为空")
+	}
+
+	current, err := s.ExportPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	diff := &PolicyDiff{}
+	diff.AddedPolicies, diff.RemovedPolicies = diffRules(current.Policies, file.Policies)
+	diff.AddedRoles, diff.RemovedRoles = diffRules(current.Roles, file.Roles)
+	if dryRun || diff.Empty() {
+		return diff, nil
+	}
+
+	if err := s.applyDiff(diff); err != nil {
+		s.invalidateCache(ctx)
+		return nil, response.NewInternalServerError("同步权限策略失败", err)
+	}
+
+	detail := map[string]any{
+		"added_policies":   ruleStrings(diff.AddedPolicies),
+		"removed_policies": ruleStrings(diff.RemovedPolicies),
+		"added_roles":      ruleStrings(diff.AddedRoles),
+		"removed_roles":    ruleStrings(diff.RemovedRoles),
+	}
+	if err := s.audit(ctx, pkgcasbin.AllDomains, auditentity.ActionPolicyApply, "policies", detail, func() error {
+		return s.applyDiff(diff.reverse())
+	}); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// applyDiff 先删除后添加，每类规则批量写库并只通知其他实例一次
+func (s *PermissionService) applyDiff(diff *PolicyDiff) error {
+	if len(diff.RemovedPolicies) > 0 {
+		if _, err := s.enforcer.RemovePolicies(ruleValues(diff.RemovedPolicies)); err != nil {
+			return err
+		}
+	}
+	if len(diff.RemovedRoles) > 0 {
+		if _, err := s.enforcer.RemoveGroupingPolicies(ruleValues(diff.RemovedRoles)); err != nil {
+			return err
+		}
+	}
+	if len(diff.AddedPolicies) > 0 {
+		if _, err := s.enforcer.AddPolicies(ruleValues(diff.AddedPolicies)); err != nil {
+			return err
+		}
+	}
+	if len(diff.AddedRoles) > 0 {
+		if _, err := s.enforcer.AddGroupingPolicies(ruleValues(diff.AddedRoles)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverse 撤销本次差异所需的差异
+func (d *PolicyDiff) reverse() *PolicyDiff {
+	return &PolicyDiff{
+		AddedPolicies:   d.RemovedPolicies,
+		RemovedPolicies: d.AddedPolicies,
+		AddedRoles:      d.RemovedRoles,
+		RemovedRoles:    d.AddedRoles,
+	}
+}
+
+type casbinRule interface {
+	comparable
+	Values() []string
+	String() string
+}
+
+// diffRules 计算从 current 到 desired 需要添加和删除的规则，desired 中的重复规则只计一次
+func diffRules[T casbinRule](current, desired []T) (added, removed []T) {
+	currentSet := make(map[T]struct{}, len(current))
+	for _, rule := range current {
+		currentSet[rule] = struct{}{}
+	}
+	desiredSet := make(map[T]struct{}, len(desired))
+	for _, rule := range desired {
+		if _, ok := desiredSet[rule]; ok {
+			continue
+		}
+		desiredSet[rule] = struct{}{}
+		if _, ok := currentSet[rule]; !ok {
+			added = append(added, rule)
+		}
+	}
+	for _, rule := range current {
+		if _, ok := desiredSet[rule]; !ok {
+			removed = append(removed, rule)
+		}
+	}
+	return added, removed
+}
+
+func ruleValues[T casbinRule](rules []T) [][]string {
+	values := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		values = append(values, rule.Values())
+	}
+	return values
+}
+
+func ruleStrings[T casbinRule](rules []T) []string {
+	values := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		values = append(values, rule.String())
+	}
+	return values
+}
+
+// audit 变更成功后写入审计日志并清空鉴权缓存
+// 缓存以请求参数为键，无法按策略精确失效，因此每次变更都整体清空
+func (s *PermissionService) audit(ctx context.Context, dom, action, resource string, detail map[string]any, rollback func() error) error {
+	if err := s.auditService.Record(ctx, dom, action, resource, detail); err != nil {
+		if rbErr := rollback(); rbErr != nil {
+			logger.Error(ctx, "Failed to roll back permission change after audit failure",
+				zap.String("action", action),
+				zap.String("resource", resource),
+				zap.Error(rbErr))
+		}
+		s.invalidateCache(ctx)
+		return err
+	}
+
+	s.invalidateCache(ctx)
+	logger.Info(ctx, "Permission changed",
+		zap.String("action", action),
+		zap.String("resource", resource),
+		zap.Any("detail", detail))
+	return nil
+}
+
+func (s *PermissionService) invalidateCache(ctx context.Context) {
+	if err := s.enforcer.InvalidateCache(); err != nil {
+		logger.Warn(ctx, "Failed to invalidate casbin cache", zap.Error(err))
+	}
+}
+
+func validatePolicy(sub, dom, obj, act string) error {
+	if strings.TrimSpace(sub) == "" || strings.TrimSpace(dom) == "" || strings.TrimSpace(obj) == "" || strings.TrimSpace(act) == "" {
+		return response.NewValidationError("策略的主体、租户、资源和操作均不能为空")
+	}
+	return nil
 }
 
-// AddRoleForUser 为用户添加角色
-func (s *PermissionService) AddRoleForUser(ctx context.Context, user, role string) (bool, error) {
-	return s.enforcer.AddRoleForUser(user, role)
+func validateRoleAssignment(user, role, dom string) error {
+	if strings.TrimSpace(user) == "" || strings.TrimSpace(role) == "" || strings.TrimSpace(dom) == "" {
+		return response.NewValidationError("用户、角色和租户均不能为空")
+	}
+	if user == role {
+		return response.NewValidationError("不能将角色分配给自身")
+	}
+	if user == pkgcasbin.OwnerSubject || role == pkgcasbin.OwnerSubject {
+		return response.NewValidationError("$owner 表示资源所有者，只能用作策略主体")
+	}
+	return nil
+}
+
+func policyDetail(sub, dom, obj, act string) map[string]any {
+	return map[string]any{"sub": sub, "dom": dom, "obj": obj, "act": act}
+}
+
+func roleDetail(user, role, dom string) map[string]any {
+	return map[string]any{"user": user, "role": role, "dom": dom}
+}
+
+// toPolicies 将casbin规则转换为策略，忽略字段不完整的规则
+func toPolicies(rules [][]string) []Policy {
+	policies := make([]Policy, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule) < 4 {
+			continue
+		}
+		policies = append(policies, Policy{Subject: rule[0], Domain: rule[1], Object: rule[2], Action: rule[3]})
+	}
+	return policies
 }
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+	"common/logger"
+	"common/response"
+)
+
+// defaultImportJobTTL 导入任务进度和错误报告的默认保留时长
+const defaultImportJobTTL = 24 * time.Hour
+
+// maxConcurrentImportJobs 每个实例同时执行的导入任务数，其余任务排队
+const maxConcurrentImportJobs = 2
+
+const (
+	importJobKeyPrefix    = "user:import:job:"    // 任务状态
+	importReportKeyPrefix = "user:import:report:" // 任务错误报告
+)
+
+// UserImportJobStatus 导入任务状态
+type UserImportJobStatus string
+
+const (
+	UserImportJobPending   UserImportJobStatus = "pending"   // 排队中
+	UserImportJobRunning   UserImportJobStatus = "running"   // 执行中
+	UserImportJobCompleted UserImportJobStatus = "completed" // 已完成，部分行失败也视为完成
+	UserImportJobFailed    UserImportJobStatus = "failed"    // 执行中断
+)
+
+// UserImportJob 导入任务
+type UserImportJob struct {
+	ID         string              `json:"id"`
+	Status     UserImportJobStatus `json:"status"`
+	FileName   string              `json:"file_name"`
+	DryRun     bool                `json:"dry_run"`
+	Total      int                 `json:"total"`
+	Processed  int                 `json:"processed"`
+	Created    int                 `json:"created"`
+	Failed     int                 `json:"failed"`
+	Error      string              `json:"error,omitempty"` // 任务中断的原因
+	CreatedBy  string              `json:"created_by,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+// UserImportJobServiceInterface 异步导入任务服务接口
+type UserImportJobServiceInterface interface {
+	// Submit 创建导入任务并在后台执行
+	Submit(ctx context.Context, fileName string, rows []UserImportRow, dryRun bool, createdBy string) (*UserImportJob, error)
+	// Get 查询任务进度
+	Get(ctx context.Context, jobID string) (*UserImportJob, error)
+	// Report 查询任务的逐行错误，任务结束后才有报告
+	Report(ctx context.Context, jobID string) ([]UserImportRowError, error)
+	// Stop 中断执行中的任务并等待其退出
+	Stop(ctx context.Context) error
+}
+
+// UserImportJobService 在后台执行导入任务，任务状态和错误报告保存在Redis，任意实例都可以查询
+type UserImportJobService struct {
+	importer    UserImporterInterface
+	redisClient *redis.RedisClient
+	logger      *zap.Logger
+	ttl         time.Duration
+
+	ctx    context.Context // 服务停止时取消，中断执行中的任务
+	cancel context.CancelFunc
+	slots  chan struct{}
+	mu     sync.Mutex // 保证 Stop 开始等待后不再有新任务加入 wg
+	wg     sync.WaitGroup
+}
+
+// NewUserImportJobService 创建异步导入任务服务
+func NewUserImportJobService(
+	importer UserImporterInterface,
+	redisClient *redis.RedisClient,
+	zapLogger *zap.Logger,
+	cfg *config.Config,
+) UserImportJobServiceInterface {
+	ttl := cfg.User.Import.JobTTL
+	if ttl <= 0 {
+		ttl = defaultImportJobTTL
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &UserImportJobService{
+		importer:    importer,
+		redisClient: redisClient,
+		logger:      zapLogger,
+		ttl:         ttl,
+		ctx:         ctx,
+		cancel:      cancel,
+		slots:       make(chan struct{}, maxConcurrentImportJobs),
+	}
+}
+
+// Submit 创建导入任务
+func (s *UserImportJobService) Submit(ctx context.Context, fileName string, rows []UserImportRow, dryRun bool, createdBy string) (*UserImportJob, error) {
+	s.mu.Lock()
+	if err := s.ctx.Err(); err != nil {
+		s.mu.Unlock()
+		return nil, response.NewInternalServerError("服务正在停止，请稍后重试")
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	job := &UserImportJob{
+		ID:        uuid.NewString(),
+		Status:    UserImportJobPending,
+		FileName:  fileName,
+		DryRun:    dryRun,
+		Total:     len(rows),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.save(ctx, job); err != nil {
+		s.wg.Done()
+		return nil, err
+	}
+
+	// 后台任务持有副本，返回给调用方的任务不会被并发修改
+	running := *job
+	go s.run(&running, rows)
+
+	return job, nil
+}
+
+// run 执行导入任务，任务与请求的上下文无关，只在服务停止时中断
+func (s *UserImportJobService) run(job *UserImportJob, rows []UserImportRow) {
+	defer s.wg.Done()
+	ctx := logger.ToContext(s.ctx, s.logger.With(zap.String("import_job_id", job.ID)))
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		s.finish(job, nil, errors.New("服务停止，任务未执行"))
+		return
+	}
+
+	job.Status = UserImportJobRunning
+	if err := s.save(ctx, job); err != nil {
+		logger.Warn(ctx, "Failed to save import job progress", zap.Error(err))
+	}
+	logger.Info(ctx, "User import job started", zap.Int("rows", len(rows)), zap.Bool("dry_run", job.DryRun))
+
+	result, err := s.importer.Import(ctx, rows, UserImportOptions{DryRun: job.DryRun}, func(p UserImportProgress) {
+		job.Processed, job.Created, job.Failed = p.Processed, p.Created, p.Failed
+		if err := s.save(ctx, job); err != nil {
+			logger.Warn(ctx, "Failed to save import job progress", zap.Error(err))
+		}
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			err = errors.New("服务停止，任务中断")
+		} else {
+			logger.Error(ctx, "User import job aborted", zap.Error(err))
+			err = errors.New("任务中断: " + errorMessage(err))
+		}
+	}
+	s.finish(job, result, err)
+}
+
+// finish 保存任务的最终状态和错误报告
+func (s *UserImportJobService) finish(job *UserImportJob, result *UserImportResult, runErr error) {
+	// 服务停止时上下文已取消，仍需把最终状态写入Redis
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = logger.ToContext(ctx, s.logger.With(zap.String("import_job_id", job.ID)))
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = UserImportJobCompleted
+	if runErr != nil {
+		job.Status = UserImportJobFailed
+		job.Error = runErr.Error()
+	}
+	if result != nil {
+		job.Processed, job.Created, job.Failed = result.Processed, result.Created, result.Failed
+		if err := s.saveReport(ctx, job.ID, result.Errors); err != nil {
+			logger.Error(ctx, "Failed to save import job report", zap.Error(err))
+		}
+	}
+	if err := s.save(ctx, job); err != nil {
+		logger.Error(ctx, "Failed to save import job result", zap.Error(err))
+	}
+
+	logger.Info(ctx, "User import job finished",
+		zap.String("status", string(job.Status)),
+		zap.Int("created", job.Created),
+		zap.Int("failed", job.Failed))
+}
+
+// Get 查询任务进度
+func (s *UserImportJobService) Get(ctx context.Context, jobID string) (*UserImportJob, error) {
+	data, err := s.redisClient.Get(ctx, importJobKeyPrefix+jobID).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, response.NewNotFoundError("导入任务不存在或已过期")
+	}
+	if err != nil {
+		return nil, response.NewInternalServerError("查询导入任务失败", err)
+	}
+
+	var job UserImportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, response.NewInternalServerError("查询导入任务失败", err)
+	}
+	return &job, nil
+}
+
+// Report 查询任务的逐行错误
+func (s *UserImportJobService) Report(ctx context.Context, jobID string) ([]UserImportRowError, error) {
+	job, err := s.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.FinishedAt == nil {
+		return nil, response.NewConcurrencyConflictError("导入任务尚未结束，结束后才能下载错误报告")
+	}
+
+	data, err := s.redisClient.Get(ctx, importReportKeyPrefix+jobID).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return []UserImportRowError{}, nil
+	}
+	if err != nil {
+		return nil, response.NewInternalServerError("查询导入错误报告失败", err)
+	}
+
+	var rowErrors []UserImportRowError
+	if err := json.Unmarshal(data, &rowErrors); err != nil {
+		return nil, response.NewInternalServerError("查询导入错误报告失败", err)
+	}
+	return rowErrors, nil
+}
+
+// Stop 中断执行中的任务并等待其保存最终状态
+func (s *UserImportJobService) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *UserImportJobService) save(ctx context.Context, job *UserImportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return response.NewInternalServerError("保存导入任务失败", err)
+	}
+	if err := s.redisClient.Set(ctx, importJobKeyPrefix+job.ID, data, s.ttl).Err(); err != nil {
+		return response.NewInternalServerError("保存导入任务失败", err)
+	}
+	return nil
+}
+
+func (s *UserImportJobService) saveReport(ctx context.Context, jobID string, rowErrors []UserImportRowError) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(rowErrors)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, importReportKeyPrefix+jobID, data, s.ttl).Err()
+}
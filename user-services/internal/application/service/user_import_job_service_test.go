@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/response"
+	userErrors "user-services/internal/domain/user/errors"
+)
+
+func newTestImportJobService(t *testing.T) (*fakeUserRepository, UserImportJobServiceInterface) {
+	repo, importer := newTestUserImporter(t)
+	_, redisClient := newTestRedis(t)
+	svc := NewUserImportJobService(importer, redisClient, zap.NewNop(), &config.Config{})
+	t.Cleanup(func() { _ = svc.Stop(context.Background()) })
+	return repo, svc
+}
+
+// waitForJob 等待任务结束并返回最终状态
+func waitForJob(t *testing.T, svc UserImportJobServiceInterface, jobID string) *UserImportJob {
+	t.Helper()
+	var job *UserImportJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.Get(context.Background(), jobID)
+		require.NoError(t, err)
+		return job.FinishedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestUserImportJobService_Report(t *testing.T) {
+	ctx := context.Background()
+	repo, svc := newTestImportJobService(t)
+
+	rows := []UserImportRow{
+		importRow(2, "wx-1", "13800138000"),
+		importRow(3, "wx-2", "+86 138 0013 8000"),
+	}
+	submitted, err := svc.Submit(ctx, "users.csv", rows, false, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, UserImportJobPending, submitted.Status)
+
+	job := waitForJob(t, svc, submitted.ID)
+	assert.Equal(t, UserImportJobCompleted, job.Status)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, 2, job.Processed)
+	assert.Equal(t, 1, job.Created)
+	assert.Equal(t, 1, job.Failed)
+	assert.Len(t, repo.users, 1)
+
+	report, err := svc.Report(ctx, submitted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []UserImportRowError{
+		{Line: 3, OpenID: "wx-2", Column: importColumnPhoneNumber, Message: "手机号与第2行重复"},
+	}, report)
+
+	_, err = svc.Report(ctx, "unknown")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+}
+
+func TestUserImportJobService_AbortsOnDatabaseError(t *testing.T) {
+	ctx := context.Background()
+	repo, svc := newTestImportJobService(t)
+	repo.existsErr = response.NewInternalServerError(userErrors.MsgCheckOpenIDExistsFailed, errors.New("connection refused"))
+
+	rows := []UserImportRow{
+		importRow(2, "wx-1", "13800138000"),
+		importRow(3, "wx-2", "13900139000"),
+	}
+	submitted, err := svc.Submit(ctx, "users.csv", rows, false, "admin")
+	require.NoError(t, err)
+
+	job := waitForJob(t, svc, submitted.ID)
+	assert.Equal(t, UserImportJobFailed, job.Status)
+	assert.Equal(t, "任务中断: "+userErrors.MsgCheckOpenIDExistsFailed, job.Error)
+	assert.Zero(t, job.Failed)
+
+	report, err := svc.Report(ctx, submitted.ID)
+	require.NoError(t, err)
+	assert.Empty(t, report)
+}
+
+func TestUserImportJobService_SubmitDuringStop(t *testing.T) {
+	ctx := context.Background()
+	_, svc := newTestImportJobService(t)
+
+	// 与 Stop 并发提交的任务要么被拒绝，要么在 Stop 返回前结束
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted []string
+	)
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			rows := []UserImportRow{importRow(2, fmt.Sprintf("wx-%d", i), fmt.Sprintf("1380013800%d", i))}
+			job, err := svc.Submit(ctx, "users.csv", rows, true, "admin")
+			if err != nil {
+				assert.True(t, response.IsErrorType(err, response.ErrorTypeInternalServer), "got %v", err)
+				return
+			}
+			mu.Lock()
+			accepted = append(accepted, job.ID)
+			mu.Unlock()
+		}(i)
+	}
+
+	// 让部分提交在 Stop 开始时正处于保存任务的阶段
+	close(start)
+	time.Sleep(time.Millisecond)
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, svc.Stop(stopCtx))
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, jobID := range accepted {
+		job, err := svc.Get(ctx, jobID)
+		require.NoError(t, err)
+		assert.NotNil(t, job.FinishedAt, "job %s still running after Stop", jobID)
+	}
+
+	_, err := svc.Submit(ctx, "users.csv", []UserImportRow{importRow(2, "wx-x", "13800138000")}, true, "admin")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeInternalServer), "got %v", err)
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"common/config"
+	"common/pkg/tabular"
+	"common/response"
+	"user-services/internal/domain/user/entity"
+	domainservice "user-services/internal/domain/user/service"
+)
+
+// 批量导入默认配置
+const (
+	defaultImportBatchSize = 500
+	defaultImportMaxRows   = 10000
+)
+
+// 导入文件的列，表头不区分大小写，列顺序任意
+const (
+	importColumnOpenID      = "open_id"
+	importColumnName        = "name"
+	importColumnGender      = "gender"
+	importColumnPhoneNumber = "phone_number"
+	importColumnPassword    = "password"
+)
+
+var importColumns = []string{
+	importColumnOpenID, importColumnName, importColumnGender, importColumnPhoneNumber, importColumnPassword,
+}
+
+// UserImportRow 导入文件中的一行
+type UserImportRow struct {
+	Line        int // 文件中的行号，表头为第1行
+	OpenID      string
+	Name        string
+	Gender      string
+	PhoneNumber string
+	Password    string
+}
+
+// UserImportOptions 导入选项
+type UserImportOptions struct {
+	DryRun    bool // 只校验，不写入
+	BatchSize int  // 每批插入的用户数，为0时使用配置
+}
+
+// UserImportRowError 一行导入失败的原因
+type UserImportRowError struct {
+	Line    int    `json:"line"`
+	OpenID  string `json:"open_id"`
+	Column  string `json:"column,omitempty"` // 出错的列，无法确定时为空
+	Message string `json:"message"`
+}
+
+// UserImportProgress 导入进度
+type UserImportProgress struct {
+	Total     int // 总行数
+	Processed int // 已处理行数
+	Created   int // 已创建(dry-run时为可创建)的用户数
+	Failed    int // 失败行数
+}
+
+// UserImportResult 导入结果
+type UserImportResult struct {
+	UserImportProgress
+	Errors []UserImportRowError
+}
+
+// UserImporterInterface 批量导入用户接口
+type UserImporterInterface interface {
+	// ReadRows 读取导入文件，表头缺少必需的列或行数超过上限时返回错误
+	ReadRows(r io.Reader, format string) ([]UserImportRow, error)
+	// Import 逐行按创建用户的规则校验，校验通过的用户分批写入；progress 在每批处理完成后调用
+	Import(ctx context.Context, rows []UserImportRow, opts UserImportOptions, progress func(UserImportProgress)) (*UserImportResult, error)
+}
+
+// UserImporter 批量导入用户
+// 每行与 POST /users 一样经过 UserDomainService 的校验，文件内重复的手机号和open_id也会被拒绝
+type UserImporter struct {
+	userService *domainservice.UserDomainService
+	batchSize   int
+	maxRows     int
+}
+
+// NewUserImporter 创建批量导入用户服务
+func NewUserImporter(userService *domainservice.UserDomainService, cfg *config.Config) UserImporterInterface {
+	importer := &UserImporter{
+		userService: userService,
+		batchSize:   cfg.User.Import.BatchSize,
+		maxRows:     cfg.User.Import.MaxRows,
+	}
+	if importer.batchSize <= 0 {
+		importer.batchSize = defaultImportBatchSize
+	}
+	if importer.maxRows <= 0 {
+		importer.maxRows = defaultImportMaxRows
+	}
+	return importer
+}
+
+// ReadRows 读取导入文件，跳过空行
+func (i *UserImporter) ReadRows(r io.Reader, format string) ([]UserImportRow, error) {
+	reader, err := tabular.NewReader(r, format)
+	if err != nil {
+		return nil, response.NewValidationError("无法读取导入文件", err)
+	}
+	defer reader.Close()
+
+	record, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, response.NewValidationError("导入文件为空")
+	}
+	if err != nil {
+		return nil, response.NewValidationError("无法读取导入文件", err)
+	}
+	header := tabular.NewHeader(record)
+	if missing := header.Missing(importColumns...); len(missing) > 0 {
+		return nil, response.NewValidationError(fmt.Sprintf("导入文件缺少列: %s", strings.Join(missing, ", ")))
+	}
+
+	rows := make([]UserImportRow, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, response.NewValidationError(fmt.Sprintf("第%d行无法读取", line), err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) >= i.maxRows {
+			return nil, response.NewValidationError(fmt.Sprintf("导入文件超过%d行", i.maxRows))
+		}
+
+		rows = append(rows, UserImportRow{
+			Line:        line,
+			OpenID:      header.Get(record, importColumnOpenID),
+			Name:        header.Get(record, importColumnName),
+			Gender:      header.Get(record, importColumnGender),
+			PhoneNumber: header.Get(record, importColumnPhoneNumber),
+			Password:    header.Get(record, importColumnPassword),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, response.NewValidationError("导入文件没有数据行")
+	}
+	return rows, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Import 批量导入用户
+// 单行失败只记录在结果中，不影响其他行；上下文取消、数据库异常等与具体行无关的错误中断导入并返回 error
+func (i *UserImporter) Import(ctx context.Context, rows []UserImportRow, opts UserImportOptions, progress func(UserImportProgress)) (*UserImportResult, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = i.batchSize
+	}
+
+	result := &UserImportResult{
+		UserImportProgress: UserImportProgress{Total: len(rows)},
+		Errors:             make([]UserImportRowError, 0),
+	}
+	seenOpenIDs := make(map[string]int, len(rows))
+	seenPhones := make(map[string]int, len(rows))
+	batch := make([]*entity.User, 0, batchSize)
+	batchRows := make([]UserImportRow, 0, batchSize)
+
+	fail := func(rowErr UserImportRowError) {
+		result.Failed++
+		result.Errors = append(result.Errors, rowErr)
+	}
+
+	flush := func() error {
+		if len(batch) > 0 {
+			if err := i.saveBatch(ctx, batch, batchRows, opts.DryRun, result, fail); err != nil {
+				return err
+			}
+		}
+		result.Processed += len(batchRows)
+		batch, batchRows = batch[:0], batchRows[:0]
+		if progress != nil {
+			progress(result.UserImportProgress)
+		}
+		return nil
+	}
+
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		user, rowErr, err := i.prepareRow(ctx, row, seenOpenIDs, seenPhones)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			return result, err
+		}
+		if rowErr != nil {
+			result.Processed++
+			fail(*rowErr)
+			continue
+		}
+
+		batch = append(batch, user)
+		batchRows = append(batchRows, row)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// prepareRow 校验一行并构造用户
+// 行数据不合法时返回该行的错误；查询数据库失败时返回 error，由调用方中断导入，避免把所有行都记为失败
+func (i *UserImporter) prepareRow(ctx context.Context, row UserImportRow, seenOpenIDs, seenPhones map[string]int) (*entity.User, *UserImportRowError, error) {
+	if row.OpenID == "" {
+		return nil, newImportRowError(row, importColumnOpenID, "open_id不能为空"), nil
+	}
+	if line, ok := seenOpenIDs[row.OpenID]; ok {
+		return nil, newImportRowError(row, importColumnOpenID, fmt.Sprintf("open_id与第%d行重复", line)), nil
+	}
+	// 按E.164格式判断重复，同一号码的不同写法视为重复
+	phoneNumber, err := i.userService.NormalizePhoneNumber(row.PhoneNumber)
+	if err != nil {
+		return nil, newImportRowError(row, importColumnPhoneNumber, errorMessage(err)), nil
+	}
+	if line, ok := seenPhones[phoneNumber]; ok {
+		return nil, newImportRowError(row, importColumnPhoneNumber, fmt.Sprintf("手机号与第%d行重复", line)), nil
+	}
+	gender, err := strconv.Atoi(row.Gender)
+	if err != nil {
+		return nil, newImportRowError(row, importColumnGender, "性别应为100(男)、200(女)或300(其他)"), nil
+	}
+
+	exists, err := i.userService.OpenIDExists(ctx, row.OpenID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exists {
+		return nil, newImportRowError(row, importColumnOpenID, "open_id已被使用"), nil
+	}
+
+	user, err := i.userService.PrepareUser(ctx, row.OpenID, row.Name, phoneNumber, row.Password, gender)
+	if err != nil {
+		if isSystemError(err) {
+			return nil, nil, err
+		}
+		return nil, newImportRowError(row, "", errorMessage(err)), nil
+	}
+
+	seenOpenIDs[row.OpenID] = row.Line
+	seenPhones[phoneNumber] = row.Line
+	return user, nil, nil
+}
+
+// saveBatch 写入一批用户
+// 整批写入失败时(通常是并发创建导致的唯一索引冲突)逐个重试，把失败定位到具体的行
+func (i *UserImporter) saveBatch(
+	ctx context.Context,
+	batch []*entity.User,
+	rows []UserImportRow,
+	dryRun bool,
+	result *UserImportResult,
+	fail func(rowErr UserImportRowError),
+) error {
+	if dryRun {
+		result.Created += len(batch)
+		return nil
+	}
+
+	if err := i.userService.CreateUsers(ctx, batch); err == nil {
+		result.Created += len(batch)
+		return nil
+	}
+
+	for idx, user := range batch {
+		if err := i.userService.CreateUsers(ctx, []*entity.User{user}); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if isSystemError(err) {
+				return err
+			}
+			fail(*newImportRowError(rows[idx], "", errorMessage(err)))
+			continue
+		}
+		result.Created++
+	}
+	return nil
+}
+
+// newImportRowError 创建一行的导入错误
+func newImportRowError(row UserImportRow, column, message string) *UserImportRowError {
+	return &UserImportRowError{Line: row.Line, OpenID: row.OpenID, Column: column, Message: message}
+}
+
+// isSystemError 判断是否为数据库异常等与具体行无关的错误，这类错误重试其他行也会失败
+func isSystemError(err error) bool {
+	var domainErr *response.DomainError
+	if !errors.As(err, &domainErr) {
+		return true
+	}
+	switch domainErr.Type {
+	case response.ErrorTypeInternalServer,
+		response.ErrorTypeDatabaseConnection,
+		response.ErrorTypeTimeout,
+		response.ErrorTypeNetworkError:
+		return true
+	}
+	return false
+}
+
+// errorMessage 领域错误只返回面向用户的消息，不暴露底层错误
+func errorMessage(err error) string {
+	var domainErr *response.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return err.Error()
+}
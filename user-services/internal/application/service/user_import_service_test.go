@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/response"
+	userEntity "user-services/internal/domain/user/entity"
+	userErrors "user-services/internal/domain/user/errors"
+	domainservice "user-services/internal/domain/user/service"
+	"user-services/internal/domain/user/validator"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// newTestUserImporter 创建批量导入服务，默认每批2个用户
+func newTestUserImporter(t *testing.T, users ...*userEntity.User) (*fakeUserRepository, UserImporterInterface) {
+	repo := newFakeUserRepository(users...)
+	passwordPolicy := validator.PasswordPolicy{MinLength: 8, MaxLength: 72}
+	userService := domainservice.NewUserDomainService(repo,
+		validator.NewUserValidator(repo, passwordPolicy, validator.PhonePolicy{DefaultRegion: "CN"}), passwordPolicy)
+
+	cfg := &config.Config{}
+	cfg.User.Import.BatchSize = 2
+	return repo, NewUserImporter(userService, cfg)
+}
+
+func importRow(line int, openID, phoneNumber string) UserImportRow {
+	return UserImportRow{
+		Line:        line,
+		OpenID:      openID,
+		Name:        "张三",
+		Gender:      "100",
+		PhoneNumber: phoneNumber,
+		Password:    "password123",
+	}
+}
+
+func TestUserImporter_Import(t *testing.T) {
+	existing := userEntity.NewUser("wx-0", "李四", "+8613600136000", "hash", valueobject.GenderFemale.Int())
+	existing.SetID("existing")
+
+	badGender := importRow(3, "wx-2", "13900139000")
+	badGender.Gender = "男"
+
+	tests := []struct {
+		name        string
+		rows        []UserImportRow
+		dryRun      bool
+		wantCreated int
+		wantErrors  []UserImportRowError
+	}{
+		{
+			name: "duplicates within file",
+			rows: []UserImportRow{
+				importRow(2, "wx-1", "13800138000"),
+				importRow(3, "wx-1", "13900139000"),
+				importRow(4, "wx-3", "+86 138 0013 8000"),
+				importRow(5, "wx-4", "13700137000"),
+			},
+			wantCreated: 2,
+			wantErrors: []UserImportRowError{
+				{Line: 3, OpenID: "wx-1", Column: importColumnOpenID, Message: "open_id与第2行重复"},
+				{Line: 4, OpenID: "wx-3", Column: importColumnPhoneNumber, Message: "手机号与第2行重复"},
+			},
+		},
+		{
+			name: "conflicts with existing users",
+			rows: []UserImportRow{
+				importRow(2, "wx-0", "13800138000"),
+				importRow(3, "wx-2", "136 0013 6000"),
+				importRow(4, "wx-3", "13900139000"),
+			},
+			wantCreated: 1,
+			wantErrors: []UserImportRowError{
+				{Line: 2, OpenID: "wx-0", Column: importColumnOpenID, Message: "open_id已被使用"},
+				{Line: 3, OpenID: "wx-2", Message: userErrors.ErrPhoneNotUnique.Message},
+			},
+		},
+		{
+			name: "invalid rows",
+			rows: []UserImportRow{
+				importRow(2, "", "13800138000"),
+				badGender,
+				importRow(4, "wx-3", "12345"),
+			},
+			wantErrors: []UserImportRowError{
+				{Line: 2, Column: importColumnOpenID, Message: "open_id不能为空"},
+				{Line: 3, OpenID: "wx-2", Column: importColumnGender, Message: "性别应为100(男)、200(女)或300(其他)"},
+				{Line: 4, OpenID: "wx-3", Column: importColumnPhoneNumber, Message: userErrors.ErrInvalidPhone.Message},
+			},
+		},
+		{
+			name: "dry run",
+			rows: []UserImportRow{
+				importRow(2, "wx-1", "13800138000"),
+				importRow(3, "wx-2", "13900139000"),
+				importRow(4, "wx-2", "13700137000"),
+			},
+			dryRun:      true,
+			wantCreated: 2,
+			wantErrors: []UserImportRowError{
+				{Line: 4, OpenID: "wx-2", Column: importColumnOpenID, Message: "open_id与第3行重复"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, importer := newTestUserImporter(t, existing)
+
+			var progress []UserImportProgress
+			result, err := importer.Import(context.Background(), tt.rows, UserImportOptions{DryRun: tt.dryRun},
+				func(p UserImportProgress) { progress = append(progress, p) })
+			require.NoError(t, err)
+
+			assert.Equal(t, len(tt.rows), result.Total)
+			assert.Equal(t, len(tt.rows), result.Processed)
+			assert.Equal(t, tt.wantCreated, result.Created)
+			assert.Equal(t, len(tt.wantErrors), result.Failed)
+			assert.Equal(t, tt.wantErrors, result.Errors)
+			require.NotEmpty(t, progress)
+			assert.Equal(t, result.UserImportProgress, progress[len(progress)-1])
+
+			if tt.dryRun {
+				assert.Zero(t, repo.batches, "dry run must not write")
+				assert.Len(t, repo.users, 1)
+				return
+			}
+			assert.Len(t, repo.users, 1+tt.wantCreated)
+		})
+	}
+}
+
+func TestUserImporter_RetriesRowsAfterBatchFailure(t *testing.T) {
+	repo, importer := newTestUserImporter(t)
+	// 校验通过后手机号被并发创建的用户占用，整批写入因唯一索引冲突失败
+	repo.createErr = func(user *userEntity.User) error {
+		if user.PhoneNumber() == "+8613900139000" {
+			return response.NewAlreadyExistsError(userErrors.MsgUserAlreadyExists)
+		}
+		return nil
+	}
+
+	rows := []UserImportRow{
+		importRow(2, "wx-1", "13800138000"),
+		importRow(3, "wx-2", "13900139000"),
+		importRow(4, "wx-3", "13700137000"),
+	}
+	result, err := importer.Import(context.Background(), rows, UserImportOptions{BatchSize: 3}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, []UserImportRowError{
+		{Line: 3, OpenID: "wx-2", Message: userErrors.MsgUserAlreadyExists},
+	}, result.Errors)
+	// 整批1次，逐行重试3次
+	assert.Equal(t, 4, repo.batches)
+	assert.Len(t, repo.users, 2)
+}
+
+func TestUserImporter_AbortsOnDatabaseError(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	rows := []UserImportRow{
+		importRow(2, "wx-1", "13800138000"),
+		importRow(3, "wx-2", "13900139000"),
+	}
+
+	tests := []struct {
+		name  string
+		setup func(*fakeUserRepository)
+	}{
+		{name: "exists check", setup: func(repo *fakeUserRepository) {
+			repo.existsErr = response.NewInternalServerError(userErrors.MsgCheckOpenIDExistsFailed, dbErr)
+		}},
+		{name: "batch insert", setup: func(repo *fakeUserRepository) {
+			repo.createErr = func(*userEntity.User) error {
+				return response.NewInternalServerError(userErrors.MsgCreateUserFailed, dbErr)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, importer := newTestUserImporter(t)
+			tt.setup(repo)
+
+			result, err := importer.Import(context.Background(), rows, UserImportOptions{}, nil)
+			assert.True(t, response.IsErrorType(err, response.ErrorTypeInternalServer), "got %v", err)
+			// 数据库异常与具体的行无关，不能把行记为失败
+			assert.Zero(t, result.Failed)
+			assert.Empty(t, result.Errors)
+			assert.Empty(t, repo.users)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/databases/redis"
+	"common/logger"
+	"common/pkg/sms"
+	"common/response"
+	"user-services/internal/domain/user/validator"
+)
+
+// CodePurpose 验证码用途，不同用途的验证码互不通用
+type CodePurpose string
+
+const (
+	CodePurposeLogin CodePurpose = "login" // 短信登录
+)
+
+// 验证码默认配置
+const (
+	defaultCodeLength      = 6
+	defaultCodeTTL         = 5 * time.Minute
+	defaultCodeMaxAttempts = 5
+	defaultSendInterval    = time.Minute
+	defaultPhoneDailyLimit = 10
+	defaultIPHourlyLimit   = 20
+)
+
+const (
+	smsCodeKeyPrefix       = "sms:code:"        // 验证码记录
+	smsIntervalKeyPrefix   = "sms:interval:"    // 手机号发送间隔
+	smsPhoneLimitKeyPrefix = "sms:limit:phone:" // 手机号每日发送计数
+	smsIPLimitKeyPrefix    = "sms:limit:ip:"    // IP每小时发送计数
+)
+
+// SendCodeResult 验证码发送结果
+type SendCodeResult struct {
+	ExpiresIn   int64 // 验证码有效期(秒)
+	ResendAfter int64 // 距离可再次发送的时间(秒)
+}
+
+// VerificationCodeServiceInterface 短信验证码服务接口
+type VerificationCodeServiceInterface interface {
+	SendCode(ctx context.Context, purpose CodePurpose, phoneNumber, clientIP string) (*SendCodeResult, error)
+	VerifyCode(ctx context.Context, purpose CodePurpose, phoneNumber, code string) error
+}
+
+// VerificationCodeService 基于Redis的短信验证码服务
+type VerificationCodeService struct {
+	redisClient   *redis.RedisClient
+	sender        sms.SMSSender
+	userValidator validator.UserValidator
+	hashKey       []byte // 验证码哈希密钥，防止拿到Redis数据后穷举出验证码
+	cfg           config.SMSCodeConfig
+}
+
+// NewVerificationCodeService 创建短信验证码服务
+func NewVerificationCodeService(
+	redisClient *redis.RedisClient,
+	sender sms.SMSSender,
+	userValidator validator.UserValidator,
+	cfg *config.Config,
+) VerificationCodeServiceInterface {
+	codeCfg := cfg.SMS.Code
+	if codeCfg.Length <= 0 {
+		codeCfg.Length = defaultCodeLength
+	}
+	if codeCfg.TTL <= 0 {
+		codeCfg.TTL = defaultCodeTTL
+	}
+	if codeCfg.MaxAttempts <= 0 {
+		codeCfg.MaxAttempts = defaultCodeMaxAttempts
+	}
+	if codeCfg.SendInterval <= 0 {
+		codeCfg.SendInterval = defaultSendInterval
+	}
+	if codeCfg.PhoneDailyLimit <= 0 {
+		codeCfg.PhoneDailyLimit = defaultPhoneDailyLimit
+	}
+	if codeCfg.IPHourlyLimit <= 0 {
+		codeCfg.IPHourlyLimit = defaultIPHourlyLimit
+	}
+
+	return &VerificationCodeService{
+		redisClient:   redisClient,
+		sender:        sender,
+		userValidator: userValidator,
+		hashKey:       []byte(cfg.System.SecretKey),
+		cfg:           codeCfg,
+	}
+}
+
+// SendCode 生成并发送验证码
+// 发送前依次检查IP每小时上限、手机号发送间隔和手机号每日上限
+func (s *VerificationCodeService) SendCode(ctx context.Context, purpose CodePurpose, phoneNumber, clientIP string) (*SendCodeResult, error) {
+	// 限流和验证码都按E.164格式的号码记录，同一号码的不同写法共用限额
+	phoneNumber, err := s.userValidator.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. IP每小时发送上限
+	if clientIP != "" {
+		if err := s.checkLimit(ctx, smsIPLimitKeyPrefix+clientIP, s.cfg.IPHourlyLimit, time.Hour); err != nil {
+			logger.Warn(ctx, "SMS send rate limited by IP", zap.String("client_ip", clientIP))
+			return nil, err
+		}
+	}
+
+	// 2. 同一手机号发送间隔
+	intervalKey := smsIntervalKeyPrefix + phoneNumber
+	ok, err := s.redisClient.SetNX(ctx, intervalKey, 1, s.cfg.SendInterval).Result()
+	if err != nil {
+		return nil, response.NewInternalServerError("发送验证码失败", err)
+	}
+	if !ok {
+		ttl, _ := s.redisClient.TTL(ctx, intervalKey).Result()
+		return nil, tooManyRequests("验证码发送过于频繁，请稍后再试", ttl)
+	}
+
+	// 3. 同一手机号每日发送上限
+	dailyKey := smsPhoneLimitKeyPrefix + phoneNumber + ":" + time.Now().Format("20060102")
+	if err := s.checkLimit(ctx, dailyKey, s.cfg.PhoneDailyLimit, 24*time.Hour); err != nil {
+		logger.Warn(ctx, "SMS send rate limited by phone number", zap.String("phone_number", phoneNumber))
+		return nil, err
+	}
+
+	// 4. 生成验证码并保存哈希值，新验证码会覆盖旧验证码并重置校验次数
+	code, err := generateNumericCode(s.cfg.Length)
+	if err != nil {
+		return nil, response.NewInternalServerError("生成验证码失败", err)
+	}
+
+	codeKey := s.codeKey(purpose, phoneNumber)
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, codeKey)
+	pipe.HSet(ctx, codeKey, "hash", s.codeHash(purpose, phoneNumber, code), "attempts", 0)
+	pipe.Expire(ctx, codeKey, s.cfg.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, response.NewInternalServerError("保存验证码失败", err)
+	}
+
+	// 5. 发送短信，失败时撤销验证码和发送间隔，允许用户立即重试
+	content := fmt.Sprintf("您的验证码是%s，%d分钟内有效，请勿泄露给他人。", code, int(s.cfg.TTL.Minutes()))
+	if err := s.sender.Send(ctx, phoneNumber, content); err != nil {
+		s.redisClient.Del(ctx, codeKey, intervalKey)
+		return nil, response.NewExternalServiceUnavailableError("短信发送失败", err)
+	}
+
+	logger.Info(ctx, "Verification code sent",
+		zap.String("purpose", string(purpose)),
+		zap.String("phone_number", phoneNumber))
+
+	return &SendCodeResult{
+		ExpiresIn:   int64(s.cfg.TTL.Seconds()),
+		ResendAfter: int64(s.cfg.SendInterval.Seconds()),
+	}, nil
+}
+
+// VerifyCode 校验验证码，校验成功后验证码立即失效
+// 超过最大校验次数后验证码作废，需要重新获取
+func (s *VerificationCodeService) VerifyCode(ctx context.Context, purpose CodePurpose, phoneNumber, code string) error {
+	phoneNumber, err := s.userValidator.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+	codeKey := s.codeKey(purpose, phoneNumber)
+
+	// 先累加校验次数再读取，避免并发请求绕过次数限制
+	pipe := s.redisClient.TxPipeline()
+	attemptsCmd := pipe.HIncrBy(ctx, codeKey, "attempts", 1)
+	hashCmd := pipe.HGet(ctx, codeKey, "hash")
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return response.NewInternalServerError("校验验证码失败", err)
+	}
+
+	attempts := attemptsCmd.Val()
+	storedHash := hashCmd.Val()
+	if storedHash == "" {
+		// 验证码不存在或已过期，清理 HIncrBy 新建的无过期时间的键
+		s.redisClient.Del(ctx, codeKey)
+		return response.NewUnauthorizedError("验证码错误或已过期")
+	}
+	if attempts > int64(s.cfg.MaxAttempts) {
+		s.redisClient.Del(ctx, codeKey)
+		return response.NewUnauthorizedError("验证码错误次数过多，请重新获取")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s.codeHash(purpose, phoneNumber, code)), []byte(storedHash)) != 1 {
+		return response.NewUnauthorizedError("验证码错误或已过期").
+			WithContext("remaining_attempts", int64(s.cfg.MaxAttempts)-attempts)
+	}
+
+	s.redisClient.Del(ctx, codeKey)
+	return nil
+}
+
+// checkLimit 固定窗口计数限制
+func (s *VerificationCodeService) checkLimit(ctx context.Context, key string, limit int, window time.Duration) error {
+	count, err := s.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		return response.NewInternalServerError("发送验证码失败", err)
+	}
+	if count == 1 {
+		s.redisClient.Expire(ctx, key, window)
+	}
+	if count > int64(limit) {
+		ttl, _ := s.redisClient.TTL(ctx, key).Result()
+		return tooManyRequests("验证码发送次数已达上限，请稍后再试", ttl)
+	}
+	return nil
+}
+
+// codeKey 验证码记录的Redis键
+func (s *VerificationCodeService) codeKey(purpose CodePurpose, phoneNumber string) string {
+	return smsCodeKeyPrefix + string(purpose) + ":" + phoneNumber
+}
+
+// codeHash 计算验证码的HMAC，Redis中不保存验证码明文
+// 验证码只有几位数字，普通哈希可以直接穷举，因此使用服务端密钥并绑定用途和手机号
+func (s *VerificationCodeService) codeHash(purpose CodePurpose, phoneNumber, code string) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte(string(purpose) + ":" + phoneNumber + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// tooManyRequests 创建带重试等待时间的限流错误
+func tooManyRequests(message string, retryAfter time.Duration) *response.DomainError {
+	return response.NewTooManyRequestsError(message).WithRetryAfter(retryAfter)
+}
+
+// generateNumericCode 生成指定位数的随机数字验证码
+func generateNumericCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// hashCode 计算随机令牌的哈希值，存储中不保存令牌明文
+// 只用于足够长的随机令牌，位数较少的验证码使用 codeHash
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
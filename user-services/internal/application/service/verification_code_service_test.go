@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/response"
+	"user-services/internal/domain/user/validator"
+)
+
+// fakeSMSSender 记录每个手机号最近收到的验证码
+type fakeSMSSender struct {
+	codes map[string]string
+	sent  int
+}
+
+var smsCodePattern = regexp.MustCompile(`\d{6}`)
+
+func (s *fakeSMSSender) Send(ctx context.Context, phoneNumber, content string) error {
+	s.codes[phoneNumber] = smsCodePattern.FindString(content)
+	s.sent++
+	return nil
+}
+
+// testSMSCodeConfig 每个验证码最多校验3次，手机号每日3条，IP每小时4条
+var testSMSCodeConfig = config.SMSCodeConfig{
+	Length:          6,
+	TTL:             5 * time.Minute,
+	MaxAttempts:     3,
+	SendInterval:    time.Minute,
+	PhoneDailyLimit: 3,
+	IPHourlyLimit:   4,
+}
+
+func newTestVerificationCodeService(t *testing.T) (*miniredis.Miniredis, *fakeSMSSender, VerificationCodeServiceInterface) {
+	mr, redisClient := newTestRedis(t)
+	sender := &fakeSMSSender{codes: make(map[string]string)}
+	userValidator := validator.NewUserValidator(newFakeUserRepository(), validator.PasswordPolicy{}, validator.PhonePolicy{DefaultRegion: "CN"})
+
+	cfg := &config.Config{}
+	cfg.System.SecretKey = "test-secret"
+	cfg.SMS.Code = testSMSCodeConfig
+	return mr, sender, NewVerificationCodeService(redisClient, sender, userValidator, cfg)
+}
+
+// wrongCode 返回与验证码不同的同位数验证码
+func wrongCode(code string) string {
+	if code[0] == '0' {
+		return "1" + code[1:]
+	}
+	return "0" + code[1:]
+}
+
+func TestVerificationCodeService_SendAndVerify(t *testing.T) {
+	ctx := context.Background()
+	mr, sender, svc := newTestVerificationCodeService(t)
+
+	result, err := svc.SendCode(ctx, CodePurposeLogin, "138 0013 8000", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), result.ExpiresIn)
+	assert.Equal(t, int64(60), result.ResendAfter)
+
+	code := sender.codes["+8613800138000"]
+	require.Len(t, code, 6)
+
+	// Redis中既不保存明文，也不保存可直接穷举的普通哈希
+	stored := mr.HGet(smsCodeKeyPrefix+"login:+8613800138000", "hash")
+	require.NotEmpty(t, stored)
+	assert.NotEqual(t, code, stored)
+	assert.NotEqual(t, hashCode(code), stored)
+
+	// 不同用途的验证码互不通用
+	err = svc.VerifyCode(ctx, CodePurpose("other"), "+8613800138000", code)
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeUnauthorized), "got %v", err)
+
+	require.NoError(t, svc.VerifyCode(ctx, CodePurposeLogin, "+86 138 0013 8000", code))
+
+	// 校验成功后验证码立即失效
+	err = svc.VerifyCode(ctx, CodePurposeLogin, "13800138000", code)
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeUnauthorized), "got %v", err)
+}
+
+func TestVerificationCodeService_SendLimits(t *testing.T) {
+	type codeRequest struct {
+		phoneNumber string
+		clientIP    string
+	}
+
+	tests := []struct {
+		name           string
+		requests       []codeRequest
+		wait           time.Duration // 相邻两次请求之间经过的时间
+		wantRetryAfter int64         // 最后一次请求被限流时的重试等待时间，0表示不限流
+	}{
+		{
+			name:           "phone interval",
+			requests:       []codeRequest{{"13800138000", "10.0.0.1"}, {"13800138000", "10.0.0.2"}},
+			wantRetryAfter: 60,
+		},
+		{
+			name:     "phone interval elapsed",
+			requests: []codeRequest{{"13800138000", "10.0.0.1"}, {"13800138000", "10.0.0.1"}},
+			wait:     time.Minute,
+		},
+		{
+			name: "phone daily limit",
+			requests: []codeRequest{
+				{"13800138000", "10.0.0.1"}, {"13800138000", "10.0.0.2"},
+				{"13800138000", "10.0.0.3"}, {"+86 138 0013 8000", "10.0.0.4"},
+			},
+			wait:           time.Minute,
+			wantRetryAfter: int64((24*time.Hour - 3*time.Minute) / time.Second),
+		},
+		{
+			name: "ip hourly limit",
+			requests: []codeRequest{
+				{"13800138000", "10.0.0.1"}, {"13800138001", "10.0.0.1"}, {"13800138002", "10.0.0.1"},
+				{"13800138003", "10.0.0.1"}, {"13800138004", "10.0.0.1"},
+			},
+			wantRetryAfter: 3600,
+		},
+		{
+			name: "ip hourly limit on another ip",
+			requests: []codeRequest{
+				{"13800138000", "10.0.0.1"}, {"13800138001", "10.0.0.1"}, {"13800138002", "10.0.0.1"},
+				{"13800138003", "10.0.0.1"}, {"13800138004", "10.0.0.2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mr, sender, svc := newTestVerificationCodeService(t)
+
+			last := len(tt.requests) - 1
+			for i, req := range tt.requests[:last] {
+				_, err := svc.SendCode(ctx, CodePurposeLogin, req.phoneNumber, req.clientIP)
+				require.NoError(t, err, "request %d", i)
+				mr.FastForward(tt.wait)
+			}
+
+			_, err := svc.SendCode(ctx, CodePurposeLogin, tt.requests[last].phoneNumber, tt.requests[last].clientIP)
+			if tt.wantRetryAfter == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, len(tt.requests), sender.sent)
+				return
+			}
+			assertRetryAfterError(t, err, errorType(response.ErrorTypeTooManyRequests), tt.wantRetryAfter)
+			assert.Equal(t, last, sender.sent, "rate limited request must not send SMS")
+		})
+	}
+}
+
+func TestVerificationCodeService_Attempts(t *testing.T) {
+	tests := []struct {
+		name          string
+		wrongAttempts int
+		wantErr       *response.DomainError // 错误若干次之后再提交正确验证码的结果
+	}{
+		{name: "first attempt"},
+		{name: "within max attempts", wrongAttempts: 2},
+		{name: "attempts exhausted", wrongAttempts: 3, wantErr: response.NewUnauthorizedError("验证码错误次数过多，请重新获取")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			_, sender, svc := newTestVerificationCodeService(t)
+
+			_, err := svc.SendCode(ctx, CodePurposeLogin, "13800138000", "10.0.0.1")
+			require.NoError(t, err)
+			code := sender.codes["+8613800138000"]
+
+			for i := 1; i <= tt.wrongAttempts; i++ {
+				err := svc.VerifyCode(ctx, CodePurposeLogin, "13800138000", wrongCode(code))
+				var domainErr *response.DomainError
+				require.ErrorAs(t, err, &domainErr)
+				remaining, _ := domainErr.GetContextValue("remaining_attempts")
+				assert.Equal(t, int64(testSMSCodeConfig.MaxAttempts-i), remaining)
+			}
+
+			err = svc.VerifyCode(ctx, CodePurposeLogin, "13800138000", code)
+			if tt.wantErr != nil {
+				assertErrorMessage(t, tt.wantErr, err)
+
+				// 作废后必须重新获取验证码
+				err = svc.VerifyCode(ctx, CodePurposeLogin, "13800138000", code)
+				assertErrorMessage(t, response.NewUnauthorizedError("验证码错误或已过期"), err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package apikey
+
+import (
+	"go.uber.org/fx"
+
+	domainrepo "user-services/internal/domain/apikey/repository"
+	entrepo "user-services/internal/infrastructure/persistence/ent/repository"
+)
+
+// DomainModule API Key领域模块
+var DomainModule = fx.Module("apikey_domain",
+	fx.Provide(
+		// 仓储实现
+		fx.Annotate(
+			entrepo.NewAPIKeyRepository,
+			fx.As(new(domainrepo.APIKeyRepository)),
+		),
+	),
+)
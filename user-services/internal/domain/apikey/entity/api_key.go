@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"time"
+)
+
+// APIKey 服务间调用使用的长期凭证
+// 只保存密钥哈希，明文仅在创建时返回一次
+type APIKey struct {
+	id         string
+	name       string
+	prefix     string
+	keyHash    string
+	ownerID    string
+	scopes     []string
+	expiresAt  *time.Time
+	lastUsedAt *time.Time
+	revokedAt  *time.Time
+	createdAt  time.Time
+}
+
+// NewAPIKey 创建API Key，expiresAt为空表示永不过期
+func NewAPIKey(name, prefix, keyHash, ownerID string, scopes []string, expiresAt *time.Time) *APIKey {
+	return &APIKey{
+		name:      name,
+		prefix:    prefix,
+		keyHash:   keyHash,
+		ownerID:   ownerID,
+		scopes:    scopes,
+		expiresAt: expiresAt,
+	}
+}
+
+func (k *APIKey) ID() string {
+	return k.id
+}
+
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+func (k *APIKey) Prefix() string {
+	return k.prefix
+}
+
+func (k *APIKey) KeyHash() string {
+	return k.keyHash
+}
+
+func (k *APIKey) OwnerID() string {
+	return k.ownerID
+}
+
+func (k *APIKey) Scopes() []string {
+	return k.scopes
+}
+
+func (k *APIKey) ExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+func (k *APIKey) LastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
+
+func (k *APIKey) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// IsExpired 判断在给定时间是否已过期
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.expiresAt != nil && !now.Before(*k.expiresAt)
+}
+
+// IsRevoked 判断是否已被吊销
+func (k *APIKey) IsRevoked() bool {
+	return k.revokedAt != nil
+}
+
+// Revoke 吊销API Key，已吊销时保留首次吊销时间
+func (k *APIKey) Revoke(now time.Time) {
+	if k.revokedAt == nil {
+		k.revokedAt = &now
+	}
+}
+
+// MarkUsed 记录最近使用时间
+func (k *APIKey) MarkUsed(now time.Time) {
+	k.lastUsedAt = &now
+}
+
+func (k *APIKey) SetID(id string) {
+	k.id = id
+}
+
+func (k *APIKey) SetCreatedAt(createdAt time.Time) {
+	k.createdAt = createdAt
+}
+
+// SetUsage 从持久化数据恢复使用和吊销状态
+func (k *APIKey) SetUsage(lastUsedAt, revokedAt *time.Time) {
+	k.lastUsedAt = lastUsedAt
+	k.revokedAt = revokedAt
+}
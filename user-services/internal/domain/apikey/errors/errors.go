@@ -0,0 +1,11 @@
+package errors
+
+// API Key 相关错误消息常量
+const (
+	MsgAPIKeyNotFound       = "API Key不存在"
+	MsgInvalidAPIKeyID      = "无效的API Key ID"
+	MsgCreateAPIKeyFailed   = "创建API Key失败"
+	MsgUpdateAPIKeyFailed   = "更新API Key失败"
+	MsgQueryAPIKeyFailed    = "查询API Key失败"
+	MsgAPIKeyPrefixConflict = "API Key前缀冲突，请重试"
+)
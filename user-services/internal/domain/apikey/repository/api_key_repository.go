@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"user-services/internal/domain/apikey/entity"
+)
+
+// APIKeyRepository API Key仓储接口
+type APIKeyRepository interface {
+	// Create 保存API Key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByID 根据ID获取API Key
+	GetByID(ctx context.Context, id string) (*entity.APIKey, error)
+
+	// FindByPrefix 根据密钥前缀获取API Key
+	FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+
+	// List 按创建时间倒序列出API Key，ownerID为空时列出全部
+	List(ctx context.Context, ownerID string) ([]*entity.APIKey, error)
+
+	// UpdateRevokedAt 保存吊销时间
+	UpdateRevokedAt(ctx context.Context, key *entity.APIKey) error
+
+	// UpdateLastUsedAt 更新最近使用时间
+	UpdateLastUsedAt(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"go.uber.org/fx"
+
+	domainrepo "user-services/internal/domain/audit/repository"
+	entrepo "user-services/internal/infrastructure/persistence/ent/repository"
+)
+
+// DomainModule 审计日志领域模块
+var DomainModule = fx.Module("audit_domain",
+	fx.Provide(
+		// 仓储实现
+		fx.Annotate(
+			entrepo.NewAuditLogRepository,
+			fx.As(new(domainrepo.AuditLogRepository)),
+		),
+	),
+)
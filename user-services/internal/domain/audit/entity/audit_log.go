@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+)
+
+// 审计操作类型
+const (
+	ActionPolicyAdd    = "policy.add"
+	ActionPolicyRemove = "policy.remove"
+	ActionRoleAssign   = "role.assign"
+	ActionRoleUnassign = "role.unassign"
+	ActionPolicyApply  = "policy.apply" // 通过策略文件整体同步
+)
+
+// ActorSystem 无法确定操作者(如命令行或后台任务)时记录的操作者
+const ActorSystem = "system"
+
+// AuditLog 管理操作审计记录，写入后不可修改
+type AuditLog struct {
+	id        string
+	actorID   string
+	domain    string
+	action    string
+	resource  string
+	detail    map[string]any
+	clientIP  string
+	createdAt time.Time
+}
+
+// NewAuditLog 创建审计记录，domain 为变更所属租户
+func NewAuditLog(actorID, domain, action, resource string, detail map[string]any, clientIP string) *AuditLog {
+	return &AuditLog{
+		actorID:  actorID,
+		domain:   domain,
+		action:   action,
+		resource: resource,
+		detail:   detail,
+		clientIP: clientIP,
+	}
+}
+
+func (l *AuditLog) ID() string {
+	return l.id
+}
+
+func (l *AuditLog) ActorID() string {
+	return l.actorID
+}
+
+func (l *AuditLog) Domain() string {
+	return l.domain
+}
+
+func (l *AuditLog) Action() string {
+	return l.action
+}
+
+func (l *AuditLog) Resource() string {
+	return l.resource
+}
+
+func (l *AuditLog) Detail() map[string]any {
+	return l.detail
+}
+
+func (l *AuditLog) ClientIP() string {
+	return l.clientIP
+}
+
+func (l *AuditLog) CreatedAt() time.Time {
+	return l.createdAt
+}
+
+func (l *AuditLog) SetID(id string) {
+	l.id = id
+}
+
+func (l *AuditLog) SetCreatedAt(createdAt time.Time) {
+	l.createdAt = createdAt
+}
@@ -0,0 +1,7 @@
+package errors
+
+// 审计日志相关错误消息常量
+const (
+	MsgCreateAuditLogFailed = "写入审计日志失败"
+	MsgQueryAuditLogFailed  = "查询审计日志失败"
+)
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"user-services/internal/domain/audit/entity"
+)
+
+// AuditLogFilter 审计日志查询条件，零值字段不参与过滤
+type AuditLogFilter struct {
+	Domain    string
+	ActorID   string
+	Action    string
+	Resource  string
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// AuditLogRepository 审计日志仓储接口
+type AuditLogRepository interface {
+	// Create 写入审计记录
+	Create(ctx context.Context, log *entity.AuditLog) error
+
+	// List 按时间倒序分页查询审计记录，返回当前页记录和总数
+	List(ctx context.Context, filter AuditLogFilter, offset, limit int) ([]*entity.AuditLog, int64, error)
+}
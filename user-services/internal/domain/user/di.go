@@ -13,6 +13,8 @@ import (
 var DomainModule = fx.Module("domain",
 	fx.Provide(
 		// 验证器
+		validator.NewPasswordPolicy,
+		validator.NewPhonePolicy,
 		validator.NewUserValidator,
 
 		// 领域服务
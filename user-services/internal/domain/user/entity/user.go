@@ -2,6 +2,16 @@ package entity
 
 import (
 	"time"
+
+	userErrors "user-services/internal/domain/user/errors"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// 支持部分更新的用户字段，与接口的JSON字段名一致
+const (
+	FieldName        = "name"
+	FieldGender      = "gender"
+	FieldPhoneNumber = "phone_number"
 )
 
 // User 用户聚合根
@@ -12,8 +22,16 @@ type User struct {
 	gender      int
 	phoneNumber string
 	password    string
+	status      valueobject.UserStatus
 	createdAt   time.Time
 	updatedAt   time.Time
+
+	passwordHistory []string // 历史密码哈希，最近使用的在前
+
+	// 两步验证
+	totpSecret         string   // 加密后的TOTP密钥
+	totpEnabled        bool     // 是否已启用
+	recoveryCodeHashes []string // 恢复码哈希
 }
 
 // NewUser 创建新用户
@@ -24,6 +42,7 @@ func NewUser(openID, name, phoneNumber, password string, gender int) *User {
 		gender:      gender,
 		phoneNumber: phoneNumber,
 		password:    password,
+		status:      valueobject.UserStatusActive,
 	}
 }
 
@@ -51,6 +70,90 @@ func (u *User) Password() string {
 	return u.password
 }
 
+func (u *User) Status() valueobject.UserStatus {
+	return u.status
+}
+
+// EnsureCanLogin 只有正常状态的账号可以登录，其他状态返回对应的错误
+func (u *User) EnsureCanLogin() error {
+	switch u.status {
+	case valueobject.UserStatusActive:
+		return nil
+	case valueobject.UserStatusLocked:
+		return userErrors.ErrUserLocked
+	case valueobject.UserStatusPending:
+		return userErrors.ErrUserPending
+	default:
+		return userErrors.ErrUserInactive
+	}
+}
+
+// Enable 启用账号，待激活、已锁定和已停用的账号均恢复为正常
+func (u *User) Enable() error {
+	return u.transitionTo(valueobject.UserStatusActive)
+}
+
+// Disable 停用账号
+func (u *User) Disable() error {
+	return u.transitionTo(valueobject.UserStatusDisabled)
+}
+
+// Lock 锁定账号
+func (u *User) Lock() error {
+	return u.transitionTo(valueobject.UserStatusLocked)
+}
+
+// transitionTo 按状态机变更状态，已处于目标状态时不做任何修改
+func (u *User) transitionTo(target valueobject.UserStatus) error {
+	if u.status == target {
+		return nil
+	}
+	if !u.status.CanTransitionTo(target) {
+		return userErrors.ErrInvalidStatusTransition.
+			WithContext("from", u.status.String()).
+			WithContext("to", target.String())
+	}
+	u.status = target
+	return nil
+}
+
+func (u *User) PasswordHistory() []string {
+	return u.passwordHistory
+}
+
+// RecentPasswordHashes 返回最近使用的n个密码哈希，包括当前密码
+func (u *User) RecentPasswordHashes(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	hashes := make([]string, 0, n)
+	if u.password != "" {
+		hashes = append(hashes, u.password)
+	}
+	for _, hash := range u.passwordHistory {
+		if len(hashes) >= n {
+			break
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// ChangePassword 设置新的密码哈希，当前密码移入历史，历史连同新密码最多保留historySize个
+func (u *User) ChangePassword(passwordHash string, historySize int) {
+	if historySize > 1 {
+		u.passwordHistory = u.RecentPasswordHashes(historySize - 1)
+	} else {
+		u.passwordHistory = nil
+	}
+	u.password = passwordHash
+}
+
+// CreatedAt 创建时间
+func (u *User) CreatedAt() time.Time {
+	return u.createdAt
+}
+
 func (u *User) GetCreatedAt() int64 {
 	return u.createdAt.UnixMilli()
 }
@@ -59,6 +162,64 @@ func (u *User) GetUpdatedAt() int64 {
 	return u.updatedAt.UnixMilli()
 }
 
+func (u *User) TOTPSecret() string {
+	return u.totpSecret
+}
+
+func (u *User) TOTPEnabled() bool {
+	return u.totpEnabled
+}
+
+func (u *User) RecoveryCodeHashes() []string {
+	return u.recoveryCodeHashes
+}
+
+// SetupTOTP 绑定新的TOTP密钥，需确认后才会启用
+func (u *User) SetupTOTP(encryptedSecret string) {
+	u.totpSecret = encryptedSecret
+	u.totpEnabled = false
+	u.recoveryCodeHashes = nil
+}
+
+// EnableTOTP 启用两步验证并设置恢复码
+func (u *User) EnableTOTP(recoveryCodeHashes []string) {
+	u.totpEnabled = true
+	u.recoveryCodeHashes = recoveryCodeHashes
+}
+
+// DisableTOTP 关闭两步验证并清除密钥和恢复码
+func (u *User) DisableTOTP() {
+	u.totpSecret = ""
+	u.totpEnabled = false
+	u.recoveryCodeHashes = nil
+}
+
+// UseRecoveryCode 消耗一个恢复码，恢复码不存在时返回false
+func (u *User) UseRecoveryCode(codeHash string) bool {
+	for i, hash := range u.recoveryCodeHashes {
+		if hash == codeHash {
+			u.recoveryCodeHashes = append(u.recoveryCodeHashes[:i:i], u.recoveryCodeHashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Rename 修改姓名
+func (u *User) Rename(name string) {
+	u.name = name
+}
+
+// ChangeGender 修改性别
+func (u *User) ChangeGender(gender int) {
+	u.gender = gender
+}
+
+// BindPhoneNumber 绑定手机号，空字符串表示解绑
+func (u *User) BindPhoneNumber(phoneNumber string) {
+	u.phoneNumber = phoneNumber
+}
+
 func (u *User) SetID(id string) {
 	u.id = id
 }
@@ -70,3 +231,20 @@ func (u *User) SetCreatedAt(createdAt time.Time) {
 func (u *User) SetUpdatedAt(updatedAt time.Time) {
 	u.updatedAt = updatedAt
 }
+
+// SetStatus 从持久化数据恢复账号状态
+func (u *User) SetStatus(status valueobject.UserStatus) {
+	u.status = status
+}
+
+// SetPasswordHistory 从持久化数据恢复历史密码
+func (u *User) SetPasswordHistory(hashes []string) {
+	u.passwordHistory = hashes
+}
+
+// SetTOTP 从持久化数据恢复两步验证状态
+func (u *User) SetTOTP(encryptedSecret string, enabled bool, recoveryCodeHashes []string) {
+	u.totpSecret = encryptedSecret
+	u.totpEnabled = enabled
+	u.recoveryCodeHashes = recoveryCodeHashes
+}
@@ -1,27 +1,42 @@
 package errors
 
 import (
+	"fmt"
+	"strings"
+
 	"common/response"
 )
 
 // 用户相关错误消息常量
 const (
-	MsgUserNotFound           = "用户不存在"
-	MsgUserAlreadyExists      = "用户已存在"
-	MsgPhoneAlreadyExists     = "手机号已存在"
-	MsgInvalidUserID          = "无效的用户ID"
-	MsgCreateUserFailed       = "创建用户失败"
-	MsgUpdateUserFailed       = "更新用户失败"
-	MsgQueryUserFailed        = "查询用户失败"
-	MsgQueryUserListFailed    = "查询用户列表失败"
-	MsgQueryUserCountFailed   = "查询用户总数失败"
-	MsgCheckPhoneExistsFailed = "查询用户手机号是否存在失败"
-	MsgFindUserByPhoneFailed  = "通过手机号查询用户失败"
+	MsgUserNotFound            = "用户不存在"
+	MsgUserAlreadyExists       = "用户已存在"
+	MsgPhoneAlreadyExists      = "手机号已存在"
+	MsgInvalidUserID           = "无效的用户ID"
+	MsgCreateUserFailed        = "创建用户失败"
+	MsgUpdateUserFailed        = "更新用户失败"
+	MsgQueryUserFailed         = "查询用户失败"
+	MsgQueryUserListFailed     = "查询用户列表失败"
+	MsgQueryUserCountFailed    = "查询用户总数失败"
+	MsgCheckPhoneExistsFailed  = "查询用户手机号是否存在失败"
+	MsgFindUserByPhoneFailed   = "通过手机号查询用户失败"
+	MsgFindUserByOpenIDFailed  = "通过open_id查询用户失败"
+	MsgCheckOpenIDExistsFailed = "查询用户open_id是否存在失败"
+	MsgDeleteUserFailed        = "删除用户失败"
+	MsgDeletedUserNotFound     = "已删除的用户不存在"
+	MsgRestoreUserConflict     = "手机号或open_id已被其他用户使用，无法恢复"
+	MsgRestoreUserFailed       = "恢复用户失败"
+	MsgPurgeUsersFailed        = "彻底删除用户失败"
 )
 
 // 用户相关错误
 var (
-	ErrUserInactive = response.NewInvalidDataError("用户已停用")
+	ErrUserInactive            = response.NewInvalidDataError("用户已停用")
+	ErrUserLocked              = response.NewInvalidDataError("账号已锁定")
+	ErrUserPending             = response.NewInvalidDataError("账号未激活")
+	ErrInvalidStatusTransition = response.NewBusinessRuleViolationError("当前账号状态不允许该操作")
+	ErrFieldNotUpdatable       = response.NewValidationError("该字段不支持修改")
+	ErrInvalidCursor           = response.NewValidationError("无效的分页游标")
 )
 
 // 用户验证错误
@@ -30,6 +45,7 @@ var (
 	ErrInvalidPhone   = response.NewValidationError("无效的手机号")
 	ErrPhoneRequired  = response.NewValidationError("手机号必填")
 	ErrPhoneNotUnique = response.NewValidationError("手机号已被使用")
+	ErrPhoneRegion    = response.NewValidationError("不支持该国家或地区的手机号")
 	// 姓名
 	ErrInvalidNickname   = response.NewValidationError("无效的昵称")
 	ErrNicknameRequired  = response.NewValidationError("昵称必填")
@@ -37,14 +53,48 @@ var (
 	ErrInvalidNameFormat = response.NewValidationError("姓名格式不正确")
 	// 密码
 	ErrPasswordRequired      = response.NewValidationError("密码不能为空")
-	ErrPasswordTooShort      = response.NewValidationError("密码长度不能少于6位")
-	ErrPasswordTooLong       = response.NewValidationError("密码长度不能超过20位")
-	ErrPasswordTooWeak       = response.NewValidationError("密码强度不够，需要包含字母和数字")
+	ErrPasswordIncorrect     = response.NewValidationError("原密码错误")
+	ErrPasswordNotSet        = response.NewBusinessRuleViolationError("当前账号未设置密码，请通过找回密码设置")
 	ErrPasswordHashingFailed = response.NewBusinessRuleViolationError("密码处理失败")
 	// 性别
 	ErrInvalidGender = response.NewValidationError("无效的性别")
 )
 
+// NewPasswordTooShortError 密码短于策略要求的最小长度
+func NewPasswordTooShortError(minLength int) *response.DomainError {
+	return response.NewValidationError(fmt.Sprintf("密码长度不能少于%d位", minLength)).
+		WithContext("min_length", minLength)
+}
+
+// NewPasswordTooLongError 密码超过策略允许的最大长度
+func NewPasswordTooLongError(maxLength int) *response.DomainError {
+	return response.NewValidationError(fmt.Sprintf("密码长度不能超过%d位", maxLength)).
+		WithContext("max_length", maxLength)
+}
+
+// NewPasswordTooWeakError 密码缺少策略要求的字符类型
+func NewPasswordTooWeakError(required []string) *response.DomainError {
+	return response.NewValidationError(fmt.Sprintf("密码强度不够，需要包含%s", joinWithAnd(required))).
+		WithContext("required", required)
+}
+
+// NewPasswordReusedError 新密码与最近使用过的密码相同
+func NewPasswordReusedError(historySize int) *response.DomainError {
+	return response.NewValidationError(fmt.Sprintf("新密码不能与最近%d次使用过的密码相同", historySize)).
+		WithContext("history_size", historySize)
+}
+
+// joinWithAnd 以顿号连接，最后一项使用"和"
+func joinWithAnd(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], "、") + "和" + items[len(items)-1]
+}
+
 // 用户业务规则错误
 var (
 	ErrUserCannotJoinTeam    = response.NewBusinessRuleViolationError("用户当前状态无法加入团队")
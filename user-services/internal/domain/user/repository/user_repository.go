@@ -4,17 +4,11 @@ import (
 	"context"
 	"time"
 
+	"common/pkg/filter"
+	"common/pkg/pagination"
 	"user-services/internal/domain/user/entity"
 )
 
-// UserListFilter 用户列表过滤条件
-type UserListFilter struct {
-	Name      *string    // 姓名模糊查询
-	Gender    *int       // 性别过滤
-	StartTime *time.Time // 创建时间开始
-	EndTime   *time.Time // 创建时间结束
-}
-
 // UserRepository 用户仓储接口
 type UserRepository interface {
 	// Create 创建用户
@@ -23,18 +17,62 @@ type UserRepository interface {
 	// List 分页查询用户列表
 	List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error)
 
-	// ListWithFilter 带过滤条件的分页查询用户列表
-	ListWithFilter(ctx context.Context, filter *UserListFilter, offset, limit int) ([]*entity.User, int64, error)
+	// ListWithFilter 按过滤和排序条件分页查询用户列表
+	ListWithFilter(ctx context.Context, q *filter.Query, offset, limit int) ([]*entity.User, int64, error)
+
+	// ListWithCursor 按 (created_at, id) 倒序的键集分页查询，忽略排序条件，after 为空时从第一条开始
+	ListWithCursor(ctx context.Context, q *filter.Query, after *pagination.Cursor, limit int) ([]*entity.User, error)
+
+	// IterateWithFilter 按 (created_at, id) 倒序逐个读取符合过滤条件的用户，每次从数据库读取 batchSize 个
+	// fn 返回错误时停止读取并返回该错误
+	IterateWithFilter(ctx context.Context, q *filter.Query, batchSize int, fn func(*entity.User) error) error
+
+	// CountWithFilter 统计符合过滤条件的用户数
+	CountWithFilter(ctx context.Context, q *filter.Query) (int64, error)
+
+	// CreateBatch 批量创建用户，任一用户失败时全部不写入
+	CreateBatch(ctx context.Context, users []*entity.User) error
 
 	// ExistsByPhoneNumber 根据手机号查询用户是否存在
 	ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error)
 
+	// ExistsByOpenID 根据open_id查询用户是否存在
+	ExistsByOpenID(ctx context.Context, openID string) (bool, error)
+
 	// Update 更新用户信息
 	Update(ctx context.Context, user *entity.User) error
 
+	// UpdateStatus 更新账号状态
+	UpdateStatus(ctx context.Context, user *entity.User) error
+
+	// UpdateTOTP 更新用户的两步验证状态
+	UpdateTOTP(ctx context.Context, user *entity.User) error
+
+	// UpdateRecoveryCodes 仅当已保存的恢复码仍为 expected 时写入用户当前的恢复码
+	// 返回false表示恢复码已被其他请求修改，未写入
+	UpdateRecoveryCodes(ctx context.Context, user *entity.User, expected []string) (bool, error)
+
+	// UpdatePassword 更新用户密码和历史密码
+	UpdatePassword(ctx context.Context, user *entity.User) error
+
 	// GetByID 根据ID获取用户
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 
 	// FindByPhoneNumber 根据手机号获取用户
 	FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entity.User, error)
+
+	// FindByOpenID 根据第三方平台open_id获取用户
+	FindByOpenID(ctx context.Context, openID string) (*entity.User, error)
+
+	// SoftDelete 软删除用户，删除后的用户不会被查询到，手机号和open_id可以被重新注册
+	SoftDelete(ctx context.Context, id string) error
+
+	// Restore 恢复已软删除的用户
+	Restore(ctx context.Context, id string) (*entity.User, error)
+
+	// CountDeletedBefore 统计在指定时间之前软删除的用户数
+	CountDeletedBefore(ctx context.Context, before time.Time) (int, error)
+
+	// PurgeDeletedBefore 彻底删除在指定时间之前软删除的用户，返回删除的行数
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error)
 }
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -9,24 +11,46 @@ import (
 	userErrors "user-services/internal/domain/user/errors"
 	"user-services/internal/domain/user/repository"
 	"user-services/internal/domain/user/validator"
+	"user-services/internal/domain/user/valueobject"
 )
 
 // UserDomainService 用户领域服务
 type UserDomainService struct {
-	userRepo      repository.UserRepository
-	userValidator validator.UserValidator
+	userRepo       repository.UserRepository
+	userValidator  validator.UserValidator
+	passwordPolicy validator.PasswordPolicy
 }
 
 // NewUserDomainService 创建用户领域服务
-func NewUserDomainService(userRepo repository.UserRepository, userValidator validator.UserValidator) *UserDomainService {
+func NewUserDomainService(userRepo repository.UserRepository, userValidator validator.UserValidator, passwordPolicy validator.PasswordPolicy) *UserDomainService {
 	return &UserDomainService{
-		userRepo:      userRepo,
-		userValidator: userValidator,
+		userRepo:       userRepo,
+		userValidator:  userValidator,
+		passwordPolicy: passwordPolicy,
 	}
 }
 
 // CreateUser 创建用户
 func (s *UserDomainService) CreateUser(ctx context.Context, openID, name, phoneNumber, password string, gender int) (*entity.User, error) {
+	user, err := s.PrepareUser(ctx, openID, name, phoneNumber, password, gender)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// PrepareUser 按创建用户的规则校验并构造用户，不保存
+// 批量导入先逐个构造，再通过 CreateUsers 分批保存
+func (s *UserDomainService) PrepareUser(ctx context.Context, openID, name, phoneNumber, password string, gender int) (*entity.User, error) {
+	phoneNumber, err := s.userValidator.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
 	if err := s.userValidator.ValidateForCreation(ctx, phoneNumber, password, name, gender); err != nil {
 		return nil, err
 	}
@@ -37,10 +61,216 @@ func (s *UserDomainService) CreateUser(ctx context.Context, openID, name, phoneN
 		return nil, userErrors.ErrPasswordHashingFailed
 	}
 
-	user := entity.NewUser(openID, name, phoneNumber, string(hashedPassword), gender)
+	return entity.NewUser(openID, name, phoneNumber, string(hashedPassword), gender), nil
+}
+
+// CreateUsers 批量保存由 PrepareUser 构造的用户，任一用户失败时整批不写入
+func (s *UserDomainService) CreateUsers(ctx context.Context, users []*entity.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	return s.userRepo.CreateBatch(ctx, users)
+}
+
+// NormalizePhoneNumber 将手机号转换为保存时使用的E.164格式，按手机号查找用户前应先转换
+func (s *UserDomainService) NormalizePhoneNumber(phoneNumber string) (string, error) {
+	return s.userValidator.NormalizePhoneNumber(phoneNumber)
+}
+
+// OpenIDExists 查询open_id是否已被使用
+func (s *UserDomainService) OpenIDExists(ctx context.Context, openID string) (bool, error) {
+	return s.userRepo.ExistsByOpenID(ctx, openID)
+}
+
+// RegisterByWeChat 为首次登录的微信用户创建账号
+// 微信账号没有密码和手机号，使用open_id后缀生成默认昵称
+func (s *UserDomainService) RegisterByWeChat(ctx context.Context, openID string) (*entity.User, error) {
+	suffix := openID
+	if len(suffix) > 6 {
+		suffix = suffix[len(suffix)-6:]
+	}
+	name := fmt.Sprintf("微信用户%s", suffix)
+
+	user := entity.NewUser(openID, name, "", "", valueobject.GenderOther.Int())
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
+
+// BindPhoneNumber 为用户绑定手机号，手机号不能已被其他用户使用
+func (s *UserDomainService) BindPhoneNumber(ctx context.Context, userID, phoneNumber string) (*entity.User, error) {
+	phoneNumber, err := s.userValidator.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.PhoneNumber() == phoneNumber {
+		return user, nil
+	}
+
+	exists, err := s.userRepo.ExistsByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, userErrors.ErrPhoneNotUnique
+	}
+
+	user.BindPhoneNumber(phoneNumber)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser 按JSON合并语义部分更新用户资料：updates中没有的字段保持不变，值为nil表示清空
+// 返回实际发生变化的字段及其新值，没有变化时不写库
+func (s *UserDomainService) UpdateUser(ctx context.Context, userID string, updates map[string]interface{}) (*entity.User, map[string]interface{}, error) {
+	for field := range updates {
+		switch field {
+		case entity.FieldName, entity.FieldGender, entity.FieldPhoneNumber:
+		default:
+			return nil, nil, userErrors.ErrFieldNotUpdatable.WithContext("field", field)
+		}
+	}
+
+	// 非空手机号先转换为E.164格式，唯一性校验和保存都使用转换后的号码
+	if phoneNumber, ok := updates[entity.FieldPhoneNumber].(string); ok {
+		normalized, err := s.userValidator.NormalizePhoneNumber(phoneNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		updates[entity.FieldPhoneNumber] = normalized
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.userValidator.ValidateForUpdate(ctx, userID, updates); err != nil {
+		return nil, nil, err
+	}
+
+	changes := make(map[string]interface{})
+	if value, ok := updates[entity.FieldName]; ok {
+		if name := value.(string); name != user.Name() {
+			user.Rename(name)
+			changes[entity.FieldName] = name
+		}
+	}
+	if value, ok := updates[entity.FieldGender]; ok {
+		if gender := value.(int); gender != user.Gender() {
+			user.ChangeGender(gender)
+			changes[entity.FieldGender] = gender
+		}
+	}
+	if value, ok := updates[entity.FieldPhoneNumber]; ok {
+		phoneNumber, _ := value.(string)
+		// 手机号是密码登录的账号，没有绑定第三方平台时解绑会导致无法登录
+		if phoneNumber == "" && user.OpenID() == "" {
+			return nil, nil, userErrors.ErrPhoneRequired
+		}
+		if phoneNumber != user.PhoneNumber() {
+			user.BindPhoneNumber(phoneNumber)
+			changes[entity.FieldPhoneNumber] = value
+		}
+	}
+
+	if len(changes) == 0 {
+		return user, changes, nil
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	return user, changes, nil
+}
+
+// DeleteUser 软删除用户，保留数据以便恢复，超过保留期后由 PurgeDeletedUsers 彻底删除
+func (s *UserDomainService) DeleteUser(ctx context.Context, userID string) error {
+	return s.userRepo.SoftDelete(ctx, userID)
+}
+
+// RestoreUser 恢复已软删除的用户
+func (s *UserDomainService) RestoreUser(ctx context.Context, userID string) (*entity.User, error) {
+	return s.userRepo.Restore(ctx, userID)
+}
+
+// PurgeDeletedUsers 彻底删除在 before 之前软删除的用户，dryRun 时只统计不删除
+func (s *UserDomainService) PurgeDeletedUsers(ctx context.Context, before time.Time, dryRun bool) (int, error) {
+	if dryRun {
+		return s.userRepo.CountDeletedBefore(ctx, before)
+	}
+	return s.userRepo.PurgeDeletedBefore(ctx, before)
+}
+
+// EnableUser 启用账号
+func (s *UserDomainService) EnableUser(ctx context.Context, userID string) (*entity.User, error) {
+	return s.changeStatus(ctx, userID, (*entity.User).Enable)
+}
+
+// DisableUser 停用账号，停用后无法登录
+func (s *UserDomainService) DisableUser(ctx context.Context, userID string) (*entity.User, error) {
+	return s.changeStatus(ctx, userID, (*entity.User).Disable)
+}
+
+// changeStatus 由聚合根校验状态变更是否合法，状态未变化时不写库
+func (s *UserDomainService) changeStatus(ctx context.Context, userID string, transition func(*entity.User) error) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.Status()
+	if err := transition(user); err != nil {
+		return nil, err
+	}
+	if user.Status() == previous {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateStatus(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// VerifyPassword 校验用户当前密码
+func (s *UserDomainService) VerifyPassword(user *entity.User, password string) error {
+	if user.Password() == "" {
+		return userErrors.ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password()), []byte(password)); err != nil {
+		return userErrors.ErrPasswordIncorrect
+	}
+	return nil
+}
+
+// ValidatePasswordChange 校验新密码是否符合密码策略且未被最近使用过，不修改用户
+func (s *UserDomainService) ValidatePasswordChange(user *entity.User, newPassword string) error {
+	recent := user.RecentPasswordHashes(s.passwordPolicy.HistorySize)
+	return s.userValidator.ValidatePasswordChange(newPassword, recent)
+}
+
+// ChangePassword 按密码策略设置新密码，新密码不能与最近使用过的密码相同
+func (s *UserDomainService) ChangePassword(ctx context.Context, user *entity.User, newPassword string) error {
+	if err := s.ValidatePasswordChange(user, newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return userErrors.ErrPasswordHashingFailed
+	}
+
+	user.ChangePassword(string(hashedPassword), s.passwordPolicy.HistorySize)
+	return s.userRepo.UpdatePassword(ctx, user)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/response"
+	"user-services/internal/domain/user/entity"
+	userErrors "user-services/internal/domain/user/errors"
+	"user-services/internal/domain/user/repository"
+	"user-services/internal/domain/user/validator"
+	"user-services/internal/domain/user/valueobject"
+)
+
+// fakeUserRepository 内存中的用户仓储，只实现领域服务用到的方法
+type fakeUserRepository struct {
+	repository.UserRepository
+	users         map[string]*entity.User
+	updates       int
+	statusUpdates int
+}
+
+func newFakeUserRepository(users ...*entity.User) *fakeUserRepository {
+	repo := &fakeUserRepository{users: make(map[string]*entity.User)}
+	for _, user := range users {
+		repo.users[user.ID()] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, response.NewNotFoundError(userErrors.MsgUserNotFound)
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.PhoneNumber() == phoneNumber {
+			return user, nil
+		}
+	}
+	return nil, response.NewNotFoundError(userErrors.MsgUserNotFound)
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.updates++
+	return nil
+}
+
+func (r *fakeUserRepository) UpdateStatus(ctx context.Context, user *entity.User) error {
+	r.statusUpdates++
+	return nil
+}
+
+func newTestUser(id, openID, phoneNumber string) *entity.User {
+	user := entity.NewUser(openID, "张三", phoneNumber, "", valueobject.GenderMale.Int())
+	user.SetID(id)
+	return user
+}
+
+func newTestDomainService(repo repository.UserRepository) *UserDomainService {
+	passwordPolicy := validator.PasswordPolicy{MinLength: 8, MaxLength: 72}
+	phonePolicy := validator.PhonePolicy{DefaultRegion: "CN"}
+	return NewUserDomainService(repo, validator.NewUserValidator(repo, passwordPolicy, phonePolicy), passwordPolicy)
+}
+
+// assertDomainError 断言返回的是指定的领域错误，领域错误附加上下文后是新实例，按消息比较
+func assertDomainError(t *testing.T, want *response.DomainError, err error) {
+	t.Helper()
+	var domainErr *response.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, want.Type, domainErr.Type)
+	assert.Equal(t, want.Message, domainErr.Message)
+}
+
+func TestUserDomainService_UpdateUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		openID      string
+		updates     map[string]interface{}
+		wantErr     *response.DomainError
+		wantChanges map[string]interface{}
+		wantName    string
+		wantGender  int
+		wantPhone   string
+	}{
+		{
+			name:        "rename",
+			updates:     map[string]interface{}{entity.FieldName: "李四"},
+			wantChanges: map[string]interface{}{entity.FieldName: "李四"},
+			wantName:    "李四",
+			wantGender:  valueobject.GenderMale.Int(),
+			wantPhone:   "+8613800138000",
+		},
+		{
+			name:        "missing keys stay unchanged",
+			updates:     map[string]interface{}{entity.FieldGender: valueobject.GenderFemale.Int()},
+			wantChanges: map[string]interface{}{entity.FieldGender: valueobject.GenderFemale.Int()},
+			wantName:    "张三",
+			wantGender:  valueobject.GenderFemale.Int(),
+			wantPhone:   "+8613800138000",
+		},
+		{
+			name:        "phone normalized before saving",
+			updates:     map[string]interface{}{entity.FieldPhoneNumber: "139 0013 9000"},
+			wantChanges: map[string]interface{}{entity.FieldPhoneNumber: "+8613900139000"},
+			wantName:    "张三",
+			wantGender:  valueobject.GenderMale.Int(),
+			wantPhone:   "+8613900139000",
+		},
+		{
+			name:        "nil clears phone with open_id",
+			openID:      "wx-openid",
+			updates:     map[string]interface{}{entity.FieldPhoneNumber: nil},
+			wantChanges: map[string]interface{}{entity.FieldPhoneNumber: nil},
+			wantName:    "张三",
+			wantGender:  valueobject.GenderMale.Int(),
+			wantPhone:   "",
+		},
+		{
+			name:    "clearing phone without open_id refused",
+			updates: map[string]interface{}{entity.FieldPhoneNumber: nil},
+			wantErr: userErrors.ErrPhoneRequired,
+		},
+		{
+			name:    "nil name refused",
+			updates: map[string]interface{}{entity.FieldName: nil},
+			wantErr: userErrors.ErrNicknameRequired,
+		},
+		{
+			name:    "unknown field refused",
+			updates: map[string]interface{}{entity.FieldName: "李四", "status": "disabled"},
+			wantErr: userErrors.ErrFieldNotUpdatable,
+		},
+		{
+			name:    "phone used by another user",
+			updates: map[string]interface{}{entity.FieldPhoneNumber: "13700137000"},
+			wantErr: userErrors.ErrPhoneNotUnique,
+		},
+		{
+			name: "unchanged values not written",
+			updates: map[string]interface{}{
+				entity.FieldName:        "张三",
+				entity.FieldGender:      valueobject.GenderMale.Int(),
+				entity.FieldPhoneNumber: "13800138000",
+			},
+			wantChanges: map[string]interface{}{},
+			wantName:    "张三",
+			wantGender:  valueobject.GenderMale.Int(),
+			wantPhone:   "+8613800138000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeUserRepository(
+				newTestUser("u1", tt.openID, "+8613800138000"),
+				newTestUser("u2", "", "+8613700137000"),
+			)
+			svc := newTestDomainService(repo)
+
+			user, changes, err := svc.UpdateUser(context.Background(), "u1", tt.updates)
+			if tt.wantErr != nil {
+				assertDomainError(t, tt.wantErr, err)
+				assert.Zero(t, repo.updates)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanges, changes)
+			assert.Equal(t, tt.wantName, user.Name())
+			assert.Equal(t, tt.wantGender, user.Gender())
+			assert.Equal(t, tt.wantPhone, user.PhoneNumber())
+
+			wantUpdates := 1
+			if len(tt.wantChanges) == 0 {
+				wantUpdates = 0
+			}
+			assert.Equal(t, wantUpdates, repo.updates)
+		})
+	}
+}
+
+func TestUserDomainService_ChangeStatus(t *testing.T) {
+	enable := (*UserDomainService).EnableUser
+	disable := (*UserDomainService).DisableUser
+
+	tests := []struct {
+		name       string
+		from       valueobject.UserStatus
+		change     func(*UserDomainService, context.Context, string) (*entity.User, error)
+		want       valueobject.UserStatus
+		wantWrites int
+	}{
+		{name: "disable active", from: valueobject.UserStatusActive, change: disable, want: valueobject.UserStatusDisabled, wantWrites: 1},
+		{name: "disable pending", from: valueobject.UserStatusPending, change: disable, want: valueobject.UserStatusDisabled, wantWrites: 1},
+		{name: "disable locked", from: valueobject.UserStatusLocked, change: disable, want: valueobject.UserStatusDisabled, wantWrites: 1},
+		{name: "disable disabled is a no-op", from: valueobject.UserStatusDisabled, change: disable, want: valueobject.UserStatusDisabled},
+		{name: "enable disabled", from: valueobject.UserStatusDisabled, change: enable, want: valueobject.UserStatusActive, wantWrites: 1},
+		{name: "enable pending", from: valueobject.UserStatusPending, change: enable, want: valueobject.UserStatusActive, wantWrites: 1},
+		{name: "enable locked", from: valueobject.UserStatusLocked, change: enable, want: valueobject.UserStatusActive, wantWrites: 1},
+		{name: "enable active is a no-op", from: valueobject.UserStatusActive, change: enable, want: valueobject.UserStatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser("u1", "", "+8613800138000")
+			user.SetStatus(tt.from)
+			repo := newFakeUserRepository(user)
+			svc := newTestDomainService(repo)
+
+			got, err := tt.change(svc, context.Background(), "u1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Status())
+			assert.Equal(t, tt.wantWrites, repo.statusUpdates)
+		})
+	}
+
+	t.Run("missing user", func(t *testing.T) {
+		svc := newTestDomainService(newFakeUserRepository())
+		_, err := svc.DisableUser(context.Background(), "missing")
+		assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound))
+	})
+}
+
+// TestUser_StatusTransitions 启用和停用对任意状态都合法，不合法的变更只出现在锁定上
+func TestUser_StatusTransitions(t *testing.T) {
+	tests := []struct {
+		from    valueobject.UserStatus
+		to      func(*entity.User) error
+		toName  string
+		wantErr bool
+	}{
+		{from: valueobject.UserStatusActive, to: (*entity.User).Lock, toName: "lock"},
+		{from: valueobject.UserStatusLocked, to: (*entity.User).Lock, toName: "lock"},
+		{from: valueobject.UserStatusPending, to: (*entity.User).Lock, toName: "lock", wantErr: true},
+		{from: valueobject.UserStatusDisabled, to: (*entity.User).Lock, toName: "lock", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from.String()+" "+tt.toName, func(t *testing.T) {
+			user := newTestUser("u1", "", "+8613800138000")
+			user.SetStatus(tt.from)
+
+			err := tt.to(user)
+			if tt.wantErr {
+				assertDomainError(t, userErrors.ErrInvalidStatusTransition, err)
+				assert.Equal(t, tt.from, user.Status())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, valueobject.UserStatusLocked, user.Status())
+		})
+	}
+}
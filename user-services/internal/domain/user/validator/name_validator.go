@@ -44,4 +44,4 @@ func (v *nameValidator) Validate(name string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
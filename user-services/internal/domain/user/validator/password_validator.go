@@ -1,23 +1,73 @@
 package validator
 
 import (
-	"regexp"
 	"strings"
+	"unicode"
 
+	"golang.org/x/crypto/bcrypt"
+
+	"common/config"
 	userErrors "user-services/internal/domain/user/errors"
 )
 
+// bcryptMaxLength bcrypt 只使用密码的前72个字节
+const bcryptMaxLength = 72
+
+// PasswordPolicy 密码策略
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireLetter bool
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	HistorySize   int // 新密码不能与最近N次使用的密码相同(含当前密码)，0表示不限制
+}
+
+// NewPasswordPolicy 从配置创建密码策略，未配置长度时使用6~20位
+func NewPasswordPolicy(cfg *config.Config) PasswordPolicy {
+	pc := cfg.Password
+	policy := PasswordPolicy{
+		MinLength:     pc.MinLength,
+		MaxLength:     pc.MaxLength,
+		RequireLetter: pc.RequireLetter,
+		RequireUpper:  pc.RequireUpper,
+		RequireLower:  pc.RequireLower,
+		RequireDigit:  pc.RequireDigit,
+		RequireSymbol: pc.RequireSymbol,
+		HistorySize:   pc.HistorySize,
+	}
+	if policy.MinLength <= 0 {
+		policy.MinLength = 6
+	}
+	if policy.MaxLength <= 0 {
+		policy.MaxLength = 20
+	}
+	if policy.MaxLength > bcryptMaxLength {
+		policy.MaxLength = bcryptMaxLength
+	}
+	if policy.HistorySize < 0 {
+		policy.HistorySize = 0
+	}
+	return policy
+}
+
 // PasswordValidator 密码验证器接口
 type PasswordValidator interface {
 	Validate(password string) error
+	// CheckReuse 检查密码是否与给定的历史密码哈希之一相同
+	CheckReuse(password string, recentHashes []string) error
 }
 
 // passwordValidator 密码验证器实现
-type passwordValidator struct{}
+type passwordValidator struct {
+	policy PasswordPolicy
+}
 
 // NewPasswordValidator 创建密码验证器
-func NewPasswordValidator() PasswordValidator {
-	return &passwordValidator{}
+func NewPasswordValidator(policy PasswordPolicy) PasswordValidator {
+	return &passwordValidator{policy: policy}
 }
 
 // Validate 验证密码
@@ -31,20 +81,63 @@ func (v *passwordValidator) Validate(password string) error {
 	}
 
 	// 检查长度
-	if len(password) < 6 {
-		return userErrors.ErrPasswordTooShort
+	if len(password) < v.policy.MinLength {
+		return userErrors.NewPasswordTooShortError(v.policy.MinLength)
 	}
-	if len(password) > 20 {
-		return userErrors.ErrPasswordTooLong
+	if len(password) > v.policy.MaxLength {
+		return userErrors.NewPasswordTooLongError(v.policy.MaxLength)
 	}
 
-	// 检查强度（示例：必须包含字母和数字）
-	hasLetter := regexp.MustCompile(`[a-zA-Z]`).MatchString(password)
-	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
+	// 检查强度
+	var hasLetter, hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasLetter, hasUpper = true, true
+		case unicode.IsLower(r):
+			hasLetter, hasLower = true, true
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
 
-	if !hasLetter || !hasNumber {
-		return userErrors.ErrPasswordTooWeak
+	var missing bool
+	var required []string
+	checks := []struct {
+		enabled bool
+		ok      bool
+		name    string
+	}{
+		{v.policy.RequireLetter, hasLetter, "字母"},
+		{v.policy.RequireUpper, hasUpper, "大写字母"},
+		{v.policy.RequireLower, hasLower, "小写字母"},
+		{v.policy.RequireDigit, hasDigit, "数字"},
+		{v.policy.RequireSymbol, hasSymbol, "特殊字符"},
+	}
+	for _, check := range checks {
+		if !check.enabled {
+			continue
+		}
+		required = append(required, check.name)
+		missing = missing || !check.ok
+	}
+	if missing {
+		return userErrors.NewPasswordTooWeakError(required)
 	}
 
 	return nil
 }
+
+// CheckReuse 检查密码是否与最近使用过的密码相同
+func (v *passwordValidator) CheckReuse(password string, recentHashes []string) error {
+	for _, hash := range recentHashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return userErrors.NewPasswordReusedError(v.policy.HistorySize)
+		}
+	}
+	return nil
+}
@@ -2,33 +2,84 @@ package validator
 
 import (
 	"context"
-	"regexp"
+	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/nyaruka/phonenumbers"
+
+	"common/config"
+	"common/response"
 	userErrors "user-services/internal/domain/user/errors"
 	"user-services/internal/domain/user/repository"
 )
 
+// defaultPhoneRegion 未配置 user.phone.default_region 时不带国际区号的号码按中国大陆解析
+const defaultPhoneRegion = "CN"
+
+// PhonePolicy 手机号策略
+type PhonePolicy struct {
+	DefaultRegion  string   // 不带国际区号的号码按此地区解析
+	AllowedRegions []string // 允许使用的手机号地区，为空时不限制
+}
+
+// NewPhonePolicy 从配置创建手机号策略，地区代码不受支持时返回错误
+func NewPhonePolicy(cfg *config.Config) (PhonePolicy, error) {
+	pc := cfg.User.Phone
+	policy := PhonePolicy{
+		DefaultRegion:  strings.ToUpper(strings.TrimSpace(pc.DefaultRegion)),
+		AllowedRegions: make([]string, 0, len(pc.AllowedRegions)),
+	}
+	if policy.DefaultRegion == "" {
+		policy.DefaultRegion = defaultPhoneRegion
+	}
+
+	supported := phonenumbers.GetSupportedRegions()
+	if _, ok := supported[policy.DefaultRegion]; !ok {
+		return PhonePolicy{}, fmt.Errorf("user.phone.default_region: unsupported region %q", policy.DefaultRegion)
+	}
+	for _, region := range pc.AllowedRegions {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if _, ok := supported[region]; !ok {
+			return PhonePolicy{}, fmt.Errorf("user.phone.allowed_regions: unsupported region %q", region)
+		}
+		policy.AllowedRegions = append(policy.AllowedRegions, region)
+	}
+	return policy, nil
+}
+
 // PhoneValidator 手机号验证器接口
 type PhoneValidator interface {
 	Validate(phoneNumber string) error
+	// Normalize 校验手机号并转换为E.164格式(如 +8613800138000)，保存和查询手机号前都应先转换
+	Normalize(phoneNumber string) (string, error)
 	CheckUniqueness(ctx context.Context, phoneNumber string) error
+	CheckUniquenessForUser(ctx context.Context, phoneNumber, userID string) error
 }
 
 // phoneValidator 手机号验证器实现
 type phoneValidator struct {
 	userRepo repository.UserRepository
+	policy   PhonePolicy
 }
 
 // NewPhoneValidator 创建手机号验证器
-func NewPhoneValidator(userRepo repository.UserRepository) PhoneValidator {
+func NewPhoneValidator(userRepo repository.UserRepository, policy PhonePolicy) PhoneValidator {
 	return &phoneValidator{
 		userRepo: userRepo,
+		policy:   policy,
 	}
 }
 
 // Validate 验证手机号格式
 func (v *phoneValidator) Validate(phoneNumber string) error {
+	_, err := v.Normalize(phoneNumber)
+	return err
+}
+
+// Normalize 按国家或地区的号码规则解析手机号
+// 以+开头的号码按其国际区号解析，其余按默认地区解析，因此 13800138000 和 +86 138 0013 8000 得到相同的结果
+func (v *phoneValidator) Normalize(phoneNumber string) (string, error) {
 	originalPhone := phoneNumber // 保留原始输入用于上下文
 
 	// 去除空格
@@ -36,28 +87,36 @@ func (v *phoneValidator) Validate(phoneNumber string) error {
 
 	// 检查是否为空
 	if phoneNumber == "" {
-		return userErrors.ErrPhoneRequired.WithContext("input", originalPhone)
+		return "", userErrors.ErrPhoneRequired.WithContext("input", originalPhone)
 	}
 
-	// 检查长度
-	if len(phoneNumber) > 11 {
-		return userErrors.ErrInvalidPhone.
+	// 检查格式
+	number, err := phonenumbers.Parse(phoneNumber, v.policy.DefaultRegion)
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return "", userErrors.ErrInvalidPhone.
 			WithContext("input", originalPhone).
-			WithContext("length", len(originalPhone)).
-			WithContext("max_length", 11).
-			WithContext("rule", "length")
+			WithContext("rule", "format").
+			WithContext("default_region", v.policy.DefaultRegion)
 	}
 
-	// 检查格式（中国大陆手机号）
-	phoneRegex := regexp.MustCompile(`^1[3-9]\d{9}$`)
-	if !phoneRegex.MatchString(phoneNumber) {
-		return userErrors.ErrInvalidPhone.
+	// 检查号码类型，部分地区(如美国)无法区分固定电话和手机
+	switch phonenumbers.GetNumberType(number) {
+	case phonenumbers.MOBILE, phonenumbers.FIXED_LINE_OR_MOBILE:
+	default:
+		return "", userErrors.ErrInvalidPhone.
 			WithContext("input", originalPhone).
-			WithContext("rule", "format").
-			WithContext("expected", "1[3-9]xxxxxxxxx")
+			WithContext("rule", "mobile")
 	}
 
-	return nil
+	// 检查地区
+	region := phonenumbers.GetRegionCodeForNumber(number)
+	if len(v.policy.AllowedRegions) > 0 && !slices.Contains(v.policy.AllowedRegions, region) {
+		return "", userErrors.ErrPhoneRegion.
+			WithContext("input", originalPhone).
+			WithContext("region", region)
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
 }
 
 // CheckUniqueness 检查手机号唯一性
@@ -74,3 +133,21 @@ func (v *phoneValidator) CheckUniqueness(ctx context.Context, phoneNumber string
 
 	return nil
 }
+
+// CheckUniquenessForUser 检查手机号是否已被其他用户使用，userID本人已绑定的手机号视为可用
+func (v *phoneValidator) CheckUniquenessForUser(ctx context.Context, phoneNumber, userID string) error {
+	user, err := v.userRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if response.IsErrorType(err, response.ErrorTypeNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.ID() != userID {
+		return userErrors.ErrPhoneNotUnique.
+			WithContext("input", phoneNumber)
+	}
+
+	return nil
+}
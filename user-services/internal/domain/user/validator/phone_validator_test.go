@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/config"
+	"common/response"
+	userErrors "user-services/internal/domain/user/errors"
+)
+
+func newTestPhonePolicy(t *testing.T, phone config.UserPhoneConfig) PhonePolicy {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.User.Phone = phone
+	policy, err := NewPhonePolicy(cfg)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestPhoneValidator_Normalize(t *testing.T) {
+	cnOnly := config.UserPhoneConfig{DefaultRegion: "CN"}
+	allowList := config.UserPhoneConfig{AllowedRegions: []string{"cn", " US ", "GB"}}
+
+	tests := []struct {
+		name    string
+		phone   config.UserPhoneConfig
+		input   string
+		want    string
+		wantErr *response.DomainError
+	}{
+		{name: "national", phone: cnOnly, input: "13800138000", want: "+8613800138000"},
+		{name: "international with spaces", phone: cnOnly, input: "+86 138 0013 8000", want: "+8613800138000"},
+		{name: "country code without plus", phone: cnOnly, input: "8613800138000", want: "+8613800138000"},
+		{name: "dashes and padding", phone: cnOnly, input: " 138-0013-8000 ", want: "+8613800138000"},
+		{name: "default region when unset", input: "13800138000", want: "+8613800138000"},
+		{name: "non-CN number under CN default", phone: cnOnly, input: "+14155552671", want: "+14155552671"},
+		{name: "allowed region", phone: allowList, input: "+44 7400 123456", want: "+447400123456"},
+		{name: "region not allowed", phone: allowList, input: "+81 90-1234-5678", wantErr: userErrors.ErrPhoneRegion},
+		{name: "foreign fixed line", phone: cnOnly, input: "+442071838750", wantErr: userErrors.ErrInvalidPhone},
+		{name: "national fixed line", phone: cnOnly, input: "010 6552 9988", wantErr: userErrors.ErrInvalidPhone},
+		{name: "too short", phone: cnOnly, input: "1380013800", wantErr: userErrors.ErrInvalidPhone},
+		{name: "not a number", phone: cnOnly, input: "abc", wantErr: userErrors.ErrInvalidPhone},
+		{name: "blank", phone: cnOnly, input: "  ", wantErr: userErrors.ErrPhoneRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewPhoneValidator(nil, newTestPhonePolicy(t, tt.phone))
+
+			got, err := v.Normalize(tt.input)
+			if tt.wantErr != nil {
+				// 领域错误附加上下文后是新实例，按消息比较
+				var domainErr *response.DomainError
+				require.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tt.wantErr.Message, domainErr.Message)
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewPhonePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		phone   config.UserPhoneConfig
+		want    PhonePolicy
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: PhonePolicy{DefaultRegion: "CN", AllowedRegions: []string{}},
+		},
+		{
+			name:  "regions normalized",
+			phone: config.UserPhoneConfig{DefaultRegion: " us ", AllowedRegions: []string{"us", "ca "}},
+			want:  PhonePolicy{DefaultRegion: "US", AllowedRegions: []string{"US", "CA"}},
+		},
+		{
+			name:    "unsupported default region",
+			phone:   config.UserPhoneConfig{DefaultRegion: "XX"},
+			wantErr: `user.phone.default_region: unsupported region "XX"`,
+		},
+		{
+			name:    "unsupported allowed region",
+			phone:   config.UserPhoneConfig{AllowedRegions: []string{"CN", "china"}},
+			wantErr: `user.phone.allowed_regions: unsupported region "CHINA"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.User.Phone = tt.phone
+
+			got, err := NewPhonePolicy(cfg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
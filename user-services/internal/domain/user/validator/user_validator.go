@@ -3,15 +3,19 @@ package validator
 import (
 	"context"
 
+	"user-services/internal/domain/user/entity"
+	userErrors "user-services/internal/domain/user/errors"
 	"user-services/internal/domain/user/repository"
+	"user-services/internal/domain/user/valueobject"
 )
 
 // UserValidator 用户验证器接口
 type UserValidator interface {
 	ValidateForCreation(ctx context.Context, phoneNumber, password, name string, gender int) error
 	ValidateForUpdate(ctx context.Context, userID string, updates map[string]interface{}) error
-	ValidatePhoneNumber(phoneNumber string) error
+	NormalizePhoneNumber(phoneNumber string) (string, error)
 	ValidatePassword(password string) error
+	ValidatePasswordChange(password string, recentHashes []string) error
 	ValidateName(name string) error
 }
 
@@ -23,15 +27,15 @@ type userValidator struct {
 }
 
 // NewUserValidator 创建用户验证器
-func NewUserValidator(userRepo repository.UserRepository) UserValidator {
+func NewUserValidator(userRepo repository.UserRepository, passwordPolicy PasswordPolicy, phonePolicy PhonePolicy) UserValidator {
 	return &userValidator{
-		phoneValidator:    NewPhoneValidator(userRepo),
-		passwordValidator: NewPasswordValidator(),
+		phoneValidator:    NewPhoneValidator(userRepo, phonePolicy),
+		passwordValidator: NewPasswordValidator(passwordPolicy),
 		nameValidator:     NewNameValidator(),
 	}
 }
 
-// ValidateForCreation 验证用户创建
+// ValidateForCreation 验证用户创建，phoneNumber 应已通过 NormalizePhoneNumber 转换，否则唯一性校验可能漏掉同一号码的其他写法
 func (v *userValidator) ValidateForCreation(ctx context.Context, phoneNumber, password, name string, gender int) error {
 	// 验证手机号格式
 	if err := v.phoneValidator.Validate(phoneNumber); err != nil {
@@ -44,27 +48,32 @@ func (v *userValidator) ValidateForCreation(ctx context.Context, phoneNumber, pa
 	}
 
 	// 验证密码
-	// if err := v.passwordValidator.Validate(password); err != nil {
-	// 	return err
-	// }
+	if err := v.passwordValidator.Validate(password); err != nil {
+		return err
+	}
 
 	// 验证姓名
 	if err := v.nameValidator.Validate(name); err != nil {
 		return err
 	}
 
+	// 验证性别
+	if !valueobject.Gender(gender).IsValid() {
+		return userErrors.ErrInvalidGender.WithContext("input", gender)
+	}
+
 	return nil
 }
 
-// ValidateForUpdate 验证用户更新
+// ValidateForUpdate 验证用户更新，updates中值为nil表示清空该字段
+// 手机号唯一性校验排除用户本人，保持原手机号不变的更新不会被判定为重复；手机号应已转换为E.164格式
 func (v *userValidator) ValidateForUpdate(ctx context.Context, userID string, updates map[string]interface{}) error {
 	// 根据更新字段进行相应验证
-	if phoneNumber, ok := updates["phone_number"].(string); ok {
+	if phoneNumber, ok := updates[entity.FieldPhoneNumber].(string); ok {
 		if err := v.phoneValidator.Validate(phoneNumber); err != nil {
 			return err
 		}
-		// 注意：更新时需要排除当前用户的手机号
-		if err := v.phoneValidator.CheckUniqueness(ctx, phoneNumber); err != nil {
+		if err := v.phoneValidator.CheckUniquenessForUser(ctx, phoneNumber, userID); err != nil {
 			return err
 		}
 	}
@@ -75,18 +84,27 @@ func (v *userValidator) ValidateForUpdate(ctx context.Context, userID string, up
 		}
 	}
 
-	if name, ok := updates["name"].(string); ok {
+	// 姓名和性别不能清空
+	if name, ok := updates[entity.FieldName]; ok {
+		name, _ := name.(string)
 		if err := v.nameValidator.Validate(name); err != nil {
 			return err
 		}
 	}
 
+	if gender, ok := updates[entity.FieldGender]; ok {
+		gender, _ := gender.(int)
+		if !valueobject.Gender(gender).IsValid() {
+			return userErrors.ErrInvalidGender.WithContext("input", gender)
+		}
+	}
+
 	return nil
 }
 
-// ValidatePhoneNumber 验证手机号
-func (v *userValidator) ValidatePhoneNumber(phoneNumber string) error {
-	return v.phoneValidator.Validate(phoneNumber)
+// NormalizePhoneNumber 验证手机号并转换为E.164格式
+func (v *userValidator) NormalizePhoneNumber(phoneNumber string) (string, error) {
+	return v.phoneValidator.Normalize(phoneNumber)
 }
 
 // ValidatePassword 验证密码
@@ -94,6 +112,14 @@ func (v *userValidator) ValidatePassword(password string) error {
 	return v.passwordValidator.Validate(password)
 }
 
+// ValidatePasswordChange 验证新密码，除密码策略外还不能与最近使用过的密码相同
+func (v *userValidator) ValidatePasswordChange(password string, recentHashes []string) error {
+	if err := v.passwordValidator.Validate(password); err != nil {
+		return err
+	}
+	return v.passwordValidator.CheckReuse(password, recentHashes)
+}
+
 // ValidateName 验证姓名
 func (v *userValidator) ValidateName(name string) error {
 	return v.nameValidator.Validate(name)
@@ -0,0 +1,38 @@
+package valueobject
+
+// UserStatus 账号状态
+type UserStatus string
+
+const (
+	UserStatusPending  UserStatus = "pending"  // 待激活
+	UserStatusActive   UserStatus = "active"   // 正常
+	UserStatusDisabled UserStatus = "disabled" // 已停用，由管理员停用和启用
+	UserStatusLocked   UserStatus = "locked"   // 已锁定，因安全原因冻结，解锁后恢复正常
+)
+
+// userStatusTransitions 允许的状态变更，已停用的账号只能重新启用
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusPending:  {UserStatusActive, UserStatusDisabled},
+	UserStatusActive:   {UserStatusDisabled, UserStatusLocked},
+	UserStatusLocked:   {UserStatusActive, UserStatusDisabled},
+	UserStatusDisabled: {UserStatusActive},
+}
+
+func (s UserStatus) IsValid() bool {
+	_, ok := userStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo 是否允许从当前状态变更为目标状态
+func (s UserStatus) CanTransitionTo(target UserStatus) bool {
+	for _, allowed := range userStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (s UserStatus) String() string {
+	return string(s)
+}
@@ -3,18 +3,21 @@ package messaging
 import (
 	"context"
 	"encoding/json"
-	"user-services/internal/domain/user/entity"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
 
 	commonRedis "common/databases/redis"
 	"common/pkg/idgen"
+	"user-services/internal/domain/user/entity"
 )
 
 // EventPublisher 事件发布器接口
 type EventPublisher interface {
 	PublishUserCreated(ctx context.Context, user *entity.User) error
+	PublishUserUpdated(ctx context.Context, user *entity.User, changes map[string]interface{}) error
+	PublishLoginLocked(ctx context.Context, event LoginLockedEvent) error
 }
 
 // RedisEventPublisher Redis事件发布器实现
@@ -55,6 +58,58 @@ func (p *RedisEventPublisher) PublishUserCreated(ctx context.Context, user *enti
 	return p.publishEvent(ctx, "events:user:created", event)
 }
 
+// UserUpdatedEvent 用户资料更新事件
+type UserUpdatedEvent struct {
+	EventID       string                 `json:"event_id"`
+	EventType     string                 `json:"event_type"`
+	UserID        string                 `json:"user_id"`
+	ChangedFields []string               `json:"changed_fields"`
+	Changes       map[string]interface{} `json:"changes"` // 字段新值，null表示已清空
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// PublishUserUpdated 发布用户资料更新事件
+func (p *RedisEventPublisher) PublishUserUpdated(ctx context.Context, user *entity.User, changes map[string]interface{}) error {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	event := UserUpdatedEvent{
+		EventID:       p.idGen.NewID().String(),
+		EventType:     "user.updated",
+		UserID:        user.ID(),
+		ChangedFields: fields,
+		Changes:       changes,
+		Timestamp:     time.Now(),
+	}
+
+	return p.publishEvent(ctx, "events:user:updated", event)
+}
+
+// LoginLockedEvent 登录锁定事件
+type LoginLockedEvent struct {
+	EventID     string    `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	Scope       string    `json:"scope"`   // 锁定维度: phone / ip
+	Subject     string    `json:"subject"` // 被锁定的手机号或IP
+	PhoneNumber string    `json:"phone_number"`
+	ClientIP    string    `json:"client_ip"`
+	Failures    int64     `json:"failures"` // 锁定时的连续失败次数
+	LockedUntil time.Time `json:"locked_until"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// PublishLoginLocked 发布登录锁定事件
+func (p *RedisEventPublisher) PublishLoginLocked(ctx context.Context, event LoginLockedEvent) error {
+	event.EventID = p.idGen.NewID().String()
+	event.EventType = "auth.login_locked"
+	event.Timestamp = time.Now()
+
+	return p.publishEvent(ctx, "events:auth:login_locked", event)
+}
+
 // publishEvent 发布事件到Redis
 func (p *RedisEventPublisher) publishEvent(ctx context.Context, channel string, event interface{}) error {
 	eventData, err := json.Marshal(event)
@@ -64,6 +119,10 @@ func (p *RedisEventPublisher) publishEvent(ctx context.Context, channel string,
 	}
 
 	// 使用Redis客户端发布事件
+	if err := p.redisClient.Publish(ctx, channel, eventData).Err(); err != nil {
+		p.logger.Error("Failed to publish event", zap.String("channel", channel), zap.Error(err))
+		return err
+	}
 
 	p.logger.Info("Event published successfully", zap.String("channel", channel), zap.String("event", string(eventData)))
 	return nil
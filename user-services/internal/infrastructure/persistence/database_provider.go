@@ -5,6 +5,9 @@ import (
 
 	"common/databases/rdbms"
 	"user-services/internal/infrastructure/persistence/ent/gen"
+
+	// schema带有拦截器，默认值、校验器和拦截器在runtime包中注册
+	_ "user-services/internal/infrastructure/persistence/ent/gen/runtime"
 )
 
 // DatabaseProvider 数据库提供者，统一管理数据库访问
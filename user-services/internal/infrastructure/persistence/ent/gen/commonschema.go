@@ -4,8 +4,8 @@ package gen
 
 import (
 	"fmt"
-	"user-services/internal/infrastructure/persistence/ent/gen/commonschema"
 	"strings"
+	"user-services/internal/infrastructure/persistence/ent/gen/commonschema"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"user-services/internal/infrastructure/persistence/ent/gen/apikey"
+	"user-services/internal/infrastructure/persistence/ent/gen/auditlog"
 	"user-services/internal/infrastructure/persistence/ent/gen/commonschema"
 	"user-services/internal/infrastructure/persistence/ent/gen/user"
-	"sync"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -74,6 +76,8 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
+			apikey.Table:       apikey.ValidColumn,
+			auditlog.Table:     auditlog.ValidColumn,
 			commonschema.Table: commonschema.ValidColumn,
 			user.Table:         user.ValidColumn,
 		})
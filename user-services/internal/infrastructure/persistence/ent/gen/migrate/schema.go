@@ -3,11 +3,82 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// APIKeysColumns holds the columns for the "api_keys" table.
+	APIKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Comment: "API Key ID"},
+		{Name: "name", Type: field.TypeString, Size: 100, Comment: "名称，用于说明用途"},
+		{Name: "prefix", Type: field.TypeString, Size: 16, Comment: "密钥前缀，用于查找密钥，可公开展示"},
+		{Name: "key_hash", Type: field.TypeString, Size: 64, Comment: "密钥SHA-256哈希"},
+		{Name: "owner_id", Type: field.TypeString, Size: 64, Comment: "所有者，作为调用方身份参与权限校验"},
+		{Name: "scopes", Type: field.TypeJSON, Nullable: true, Comment: "授权范围"},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true, Comment: "过期时间，为空表示永不过期"},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true, Comment: "最近使用时间"},
+		{Name: "revoked_at", Type: field.TypeTime, Nullable: true, Comment: "吊销时间"},
+		{Name: "created_at", Type: field.TypeTime, Comment: "创建时间"},
+	}
+	// APIKeysTable holds the schema information for the "api_keys" table.
+	APIKeysTable = &schema.Table{
+		Name:       "api_keys",
+		Columns:    APIKeysColumns,
+		PrimaryKey: []*schema.Column{APIKeysColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "apikey_prefix",
+				Unique:  true,
+				Columns: []*schema.Column{APIKeysColumns[2]},
+			},
+			{
+				Name:    "apikey_owner_id",
+				Unique:  false,
+				Columns: []*schema.Column{APIKeysColumns[4]},
+			},
+		},
+	}
+	// AuditLogsColumns holds the columns for the "audit_logs" table.
+	AuditLogsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Comment: "审计日志ID"},
+		{Name: "actor_id", Type: field.TypeString, Size: 64, Comment: "操作者，用户ID或API Key所有者，系统操作为system"},
+		{Name: "domain", Type: field.TypeString, Size: 64, Comment: "所属租户(Casbin域)，* 表示平台级", Default: ""},
+		{Name: "action", Type: field.TypeString, Size: 64, Comment: "操作类型，如 policy.add、role.assign"},
+		{Name: "resource", Type: field.TypeString, Size: 255, Comment: "操作对象"},
+		{Name: "detail", Type: field.TypeJSON, Nullable: true, Comment: "操作详情"},
+		{Name: "client_ip", Type: field.TypeString, Nullable: true, Size: 64, Comment: "客户端IP"},
+		{Name: "created_at", Type: field.TypeTime, Comment: "操作时间"},
+	}
+	// AuditLogsTable holds the schema information for the "audit_logs" table.
+	AuditLogsTable = &schema.Table{
+		Name:       "audit_logs",
+		Columns:    AuditLogsColumns,
+		PrimaryKey: []*schema.Column{AuditLogsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditlog_domain",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[2]},
+			},
+			{
+				Name:    "auditlog_actor_id",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[1]},
+			},
+			{
+				Name:    "auditlog_action",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[3]},
+			},
+			{
+				Name:    "auditlog_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[7]},
+			},
+		},
+	}
 	// CommonSchemasColumns holds the columns for the "common_schemas" table.
 	CommonSchemasColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUint64, Increment: true},
@@ -24,10 +95,17 @@ var (
 		{Name: "name", Type: field.TypeString, Size: 50, Comment: "用户名"},
 		{Name: "open_id", Type: field.TypeString, Comment: "open_id"},
 		{Name: "password", Type: field.TypeString, Size: 100, Comment: "密码"},
-		{Name: "phone_number", Type: field.TypeString, Comment: "手机号", Default: ""},
+		{Name: "password_history", Type: field.TypeJSON, Nullable: true, Comment: "历史密码哈希，最近使用的在前，用于禁止重复使用近期密码"},
+		{Name: "phone_number", Type: field.TypeString, Nullable: true, Comment: "手机号(E.164格式，如+8613800138000)，微信注册的用户在绑定前为空"},
+		{Name: "totp_secret", Type: field.TypeString, Nullable: true, Size: 255, Comment: "TOTP密钥(AES-GCM加密)，未绑定身份验证器时为空"},
+		{Name: "totp_enabled", Type: field.TypeBool, Comment: "是否已启用TOTP两步验证", Default: false},
+		{Name: "totp_recovery_codes", Type: field.TypeJSON, Nullable: true, Comment: "两步验证恢复码哈希，每个恢复码只能使用一次"},
 		{Name: "gender", Type: field.TypeInt, Comment: "性别"},
+		{Name: "status", Type: field.TypeString, Size: 16, Comment: "账号状态：pending待激活、active正常、disabled已停用、locked已锁定", Default: "active"},
 		{Name: "created_at", Type: field.TypeTime, Comment: "创建时间"},
 		{Name: "updated_at", Type: field.TypeTime, Comment: "更新时间"},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true, Comment: "删除时间，非空表示已软删除"},
+		{Name: "alive", Type: field.TypeBool, Nullable: true, Comment: "未删除时为1，软删除后为NULL，用于唯一索引", Default: true},
 	}
 	// UsersTable holds the schema information for the "users" table.
 	UsersTable = &schema.Table{
@@ -36,28 +114,38 @@ var (
 		PrimaryKey: []*schema.Column{UsersColumns[0]},
 		Indexes: []*schema.Index{
 			{
-				Name:    "user_open_id",
+				Name:    "user_open_id_alive",
 				Unique:  true,
-				Columns: []*schema.Column{UsersColumns[2]},
+				Columns: []*schema.Column{UsersColumns[2], UsersColumns[14]},
 			},
 			{
-				Name:    "user_phone_number",
+				Name:    "user_phone_number_alive",
 				Unique:  true,
-				Columns: []*schema.Column{UsersColumns[4]},
+				Columns: []*schema.Column{UsersColumns[5], UsersColumns[14]},
 			},
 			{
 				Name:    "user_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{UsersColumns[6]},
+				Columns: []*schema.Column{UsersColumns[11]},
+			},
+			{
+				Name:    "user_deleted_at",
+				Unique:  false,
+				Columns: []*schema.Column{UsersColumns[13]},
 			},
 		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		APIKeysTable,
+		AuditLogsTable,
 		CommonSchemasTable,
 		UsersTable,
 	}
 )
 
 func init() {
+	APIKeysTable.Annotation = &entsql.Annotation{
+		Table: "api_keys",
+	}
 }
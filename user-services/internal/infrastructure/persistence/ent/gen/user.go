@@ -3,10 +3,11 @@
 package gen
 
 import (
+	"encoding/json"
 	"fmt"
-	"user-services/internal/infrastructure/persistence/ent/gen/user"
 	"strings"
 	"time"
+	"user-services/internal/infrastructure/persistence/ent/gen/user"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -25,14 +26,28 @@ type User struct {
 	OpenID string `json:"open_id,omitempty"`
 	// 密码
 	Password string `json:"-"`
-	// 手机号
-	PhoneNumber string `json:"phone_number,omitempty"`
+	// 历史密码哈希，最近使用的在前，用于禁止重复使用近期密码
+	PasswordHistory []string `json:"-"`
+	// 手机号(E.164格式，如+8613800138000)，微信注册的用户在绑定前为空
+	PhoneNumber *string `json:"phone_number,omitempty"`
+	// TOTP密钥(AES-GCM加密)，未绑定身份验证器时为空
+	TotpSecret *string `json:"-"`
+	// 是否已启用TOTP两步验证
+	TotpEnabled bool `json:"totp_enabled,omitempty"`
+	// 两步验证恢复码哈希，每个恢复码只能使用一次
+	TotpRecoveryCodes []string `json:"-"`
 	// 性别
 	Gender int `json:"gender,omitempty"`
+	// 账号状态：pending待激活、active正常、disabled已停用、locked已锁定
+	Status string `json:"status,omitempty"`
 	// 创建时间
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// 更新时间
-	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// 删除时间，非空表示已软删除
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// 未删除时为1，软删除后为NULL，用于唯一索引
+	Alive        *bool `json:"alive,omitempty"`
 	selectValues sql.SelectValues
 }
 
@@ -41,11 +56,15 @@ func (*User) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case user.FieldPasswordHistory, user.FieldTotpRecoveryCodes:
+			values[i] = new([]byte)
+		case user.FieldTotpEnabled, user.FieldAlive:
+			values[i] = new(sql.NullBool)
 		case user.FieldGender:
 			values[i] = new(sql.NullInt64)
-		case user.FieldName, user.FieldOpenID, user.FieldPassword, user.FieldPhoneNumber:
+		case user.FieldName, user.FieldOpenID, user.FieldPassword, user.FieldPhoneNumber, user.FieldTotpSecret, user.FieldStatus:
 			values[i] = new(sql.NullString)
-		case user.FieldCreatedAt, user.FieldUpdatedAt:
+		case user.FieldCreatedAt, user.FieldUpdatedAt, user.FieldDeletedAt:
 			values[i] = new(sql.NullTime)
 		case user.FieldID:
 			values[i] = new(uuid.UUID)
@@ -88,11 +107,41 @@ func (_m *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.Password = value.String
 			}
+		case user.FieldPasswordHistory:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field password_history", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.PasswordHistory); err != nil {
+					return fmt.Errorf("unmarshal field password_history: %w", err)
+				}
+			}
 		case user.FieldPhoneNumber:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field phone_number", values[i])
 			} else if value.Valid {
-				_m.PhoneNumber = value.String
+				_m.PhoneNumber = new(string)
+				*_m.PhoneNumber = value.String
+			}
+		case user.FieldTotpSecret:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field totp_secret", values[i])
+			} else if value.Valid {
+				_m.TotpSecret = new(string)
+				*_m.TotpSecret = value.String
+			}
+		case user.FieldTotpEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field totp_enabled", values[i])
+			} else if value.Valid {
+				_m.TotpEnabled = value.Bool
+			}
+		case user.FieldTotpRecoveryCodes:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field totp_recovery_codes", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.TotpRecoveryCodes); err != nil {
+					return fmt.Errorf("unmarshal field totp_recovery_codes: %w", err)
+				}
 			}
 		case user.FieldGender:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -100,6 +149,12 @@ func (_m *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.Gender = int(value.Int64)
 			}
+		case user.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				_m.Status = value.String
+			}
 		case user.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -112,6 +167,20 @@ func (_m *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.UpdatedAt = value.Time
 			}
+		case user.FieldDeletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deleted_at", values[i])
+			} else if value.Valid {
+				_m.DeletedAt = new(time.Time)
+				*_m.DeletedAt = value.Time
+			}
+		case user.FieldAlive:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field alive", values[i])
+			} else if value.Valid {
+				_m.Alive = new(bool)
+				*_m.Alive = value.Bool
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -156,17 +225,41 @@ func (_m *User) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("password=<sensitive>")
 	builder.WriteString(", ")
-	builder.WriteString("phone_number=")
-	builder.WriteString(_m.PhoneNumber)
+	builder.WriteString("password_history=<sensitive>")
+	builder.WriteString(", ")
+	if v := _m.PhoneNumber; v != nil {
+		builder.WriteString("phone_number=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	builder.WriteString("totp_secret=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("totp_enabled=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TotpEnabled))
+	builder.WriteString(", ")
+	builder.WriteString("totp_recovery_codes=<sensitive>")
 	builder.WriteString(", ")
 	builder.WriteString("gender=")
 	builder.WriteString(fmt.Sprintf("%v", _m.Gender))
 	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(_m.Status)
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
 	builder.WriteString("updated_at=")
 	builder.WriteString(_m.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := _m.DeletedAt; v != nil {
+		builder.WriteString("deleted_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.Alive; v != nil {
+		builder.WriteString("alive=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -1,3 +1,3 @@
-//go:generate go run entgo.io/ent/cmd/ent generate --target ./gen ./schema
+//go:generate go run entgo.io/ent/cmd/ent generate --feature intercept --target ./gen ./schema
 
 package ent
@@ -0,0 +1,4 @@
+-- Modify "users" table
+ALTER TABLE `users` MODIFY COLUMN `phone_number` varchar(255) NULL COMMENT "手机号，微信注册的用户在绑定前为空";
+-- Convert placeholder empty phone numbers to NULL so the unique index allows multiple unbound users
+UPDATE `users` SET `phone_number` = NULL WHERE `phone_number` = '';
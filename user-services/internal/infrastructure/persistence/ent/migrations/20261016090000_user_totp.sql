@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `totp_secret` varchar(255) NULL COMMENT "TOTP密钥(AES-GCM加密)，未绑定身份验证器时为空" AFTER `phone_number`, ADD COLUMN `totp_enabled` bool NOT NULL DEFAULT 0 COMMENT "是否已启用TOTP两步验证" AFTER `totp_secret`, ADD COLUMN `totp_recovery_codes` json NULL COMMENT "两步验证恢复码哈希，每个恢复码只能使用一次" AFTER `totp_enabled`;
@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `password_history` json NULL COMMENT "历史密码哈希，最近使用的在前，用于禁止重复使用近期密码" AFTER `password`;
@@ -0,0 +1,16 @@
+-- Create "api_keys" table
+CREATE TABLE `api_keys` (
+  `id` char(36) NOT NULL COMMENT "API Key ID",
+  `name` varchar(100) NOT NULL COMMENT "名称，用于说明用途",
+  `prefix` varchar(16) NOT NULL COMMENT "密钥前缀，用于查找密钥，可公开展示",
+  `key_hash` varchar(64) NOT NULL COMMENT "密钥SHA-256哈希",
+  `owner_id` varchar(64) NOT NULL COMMENT "所有者，作为调用方身份参与权限校验",
+  `scopes` json NULL COMMENT "授权范围",
+  `expires_at` timestamp NULL COMMENT "过期时间，为空表示永不过期",
+  `last_used_at` timestamp NULL COMMENT "最近使用时间",
+  `revoked_at` timestamp NULL COMMENT "吊销时间",
+  `created_at` timestamp NOT NULL COMMENT "创建时间",
+  PRIMARY KEY (`id`),
+  INDEX `apikey_owner_id` (`owner_id`),
+  UNIQUE INDEX `apikey_prefix` (`prefix`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
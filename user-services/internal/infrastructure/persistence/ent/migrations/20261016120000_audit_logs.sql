@@ -0,0 +1,14 @@
+-- Create "audit_logs" table
+CREATE TABLE `audit_logs` (
+  `id` char(36) NOT NULL COMMENT "审计日志ID",
+  `actor_id` varchar(64) NOT NULL COMMENT "操作者，用户ID或API Key所有者，系统操作为system",
+  `action` varchar(64) NOT NULL COMMENT "操作类型，如 policy.add、role.assign",
+  `resource` varchar(255) NOT NULL COMMENT "操作对象",
+  `detail` json NULL COMMENT "操作详情",
+  `client_ip` varchar(64) NULL COMMENT "客户端IP",
+  `created_at` timestamp NOT NULL COMMENT "操作时间",
+  PRIMARY KEY (`id`),
+  INDEX `auditlog_action` (`action`),
+  INDEX `auditlog_actor_id` (`actor_id`),
+  INDEX `auditlog_created_at` (`created_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,2 @@
+-- Modify "audit_logs" table
+ALTER TABLE `audit_logs` ADD COLUMN `domain` varchar(64) NOT NULL DEFAULT "" COMMENT "所属租户(Casbin域)，* 表示平台级" AFTER `actor_id`, ADD INDEX `auditlog_domain` (`domain`);
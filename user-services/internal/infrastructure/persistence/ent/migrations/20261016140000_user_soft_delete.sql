@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `deleted_at` timestamp NULL COMMENT "删除时间，非空表示已软删除" AFTER `updated_at`, ADD COLUMN `alive` bool NULL DEFAULT 1 COMMENT "未删除时为1，软删除后为NULL，用于唯一索引" AFTER `deleted_at`, DROP INDEX `user_open_id`, ADD UNIQUE INDEX `user_open_id_alive` (`open_id`, `alive`), DROP INDEX `user_phone_number`, ADD UNIQUE INDEX `user_phone_number_alive` (`phone_number`, `alive`), ADD INDEX `user_deleted_at` (`deleted_at`);
@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `status` varchar(16) NOT NULL DEFAULT "active" COMMENT "账号状态：pending待激活、active正常、disabled已停用、locked已锁定" AFTER `gender`;
@@ -0,0 +1,4 @@
+-- Modify "users" table
+ALTER TABLE `users` MODIFY COLUMN `phone_number` varchar(255) NULL COMMENT "手机号(E.164格式，如+8613800138000)，微信注册的用户在绑定前为空";
+-- Rewrite existing mainland China numbers to E.164 so lookups and the unique index see one canonical form per number
+UPDATE `users` SET `phone_number` = CONCAT('+86', `phone_number`) WHERE `phone_number` IS NOT NULL AND `phone_number` NOT LIKE '+%';
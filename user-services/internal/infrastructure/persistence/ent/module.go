@@ -11,7 +11,7 @@ import (
 var Module = fx.Module("ent",
 	// 提供 DatabaseProvider
 	fx.Provide(persistence.NewDatabaseProvider),
-	
+
 	// 提供 gen.Client，基于 DatabaseProvider
 	fx.Provide(func(provider *persistence.DatabaseProvider) (*gen.Client, error) {
 		return provider.CreateEntClient()
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"common/response"
+	"user-services/internal/domain/apikey/entity"
+	domainapikey "user-services/internal/domain/apikey/errors"
+	"user-services/internal/domain/apikey/repository"
+	"user-services/internal/infrastructure/persistence/ent/gen"
+	entapikey "user-services/internal/infrastructure/persistence/ent/gen/apikey"
+)
+
+// APIKeyRepositoryImpl Ent API Key仓储实现
+type APIKeyRepositoryImpl struct {
+	client *gen.Client
+}
+
+// NewAPIKeyRepository 创建API Key仓储
+func NewAPIKeyRepository(client *gen.Client) repository.APIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		client: client,
+	}
+}
+
+// Create 保存API Key
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *entity.APIKey) error {
+	created, err := r.client.APIKey.Create().
+		SetName(key.Name()).
+		SetPrefix(key.Prefix()).
+		SetKeyHash(key.KeyHash()).
+		SetOwnerID(key.OwnerID()).
+		SetScopes(key.Scopes()).
+		SetNillableExpiresAt(key.ExpiresAt()).
+		Save(ctx)
+	if err != nil {
+		if gen.IsConstraintError(err) {
+			return response.NewAlreadyExistsError(domainapikey.MsgAPIKeyPrefixConflict, err)
+		}
+		return response.NewInternalServerError(domainapikey.MsgCreateAPIKeyFailed, err)
+	}
+
+	key.SetID(created.ID.String())
+	key.SetCreatedAt(created.CreatedAt)
+	return nil
+}
+
+// GetByID 根据ID获取API Key
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, response.NewInvalidDataError(domainapikey.MsgInvalidAPIKeyID, err)
+	}
+
+	entKey, err := r.client.APIKey.Get(ctx, keyID)
+	if err != nil {
+		if gen.IsNotFound(err) {
+			return nil, response.NewNotFoundError(domainapikey.MsgAPIKeyNotFound, err)
+		}
+		return nil, response.NewInternalServerError(domainapikey.MsgQueryAPIKeyFailed, err)
+	}
+	return r.entAPIKeyToEntity(entKey), nil
+}
+
+// FindByPrefix 根据密钥前缀获取API Key
+func (r *APIKeyRepositoryImpl) FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	entKey, err := r.client.APIKey.Query().
+		Where(entapikey.Prefix(prefix)).
+		Only(ctx)
+	if err != nil {
+		if gen.IsNotFound(err) {
+			return nil, response.NewNotFoundError(domainapikey.MsgAPIKeyNotFound, err)
+		}
+		return nil, response.NewInternalServerError(domainapikey.MsgQueryAPIKeyFailed, err)
+	}
+	return r.entAPIKeyToEntity(entKey), nil
+}
+
+// List 按创建时间倒序列出API Key
+func (r *APIKeyRepositoryImpl) List(ctx context.Context, ownerID string) ([]*entity.APIKey, error) {
+	query := r.client.APIKey.Query()
+	if ownerID != "" {
+		query.Where(entapikey.OwnerID(ownerID))
+	}
+
+	entKeys, err := query.Order(gen.Desc(entapikey.FieldCreatedAt)).All(ctx)
+	if err != nil {
+		return nil, response.NewInternalServerError(domainapikey.MsgQueryAPIKeyFailed, err)
+	}
+
+	keys := make([]*entity.APIKey, 0, len(entKeys))
+	for _, entKey := range entKeys {
+		keys = append(keys, r.entAPIKeyToEntity(entKey))
+	}
+	return keys, nil
+}
+
+// UpdateRevokedAt 保存吊销时间
+func (r *APIKeyRepositoryImpl) UpdateRevokedAt(ctx context.Context, key *entity.APIKey) error {
+	keyID, err := uuid.Parse(key.ID())
+	if err != nil {
+		return response.NewInvalidDataError(domainapikey.MsgInvalidAPIKeyID, err)
+	}
+
+	update := r.client.APIKey.UpdateOneID(keyID)
+	if key.RevokedAt() != nil {
+		update.SetRevokedAt(*key.RevokedAt())
+	} else {
+		update.ClearRevokedAt()
+	}
+
+	if _, err := update.Save(ctx); err != nil {
+		if gen.IsNotFound(err) {
+			return response.NewNotFoundError(domainapikey.MsgAPIKeyNotFound, err)
+		}
+		return response.NewInternalServerError(domainapikey.MsgUpdateAPIKeyFailed, err)
+	}
+	return nil
+}
+
+// UpdateLastUsedAt 更新最近使用时间
+func (r *APIKeyRepositoryImpl) UpdateLastUsedAt(ctx context.Context, id string, usedAt time.Time) error {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return response.NewInvalidDataError(domainapikey.MsgInvalidAPIKeyID, err)
+	}
+
+	if err := r.client.APIKey.UpdateOneID(keyID).SetLastUsedAt(usedAt).Exec(ctx); err != nil {
+		if gen.IsNotFound(err) {
+			return response.NewNotFoundError(domainapikey.MsgAPIKeyNotFound, err)
+		}
+		return response.NewInternalServerError(domainapikey.MsgUpdateAPIKeyFailed, err)
+	}
+	return nil
+}
+
+// entAPIKeyToEntity 将Ent API Key转换为领域实体
+func (r *APIKeyRepositoryImpl) entAPIKeyToEntity(entKey *gen.APIKey) *entity.APIKey {
+	key := entity.NewAPIKey(
+		entKey.Name,
+		entKey.Prefix,
+		entKey.KeyHash,
+		entKey.OwnerID,
+		entKey.Scopes,
+		entKey.ExpiresAt,
+	)
+	key.SetID(entKey.ID.String())
+	key.SetCreatedAt(entKey.CreatedAt)
+	key.SetUsage(entKey.LastUsedAt, entKey.RevokedAt)
+	return key
+}
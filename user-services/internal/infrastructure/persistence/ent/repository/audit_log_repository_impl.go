@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"common/response"
+	"user-services/internal/domain/audit/entity"
+	domainaudit "user-services/internal/domain/audit/errors"
+	"user-services/internal/domain/audit/repository"
+	"user-services/internal/infrastructure/persistence/ent/gen"
+	entauditlog "user-services/internal/infrastructure/persistence/ent/gen/auditlog"
+)
+
+// AuditLogRepositoryImpl Ent审计日志仓储实现
+type AuditLogRepositoryImpl struct {
+	client *gen.Client
+}
+
+// NewAuditLogRepository 创建审计日志仓储
+func NewAuditLogRepository(client *gen.Client) repository.AuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		client: client,
+	}
+}
+
+// Create 写入审计记录
+func (r *AuditLogRepositoryImpl) Create(ctx context.Context, log *entity.AuditLog) error {
+	create := r.client.AuditLog.Create().
+		SetActorID(log.ActorID()).
+		SetDomain(log.Domain()).
+		SetAction(log.Action()).
+		SetResource(log.Resource()).
+		SetClientIP(log.ClientIP())
+	if log.Detail() != nil {
+		create.SetDetail(log.Detail())
+	}
+
+	created, err := create.Save(ctx)
+	if err != nil {
+		return response.NewInternalServerError(domainaudit.MsgCreateAuditLogFailed, err)
+	}
+
+	log.SetID(created.ID.String())
+	log.SetCreatedAt(created.CreatedAt)
+	return nil
+}
+
+// List 按时间倒序分页查询审计记录
+func (r *AuditLogRepositoryImpl) List(ctx context.Context, filter repository.AuditLogFilter, offset, limit int) ([]*entity.AuditLog, int64, error) {
+	baseQuery := r.client.AuditLog.Query()
+	if filter.Domain != "" {
+		baseQuery.Where(entauditlog.Domain(filter.Domain))
+	}
+	if filter.ActorID != "" {
+		baseQuery.Where(entauditlog.ActorID(filter.ActorID))
+	}
+	if filter.Action != "" {
+		baseQuery.Where(entauditlog.Action(filter.Action))
+	}
+	if filter.Resource != "" {
+		baseQuery.Where(entauditlog.Resource(filter.Resource))
+	}
+	if filter.StartTime != nil {
+		baseQuery.Where(entauditlog.CreatedAtGTE(*filter.StartTime))
+	}
+	if filter.EndTime != nil {
+		baseQuery.Where(entauditlog.CreatedAtLT(*filter.EndTime))
+	}
+
+	total, err := baseQuery.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, response.NewInternalServerError(domainaudit.MsgQueryAuditLogFailed, err)
+	}
+
+	entLogs, err := baseQuery.
+		Offset(offset).
+		Limit(limit).
+		Order(gen.Desc(entauditlog.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, 0, response.NewInternalServerError(domainaudit.MsgQueryAuditLogFailed, err)
+	}
+
+	logs := make([]*entity.AuditLog, 0, len(entLogs))
+	for _, entLog := range entLogs {
+		logs = append(logs, r.entAuditLogToEntity(entLog))
+	}
+	return logs, int64(total), nil
+}
+
+// entAuditLogToEntity 将Ent审计日志转换为领域实体
+func (r *AuditLogRepositoryImpl) entAuditLogToEntity(entLog *gen.AuditLog) *entity.AuditLog {
+	log := entity.NewAuditLog(
+		entLog.ActorID,
+		entLog.Domain,
+		entLog.Action,
+		entLog.Resource,
+		entLog.Detail,
+		entLog.ClientIP,
+	)
+	log.SetID(entLog.ID.String())
+	log.SetCreatedAt(entLog.CreatedAt)
+	return log
+}
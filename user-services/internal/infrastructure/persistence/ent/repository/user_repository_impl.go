@@ -1,14 +1,24 @@
 package repository
 
 import (
-	"common/response"
 	"context"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/google/uuid"
+
+	"common/pkg/filter"
+	"common/pkg/pagination"
+	"common/response"
 	"user-services/internal/domain/user/entity"
+	domainuser "user-services/internal/domain/user/errors"
 	"user-services/internal/domain/user/repository"
+	uservo "user-services/internal/domain/user/valueobject"
 	"user-services/internal/infrastructure/persistence/ent/gen"
-	domainuser "user-services/internal/domain/user/errors"
+	"user-services/internal/infrastructure/persistence/ent/gen/predicate"
 	entuser "user-services/internal/infrastructure/persistence/ent/gen/user"
-	"github.com/google/uuid"
+	"user-services/internal/infrastructure/persistence/ent/schema"
 )
 
 // UserRepositoryImpl Ent用户仓储实现
@@ -25,21 +35,24 @@ func NewUserRepository(client *gen.Client) repository.UserRepository {
 
 // Create 保存用户
 func (r *UserRepositoryImpl) Create(ctx context.Context, userEntity *entity.User) error {
-	// 检查手机号是否已存在
-	exists, err := r.ExistsByPhoneNumber(ctx, userEntity.PhoneNumber())
-	if err != nil {
-		return err // 直接返回错误，因为ExistsByPhoneNumber已经包装过
-	}
-	if exists {
-		return response.NewAlreadyExistsError(domainuser.MsgPhoneAlreadyExists)
+	// 检查手机号是否已存在（微信注册的用户可以没有手机号）
+	if userEntity.PhoneNumber() != "" {
+		exists, err := r.ExistsByPhoneNumber(ctx, userEntity.PhoneNumber())
+		if err != nil {
+			return err // 直接返回错误，因为ExistsByPhoneNumber已经包装过
+		}
+		if exists {
+			return response.NewAlreadyExistsError(domainuser.MsgPhoneAlreadyExists)
+		}
 	}
 
 	user, err := r.client.User.Create().
 		SetOpenID(userEntity.OpenID()).
 		SetName(userEntity.Name()).
-		SetPhoneNumber(userEntity.PhoneNumber()).
+		SetNillablePhoneNumber(nullablePhoneNumber(userEntity.PhoneNumber())).
 		SetPassword(userEntity.Password()).
 		SetGender(userEntity.Gender()).
+		SetStatus(userEntity.Status().String()).
 		Save(ctx)
 
 	if err != nil {
@@ -57,6 +70,37 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, userEntity *entity.User
 	return nil
 }
 
+// CreateBatch 批量保存用户，所有用户在一条INSERT语句中写入
+// 手机号和open_id的唯一性由调用方预先校验，并发导致的冲突由唯一索引兜底，整批失败
+func (r *UserRepositoryImpl) CreateBatch(ctx context.Context, users []*entity.User) error {
+	builders := make([]*gen.UserCreate, 0, len(users))
+	for _, userEntity := range users {
+		builders = append(builders, r.client.User.Create().
+			SetOpenID(userEntity.OpenID()).
+			SetName(userEntity.Name()).
+			SetNillablePhoneNumber(nullablePhoneNumber(userEntity.PhoneNumber())).
+			SetPassword(userEntity.Password()).
+			SetGender(userEntity.Gender()).
+			SetStatus(userEntity.Status().String()))
+	}
+
+	saved, err := r.client.User.CreateBulk(builders...).Save(ctx)
+	if err != nil {
+		if gen.IsConstraintError(err) {
+			return response.NewAlreadyExistsError(domainuser.MsgUserAlreadyExists, err)
+		}
+		return response.NewInternalServerError(domainuser.MsgCreateUserFailed, err)
+	}
+
+	for i, user := range saved {
+		users[i].SetID(user.ID.String())
+		users[i].SetUpdatedAt(user.UpdatedAt)
+		users[i].SetCreatedAt(user.CreatedAt)
+	}
+
+	return nil
+}
+
 // Update 更新用户信息
 func (r *UserRepositoryImpl) Update(ctx context.Context, userEntity *entity.User) error {
 	// 将字符串类型的ID转换为uuid.UUID类型
@@ -67,11 +111,17 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, userEntity *entity.User
 
 	// 更新用户时，updated_at 字段会自动更新为当前时间
 	// 因为在数据库层面已经配置了 UpdateDefault(time.Now)
-	_, err = r.client.User.UpdateOneID(userID).
+	// 软删除拦截器只作用于查询，更新需要自行排除已删除的用户，否则按ID仍可修改已删除的用户
+	update := r.client.User.UpdateOneID(userID).
+		Where(entuser.DeletedAtIsNil()).
 		SetName(userEntity.Name()).
-		SetPhoneNumber(userEntity.PhoneNumber()).
-		SetGender(userEntity.Gender()).
-		Save(ctx)
+		SetGender(userEntity.Gender())
+	if phoneNumber := nullablePhoneNumber(userEntity.PhoneNumber()); phoneNumber != nil {
+		update.SetPhoneNumber(*phoneNumber)
+	} else {
+		update.ClearPhoneNumber()
+	}
+	updated, err := update.Save(ctx)
 
 	if err != nil {
 		if gen.IsNotFound(err) {
@@ -82,6 +132,113 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, userEntity *entity.User
 		}
 		return response.NewInternalServerError(domainuser.MsgUpdateUserFailed, err)
 	}
+	userEntity.SetUpdatedAt(updated.UpdatedAt)
+	return nil
+}
+
+// UpdateStatus 更新账号状态
+func (r *UserRepositoryImpl) UpdateStatus(ctx context.Context, userEntity *entity.User) error {
+	userID, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	updated, err := r.client.User.UpdateOneID(userID).
+		Where(entuser.DeletedAtIsNil()).
+		SetStatus(userEntity.Status().String()).
+		Save(ctx)
+	if err != nil {
+		if gen.IsNotFound(err) {
+			return response.NewNotFoundError(domainuser.MsgUserNotFound, err)
+		}
+		return response.NewInternalServerError(domainuser.MsgUpdateUserFailed, err)
+	}
+	userEntity.SetUpdatedAt(updated.UpdatedAt)
+	return nil
+}
+
+// UpdateTOTP 更新用户的两步验证状态
+func (r *UserRepositoryImpl) UpdateTOTP(ctx context.Context, userEntity *entity.User) error {
+	userID, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	update := r.client.User.UpdateOneID(userID).
+		Where(entuser.DeletedAtIsNil()).
+		SetTotpEnabled(userEntity.TOTPEnabled())
+	if userEntity.TOTPSecret() != "" {
+		update.SetTotpSecret(userEntity.TOTPSecret())
+	} else {
+		update.ClearTotpSecret()
+	}
+	if len(userEntity.RecoveryCodeHashes()) > 0 {
+		update.SetTotpRecoveryCodes(userEntity.RecoveryCodeHashes())
+	} else {
+		update.ClearTotpRecoveryCodes()
+	}
+
+	if _, err := update.Save(ctx); err != nil {
+		if gen.IsNotFound(err) {
+			return response.NewNotFoundError(domainuser.MsgUserNotFound, err)
+		}
+		return response.NewInternalServerError(domainuser.MsgUpdateUserFailed, err)
+	}
+	return nil
+}
+
+// UpdateRecoveryCodes 以恢复码未被修改为条件更新恢复码
+// 恢复码互不相同，数量一致且包含 expected 的每一项即与 expected 相同
+func (r *UserRepositoryImpl) UpdateRecoveryCodes(ctx context.Context, userEntity *entity.User, expected []string) (bool, error) {
+	userID, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return false, response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	update := r.client.User.Update().
+		Where(entuser.ID(userID), entuser.DeletedAtIsNil(), entuser.TotpEnabled(true)).
+		Where(func(s *sql.Selector) {
+			predicates := []*sql.Predicate{sqljson.LenEQ(entuser.FieldTotpRecoveryCodes, len(expected))}
+			for _, hash := range expected {
+				predicates = append(predicates, sqljson.ValueContains(entuser.FieldTotpRecoveryCodes, hash))
+			}
+			s.Where(sql.And(predicates...))
+		})
+	if len(userEntity.RecoveryCodeHashes()) > 0 {
+		update.SetTotpRecoveryCodes(userEntity.RecoveryCodeHashes())
+	} else {
+		update.ClearTotpRecoveryCodes()
+	}
+
+	affected, err := update.Save(ctx)
+	if err != nil {
+		return false, response.NewInternalServerError(domainuser.MsgUpdateUserFailed, err)
+	}
+	return affected > 0, nil
+}
+
+// UpdatePassword 更新用户密码和历史密码
+func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, userEntity *entity.User) error {
+	userID, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	update := r.client.User.UpdateOneID(userID).
+		Where(entuser.DeletedAtIsNil()).
+		SetPassword(userEntity.Password())
+	if len(userEntity.PasswordHistory()) > 0 {
+		update.SetPasswordHistory(userEntity.PasswordHistory())
+	} else {
+		update.ClearPasswordHistory()
+	}
+
+	if _, err := update.Save(ctx); err != nil {
+		if gen.IsNotFound(err) {
+			return response.NewNotFoundError(domainuser.MsgUserNotFound, err)
+		}
+		return response.NewInternalServerError(domainuser.MsgUpdateUserFailed, err)
+	}
 	return nil
 }
 
@@ -113,9 +270,9 @@ func (r *UserRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*en
 }
 
 // ListWithFilter 获取用户列表
-func (r *UserRepositoryImpl) ListWithFilter(ctx context.Context, filter *repository.UserListFilter, offset, limit int) ([]*entity.User, int64, error) {
+func (r *UserRepositoryImpl) ListWithFilter(ctx context.Context, q *filter.Query, offset, limit int) ([]*entity.User, int64, error) {
 	// 构建基础查询（只构建一次）
-	baseQuery := r.buildUserQuery(filter)
+	baseQuery := r.buildUserQuery(q)
 
 	// 先查询总数（使用 Clone 避免修改原始查询）
 	total, err := baseQuery.Clone().Count(ctx)
@@ -123,11 +280,12 @@ func (r *UserRepositoryImpl) ListWithFilter(ctx context.Context, filter *reposit
 		return nil, 0, response.NewInternalServerError(domainuser.MsgQueryUserCountFailed, err)
 	}
 
-	// 再查询分页数据（复用相同的查询条件）
+	// 再查询分页数据（复用相同的查询条件），最后按id排序保证排序字段相同的记录在翻页时顺序稳定
 	entUsers, err := baseQuery.
 		Offset(offset).
 		Limit(limit).
-		Order(gen.Desc(entuser.FieldCreatedAt)).
+		Order(filter.OrderOptions[entuser.OrderOption](q)...).
+		Order(gen.Desc(entuser.FieldID)).
 		All(ctx)
 	if err != nil {
 		return nil, 0, response.NewInternalServerError(domainuser.MsgQueryUserListFailed, err)
@@ -143,6 +301,70 @@ func (r *UserRepositoryImpl) ListWithFilter(ctx context.Context, filter *reposit
 	return users, int64(total), nil
 }
 
+// ListWithCursor 键集分页获取用户列表
+// 以 (created_at, id) 作为排序键，id 保证创建时间相同的记录顺序稳定，翻页时不会重复或遗漏
+func (r *UserRepositoryImpl) ListWithCursor(ctx context.Context, q *filter.Query, after *pagination.Cursor, limit int) ([]*entity.User, error) {
+	query := r.buildUserQuery(q)
+
+	if after != nil {
+		afterID, err := uuid.Parse(after.ID)
+		if err != nil {
+			return nil, domainuser.ErrInvalidCursor
+		}
+		query = query.Where(entuser.Or(
+			entuser.CreatedAtLT(after.CreatedAt),
+			entuser.And(entuser.CreatedAtEQ(after.CreatedAt), entuser.IDLT(afterID)),
+		))
+	}
+
+	entUsers, err := query.
+		Order(gen.Desc(entuser.FieldCreatedAt), gen.Desc(entuser.FieldID)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, response.NewInternalServerError(domainuser.MsgQueryUserListFailed, err)
+	}
+
+	users := make([]*entity.User, 0, len(entUsers))
+	for _, entUser := range entUsers {
+		users = append(users, r.entUserToEntity(entUser))
+	}
+
+	return users, nil
+}
+
+// IterateWithFilter 按键集分批读取用户，同一时刻只持有一批记录，内存占用与结果总数无关
+func (r *UserRepositoryImpl) IterateWithFilter(ctx context.Context, q *filter.Query, batchSize int, fn func(*entity.User) error) error {
+	var after *pagination.Cursor
+	for {
+		users, err := r.ListWithCursor(ctx, q, after, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if len(users) < batchSize {
+			return nil
+		}
+
+		last := users[len(users)-1]
+		after = &pagination.Cursor{CreatedAt: last.CreatedAt(), ID: last.ID()}
+	}
+}
+
+// CountWithFilter 统计符合过滤条件的用户数
+func (r *UserRepositoryImpl) CountWithFilter(ctx context.Context, q *filter.Query) (int64, error) {
+	total, err := r.buildUserQuery(q).Count(ctx)
+	if err != nil {
+		return 0, response.NewInternalServerError(domainuser.MsgQueryUserCountFailed, err)
+	}
+
+	return int64(total), nil
+}
+
 func (r *UserRepositoryImpl) ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error) {
 	exists, err := r.client.User.
 		Query().
@@ -155,28 +377,22 @@ func (r *UserRepositoryImpl) ExistsByPhoneNumber(ctx context.Context, phoneNumbe
 	return exists, nil
 }
 
-// 提取一个私有方法来构建查询条件
-func (r *UserRepositoryImpl) buildUserQuery(filter *repository.UserListFilter) *gen.UserQuery {
-	query := r.client.User.Query()
-
-	if filter == nil {
-		return query
+// ExistsByOpenID 根据open_id查询用户是否存在
+func (r *UserRepositoryImpl) ExistsByOpenID(ctx context.Context, openID string) (bool, error) {
+	exists, err := r.client.User.
+		Query().
+		Where(entuser.OpenID(openID)).
+		Exist(ctx)
+	if err != nil {
+		return false, response.NewInternalServerError(domainuser.MsgCheckOpenIDExistsFailed, err)
 	}
 
-	if filter.Name != nil && *filter.Name != "" {
-		query = query.Where(entuser.NameContains(*filter.Name))
-	}
-	if filter.Gender != nil {
-		query = query.Where(entuser.Gender(*filter.Gender))
-	}
-	if filter.StartTime != nil {
-		query = query.Where(entuser.CreatedAtGTE(*filter.StartTime))
-	}
-	if filter.EndTime != nil {
-		query = query.Where(entuser.CreatedAtLT(*filter.EndTime))
-	}
+	return exists, nil
+}
 
-	return query
+// buildUserQuery 按过滤条件构建查询，排序由调用方决定
+func (r *UserRepositoryImpl) buildUserQuery(q *filter.Query) *gen.UserQuery {
+	return r.client.User.Query().Where(filter.Predicates[predicate.User](q)...)
 }
 
 // entUserToEntity 将Ent用户实体转换为领域用户实体
@@ -185,11 +401,16 @@ func (r *UserRepositoryImpl) entUserToEntity(entUser *gen.User) *entity.User {
 		return nil
 	}
 
+	var phoneNumber string
+	if entUser.PhoneNumber != nil {
+		phoneNumber = *entUser.PhoneNumber
+	}
+
 	// 创建领域用户实体
 	user := entity.NewUser(
 		entUser.OpenID,
 		entUser.Name,
-		entUser.PhoneNumber,
+		phoneNumber,
 		entUser.Password,
 		entUser.Gender,
 	)
@@ -198,6 +419,14 @@ func (r *UserRepositoryImpl) entUserToEntity(entUser *gen.User) *entity.User {
 	user.SetID(entUser.ID.String())
 	user.SetCreatedAt(entUser.CreatedAt)
 	user.SetUpdatedAt(entUser.UpdatedAt)
+	user.SetStatus(uservo.UserStatus(entUser.Status))
+	user.SetPasswordHistory(entUser.PasswordHistory)
+
+	var totpSecret string
+	if entUser.TotpSecret != nil {
+		totpSecret = *entUser.TotpSecret
+	}
+	user.SetTOTP(totpSecret, entUser.TotpEnabled, entUser.TotpRecoveryCodes)
 
 	return user
 }
@@ -233,4 +462,95 @@ func (r *UserRepositoryImpl) FindByPhoneNumber(ctx context.Context, phoneNumber
 		return nil, response.NewInternalServerError(domainuser.MsgFindUserByPhoneFailed, err)
 	}
 	return r.entUserToEntity(entUser), nil
-}
\ No newline at end of file
+}
+
+// FindByOpenID 根据第三方平台open_id获取用户
+func (r *UserRepositoryImpl) FindByOpenID(ctx context.Context, openID string) (*entity.User, error) {
+	entUser, err := r.client.User.
+		Query().
+		Where(entuser.OpenIDEQ(openID)).
+		Only(ctx)
+	if err != nil {
+		if gen.IsNotFound(err) {
+			return nil, response.NewNotFoundError(domainuser.MsgUserNotFound, err)
+		}
+		return nil, response.NewInternalServerError(domainuser.MsgFindUserByOpenIDFailed, err)
+	}
+	return r.entUserToEntity(entUser), nil
+}
+
+// nullablePhoneNumber 空手机号在数据库中存储为NULL，避免唯一索引冲突
+func nullablePhoneNumber(phoneNumber string) *string {
+	if phoneNumber == "" {
+		return nil
+	}
+	return &phoneNumber
+}
+
+// SoftDelete 软删除用户，同时清空alive使手机号和open_id不再占用唯一索引
+func (r *UserRepositoryImpl) SoftDelete(ctx context.Context, id string) error {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	affected, err := r.client.User.Update().
+		Where(entuser.ID(userID), entuser.DeletedAtIsNil()).
+		SetDeletedAt(time.Now()).
+		ClearAlive().
+		Save(ctx)
+	if err != nil {
+		return response.NewInternalServerError(domainuser.MsgDeleteUserFailed, err)
+	}
+	if affected == 0 {
+		return response.NewNotFoundError(domainuser.MsgUserNotFound)
+	}
+	return nil
+}
+
+// Restore 恢复已软删除的用户，手机号或open_id已被新用户使用时返回冲突
+func (r *UserRepositoryImpl) Restore(ctx context.Context, id string) (*entity.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, response.NewInvalidDataError(domainuser.MsgInvalidUserID, err)
+	}
+
+	affected, err := r.client.User.Update().
+		Where(entuser.ID(userID), entuser.DeletedAtNotNil()).
+		ClearDeletedAt().
+		SetAlive(true).
+		Save(ctx)
+	if err != nil {
+		if gen.IsConstraintError(err) {
+			return nil, response.NewAlreadyExistsError(domainuser.MsgRestoreUserConflict, err)
+		}
+		return nil, response.NewInternalServerError(domainuser.MsgRestoreUserFailed, err)
+	}
+	if affected == 0 {
+		return nil, response.NewNotFoundError(domainuser.MsgDeletedUserNotFound)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// CountDeletedBefore 统计在指定时间之前软删除的用户数
+func (r *UserRepositoryImpl) CountDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	count, err := r.client.User.Query().
+		Where(entuser.DeletedAtLT(before)).
+		Count(schema.IncludeDeleted(ctx))
+	if err != nil {
+		return 0, response.NewInternalServerError(domainuser.MsgQueryUserCountFailed, err)
+	}
+	return count, nil
+}
+
+// PurgeDeletedBefore 彻底删除在指定时间之前软删除的用户
+func (r *UserRepositoryImpl) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	affected, err := r.client.User.Delete().
+		Where(entuser.DeletedAtLT(before)).
+		Exec(ctx)
+	if err != nil {
+		return 0, response.NewInternalServerError(domainuser.MsgPurgeUsersFailed, err)
+	}
+	return affected, nil
+}
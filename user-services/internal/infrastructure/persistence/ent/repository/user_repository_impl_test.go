@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"common/response"
+	"user-services/internal/domain/user/entity"
+	"user-services/internal/domain/user/repository"
+	uservo "user-services/internal/domain/user/valueobject"
+	"user-services/internal/infrastructure/persistence/ent/gen"
+	"user-services/internal/infrastructure/persistence/ent/gen/enttest"
+	"user-services/internal/infrastructure/persistence/ent/schema"
+)
+
+// newTestUserRepository 使用内存SQLite创建用户仓储，每个测试独立一个数据库
+func newTestUserRepository(t *testing.T) (*gen.Client, repository.UserRepository) {
+	client := enttest.Open(t, dialect.SQLite, "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = client.Close() })
+	return client, NewUserRepository(client)
+}
+
+func createTestUser(t *testing.T, repo repository.UserRepository, openID, phoneNumber string) *entity.User {
+	t.Helper()
+	user := entity.NewUser(openID, "张三", phoneNumber, "password-hash", uservo.GenderMale.Int())
+	require.NoError(t, repo.Create(context.Background(), user))
+	return user
+}
+
+func TestUserRepository_SoftDeleteHidesUser(t *testing.T) {
+	ctx := context.Background()
+	client, repo := newTestUserRepository(t)
+
+	deleted := createTestUser(t, repo, "wx-1", "+8613800138000")
+	createTestUser(t, repo, "wx-2", "+8613900139000")
+	require.NoError(t, repo.SoftDelete(ctx, deleted.ID()))
+
+	_, err := repo.GetByID(ctx, deleted.ID())
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+	_, err = repo.FindByPhoneNumber(ctx, "+8613800138000")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+	_, err = repo.FindByOpenID(ctx, "wx-1")
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+
+	exists, err := repo.ExistsByPhoneNumber(ctx, "+8613800138000")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	users, total, err := repo.List(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "wx-2", users[0].OpenID())
+
+	// 已删除的记录仍在表中，只是默认查询不可见
+	count, err := client.User.Query().Count(schema.IncludeDeleted(ctx))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// 重复删除视为不存在
+	err = repo.SoftDelete(ctx, deleted.ID())
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+}
+
+func TestUserRepository_UpdatesSkipDeletedUsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(context.Context, repository.UserRepository, *entity.User) error
+	}{
+		{name: "Update", update: func(ctx context.Context, repo repository.UserRepository, user *entity.User) error {
+			user.Rename("李四")
+			return repo.Update(ctx, user)
+		}},
+		{name: "UpdateStatus", update: func(ctx context.Context, repo repository.UserRepository, user *entity.User) error {
+			user.SetStatus(uservo.UserStatusDisabled)
+			return repo.UpdateStatus(ctx, user)
+		}},
+		{name: "UpdateTOTP", update: func(ctx context.Context, repo repository.UserRepository, user *entity.User) error {
+			user.SetTOTP("secret", true, []string{"code-hash"})
+			return repo.UpdateTOTP(ctx, user)
+		}},
+		{name: "UpdatePassword", update: func(ctx context.Context, repo repository.UserRepository, user *entity.User) error {
+			user.ChangePassword("new-password-hash", 3)
+			return repo.UpdatePassword(ctx, user)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client, repo := newTestUserRepository(t)
+
+			user := createTestUser(t, repo, "wx-1", "+8613800138000")
+			before, err := client.User.Get(ctx, uuid.MustParse(user.ID()))
+			require.NoError(t, err)
+			require.NoError(t, repo.SoftDelete(ctx, user.ID()))
+
+			err = tt.update(ctx, repo, user)
+			assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+
+			after, err := client.User.Get(schema.IncludeDeleted(ctx), before.ID)
+			require.NoError(t, err)
+			assert.Equal(t, before.Name, after.Name)
+			assert.Equal(t, before.Status, after.Status)
+			assert.Equal(t, before.Password, after.Password)
+			assert.Equal(t, before.TotpEnabled, after.TotpEnabled)
+		})
+	}
+}
+
+func TestUserRepository_RestoreConflict(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newTestUserRepository(t)
+
+	original := createTestUser(t, repo, "wx-1", "+8613800138000")
+	require.NoError(t, repo.SoftDelete(ctx, original.ID()))
+
+	// 已删除的用户不再占用手机号
+	replacement := createTestUser(t, repo, "wx-2", "+8613800138000")
+
+	_, err := repo.Restore(ctx, original.ID())
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeAlreadyExists), "got %v", err)
+
+	// 占用手机号的用户删除后可以恢复
+	require.NoError(t, repo.SoftDelete(ctx, replacement.ID()))
+	restored, err := repo.Restore(ctx, original.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "+8613800138000", restored.PhoneNumber())
+
+	_, err = repo.Restore(ctx, original.ID())
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+}
+
+func TestUserRepository_PurgeDeletedBefore(t *testing.T) {
+	ctx := context.Background()
+	client, repo := newTestUserRepository(t)
+
+	deleted := createTestUser(t, repo, "wx-1", "+8613800138000")
+	createTestUser(t, repo, "wx-2", "+8613900139000")
+	require.NoError(t, repo.SoftDelete(ctx, deleted.ID()))
+
+	// 删除时间晚于截止时间的用户保留
+	purged, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	cutoff := time.Now().Add(time.Second)
+	count, err := repo.CountDeletedBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	purged, err = repo.PurgeDeletedBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	// 彻底删除后包括已删除记录在内都查不到，未删除的用户不受影响
+	remaining, err := client.User.Query().All(schema.IncludeDeleted(ctx))
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "wx-2", remaining[0].OpenID)
+
+	_, err = repo.Restore(ctx, deleted.ID())
+	assert.True(t, response.IsErrorType(err, response.ErrorTypeNotFound), "got %v", err)
+}
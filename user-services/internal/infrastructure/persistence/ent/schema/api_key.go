@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// APIKey holds the schema definition for the APIKey entity.
+type APIKey struct {
+	ent.Schema
+}
+
+// Annotations of the APIKey.
+func (APIKey) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Table("api_keys"),
+		entsql.WithComments(true),
+	}
+}
+
+// Fields of the APIKey.
+func (APIKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New).
+			Immutable().
+			Comment("API Key ID"),
+		field.String("name").
+			MaxLen(100).
+			NotEmpty().
+			Comment("名称，用于说明用途"),
+		field.String("prefix").
+			MaxLen(16).
+			NotEmpty().
+			Immutable().
+			Comment("密钥前缀，用于查找密钥，可公开展示"),
+		field.String("key_hash").
+			MaxLen(64).
+			NotEmpty().
+			Immutable().
+			Sensitive().
+			Comment("密钥SHA-256哈希"),
+		field.String("owner_id").
+			MaxLen(64).
+			NotEmpty().
+			Comment("所有者，作为调用方身份参与权限校验"),
+		field.Strings("scopes").
+			Optional().
+			Comment("授权范围"),
+		field.Time("expires_at").
+			Optional().
+			Nillable().
+			Comment("过期时间，为空表示永不过期"),
+		field.Time("last_used_at").
+			Optional().
+			Nillable().
+			Comment("最近使用时间"),
+		field.Time("revoked_at").
+			Optional().
+			Nillable().
+			Comment("吊销时间"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable().
+			Comment("创建时间"),
+	}
+}
+
+// Edges of the APIKey.
+func (APIKey) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the APIKey.
+func (APIKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("prefix").Unique(),
+		index.Fields("owner_id"),
+	}
+}
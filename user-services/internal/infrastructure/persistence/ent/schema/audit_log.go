@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// AuditLog holds the schema definition for the AuditLog entity.
+type AuditLog struct {
+	ent.Schema
+}
+
+// Annotations of the AuditLog.
+func (AuditLog) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+	}
+}
+
+// Fields of the AuditLog.
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New).
+			Immutable().
+			Comment("审计日志ID"),
+		field.String("actor_id").
+			MaxLen(64).
+			Immutable().
+			Comment("操作者，用户ID或API Key所有者，系统操作为system"),
+		field.String("domain").
+			MaxLen(64).
+			Default("").
+			Immutable().
+			Comment("所属租户(Casbin域)，* 表示平台级"),
+		field.String("action").
+			MaxLen(64).
+			Immutable().
+			Comment("操作类型，如 policy.add、role.assign"),
+		field.String("resource").
+			MaxLen(255).
+			Immutable().
+			Comment("操作对象"),
+		field.JSON("detail", map[string]any{}).
+			Optional().
+			Immutable().
+			Comment("操作详情"),
+		field.String("client_ip").
+			MaxLen(64).
+			Optional().
+			Immutable().
+			Comment("客户端IP"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable().
+			Comment("操作时间"),
+	}
+}
+
+// Edges of the AuditLog.
+func (AuditLog) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the AuditLog.
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("domain"),
+		index.Fields("actor_id"),
+		index.Fields("action"),
+		index.Fields("created_at"),
+	}
+}
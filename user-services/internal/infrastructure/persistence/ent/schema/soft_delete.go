@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"context"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+
+	"user-services/internal/infrastructure/persistence/ent/gen/intercept"
+)
+
+type includeDeletedKey struct{}
+
+// IncludeDeleted 返回的context中查询不再过滤已软删除的记录，用于恢复和彻底删除
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// softDeleteInterceptor 默认只查询未删除的记录，即 deleted_at 为空
+func softDeleteInterceptor() ent.Interceptor {
+	return intercept.TraverseFunc(func(ctx context.Context, q intercept.Query) error {
+		if include, _ := ctx.Value(includeDeletedKey{}).(bool); include {
+			return nil
+		}
+		q.WhereP(sql.FieldIsNull("deleted_at"))
+		return nil
+	})
+}
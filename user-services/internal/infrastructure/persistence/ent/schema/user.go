@@ -43,9 +43,27 @@ func (User) Fields() []ent.Field {
 			MaxLen(100).
 			Sensitive().
 			Comment("密码"),
+		field.Strings("password_history").
+			Optional().
+			Sensitive().
+			Comment("历史密码哈希，最近使用的在前，用于禁止重复使用近期密码"),
 		field.String("phone_number").
-			Default("").
-			Comment("手机号"),
+			Optional().
+			Nillable().
+			Comment("手机号(E.164格式，如+8613800138000)，微信注册的用户在绑定前为空"),
+		field.String("totp_secret").
+			MaxLen(255).
+			Optional().
+			Nillable().
+			Sensitive().
+			Comment("TOTP密钥(AES-GCM加密)，未绑定身份验证器时为空"),
+		field.Bool("totp_enabled").
+			Default(false).
+			Comment("是否已启用TOTP两步验证"),
+		field.Strings("totp_recovery_codes").
+			Optional().
+			Sensitive().
+			Comment("两步验证恢复码哈希，每个恢复码只能使用一次"),
 		field.Int("gender").
 			Validate(func(i int) error {
 				switch i {
@@ -55,6 +73,16 @@ func (User) Fields() []ent.Field {
 					return errors.New("invalid gender value")
 				}
 			}).Comment("性别"),
+		field.String("status").
+			MaxLen(16).
+			Default(uservo.UserStatusActive.String()).
+			Validate(func(s string) error {
+				if !uservo.UserStatus(s).IsValid() {
+					return errors.New("invalid user status")
+				}
+				return nil
+			}).
+			Comment("账号状态：pending待激活、active正常、disabled已停用、locked已锁定"),
 		field.Time("created_at").
 			Default(time.Now).
 			Comment("创建时间"),
@@ -62,6 +90,17 @@ func (User) Fields() []ent.Field {
 			Default(time.Now).
 			UpdateDefault(time.Now).
 			Comment("更新时间"),
+		field.Time("deleted_at").
+			Optional().
+			Nillable().
+			Comment("删除时间，非空表示已软删除"),
+		// MySQL唯一索引中NULL互不冲突：未删除时为true，删除后置为NULL，
+		// 与手机号、open_id组成联合唯一索引，已删除用户不再占用手机号和open_id
+		field.Bool("alive").
+			Optional().
+			Nillable().
+			Default(true).
+			Comment("未删除时为1，软删除后为NULL，用于唯一索引"),
 	}
 }
 
@@ -73,8 +112,16 @@ func (User) Edges() []ent.Edge {
 // Indexes of the User.
 func (User) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("open_id").Unique(),
-		index.Fields("phone_number").Unique(),
+		index.Fields("open_id", "alive").Unique(),
+		index.Fields("phone_number", "alive").Unique(),
 		index.Fields("created_at"),
+		index.Fields("deleted_at"),
+	}
+}
+
+// Interceptors of the User.
+func (User) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		softDeleteInterceptor(),
 	}
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"go.uber.org/fx"
 
+	commonMiddleware "common/middleware"
 	"user-services/internal/interfaces/http/handler"
 	"user-services/internal/interfaces/http/routes"
 )
@@ -13,11 +14,18 @@ var InterfaceModuleFinal = fx.Module("interface_final",
 		handler.NewUserHandler,
 		handler.NewHealthHandler,
 		handler.NewAuthHandler,
+		handler.NewMFAHandler,
+		handler.NewPasswordHandler,
+		handler.NewPermissionHandler,
+		handler.NewJWKSHandler,
+		handler.NewUserImportHandler,
+		handler.NewUserExportHandler,
 
 		// HTTP Server
 		NewServer,
 
 		// Middleware
+		commonMiddleware.NewOwnership,
 		NewCasbinMiddleware,
 		NewAuthMiddleware,
 	),
@@ -10,3 +10,46 @@ type LoginRequest struct {
 type WeChatLoginRequest struct {
 	Code string `json:"code" binding:"required" label:"微信授权码" example:"wx_auth_code_123456"` // 微信授权后获得的临时授权码
 }
+
+// SendSMSCodeRequest 发送短信验证码请求DTO
+type SendSMSCodeRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" label:"手机号" example:"13800138000"` // 接收验证码的手机号
+}
+
+// SMSLoginRequest 短信验证码登录请求DTO
+type SMSLoginRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" label:"手机号" example:"13800138000"` // 用户手机号码
+	Code        string `json:"code" binding:"required" label:"验证码" example:"123456"`              // 短信验证码
+}
+
+// WeChatBindPhoneRequest 绑定微信手机号请求DTO
+type WeChatBindPhoneRequest struct {
+	Code string `json:"code" binding:"required" label:"手机号授权码" example:"wx_phone_code_123456"` // 手机号快速验证组件返回的code
+}
+
+// RefreshTokenRequest 刷新令牌请求DTO
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" label:"刷新令牌" example:"dGhpcyBpcyBhIHJlZnJlc2ggdG9rZW4"` // 登录或上次刷新时获得的刷新令牌
+}
+
+// MFALoginRequest 两步验证登录请求DTO
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" label:"两步验证令牌" example:"c2hvcnQtbGl2ZWQgbWZhIHRva2Vu"` // 密码登录返回的两步验证令牌
+	Code     string `json:"code" binding:"required" label:"验证码" example:"123456"`                               // 身份验证器中的6位验证码或恢复码
+}
+
+// TOTPCodeRequest 两步验证验证码请求DTO
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required" label:"验证码" example:"123456"` // 身份验证器中的6位验证码，关闭两步验证时也可使用恢复码
+}
+
+// PasswordResetRequest 申请重置密码请求DTO
+type PasswordResetRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" label:"手机号" example:"13800138000"` // 注册时使用的手机号
+}
+
+// PasswordResetConfirmRequest 确认重置密码请求DTO
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required" label:"重置令牌" example:"cmVzZXQgdG9rZW4"`      // 短信中收到的重置令牌
+	NewPassword string `json:"new_password" binding:"required" label:"新密码" example:"newPassword456"` // 新密码，需符合密码策略且不能与最近使用过的密码相同
+}
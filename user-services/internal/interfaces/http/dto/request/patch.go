@@ -0,0 +1,31 @@
+package request
+
+import "encoding/json"
+
+// PatchField JSON合并补丁(RFC 7396)中的字段，区分未提供、null和具体值
+type PatchField[T any] struct {
+	Set   bool // 请求体中出现了该字段
+	Null  bool // 字段值为null，表示清空
+	Value T
+}
+
+// UnmarshalJSON 只有字段出现在请求体中时才会被调用
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// apply 将字段写入更新集合，null写入nil
+func (f PatchField[T]) apply(updates map[string]interface{}, field string, value func(T) interface{}) {
+	switch {
+	case !f.Set:
+	case f.Null:
+		updates[field] = nil
+	default:
+		updates[field] = value(f.Value)
+	}
+}
@@ -0,0 +1,36 @@
+package request
+
+import (
+	"time"
+
+	"common/pkg/pagination"
+)
+
+// PolicyRequest 添加权限策略请求DTO
+type PolicyRequest struct {
+	Subject string `json:"sub" binding:"required,max=100" label:"主体" example:"admin"`           // 用户ID或角色
+	Object  string `json:"obj" binding:"required,max=255" label:"资源" example:"/api/v1/admin/*"` // 资源路径，支持 * 通配
+	Action  string `json:"act" binding:"required,max=20" label:"操作" example:"GET"`              // HTTP方法，"*" 表示全部
+}
+
+// PolicyQueryRequest 查询或删除权限策略的查询参数DTO
+type PolicyQueryRequest struct {
+	Subject string `form:"sub" binding:"omitempty,max=100" label:"主体" example:"admin"`           // 用户ID或角色
+	Object  string `form:"obj" binding:"omitempty,max=255" label:"资源" example:"/api/v1/admin/*"` // 资源路径
+	Action  string `form:"act" binding:"omitempty,max=20" label:"操作" example:"GET"`              // HTTP方法
+}
+
+// AssignRoleRequest 为用户分配角色请求DTO
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=100" label:"角色" example:"admin"` // 角色名称
+}
+
+// ListAuditLogsRequest 审计日志列表请求DTO
+type ListAuditLogsRequest struct {
+	pagination.PageParams
+	ActorID   string     `form:"actor_id" binding:"omitempty,max=64" label:"操作者" example:"user_123456789"`                  // 操作者ID
+	Action    string     `form:"action" binding:"omitempty,max=64" label:"操作类型" example:"role.assign"`                      // 操作类型：policy.add、policy.remove、role.assign、role.unassign
+	Resource  string     `form:"resource" binding:"omitempty,max=255" label:"操作对象" example:"user_123456789"`                // 操作对象，策略为主体，角色为用户ID
+	StartTime *time.Time `form:"start_time" binding:"omitempty" time_format:"2006-01-02" label:"开始时间" example:"2023-01-01"` // 操作时间范围的开始时间，格式：YYYY-MM-DD
+	EndTime   *time.Time `form:"end_time" binding:"omitempty" time_format:"2006-01-02" label:"结束时间" example:"2023-12-31"`   // 操作时间范围的结束时间，格式：YYYY-MM-DD
+}
@@ -1,9 +1,11 @@
 package request
 
 import (
-	"time"
+	"mime/multipart"
 
+	"common/pkg/filter"
 	"common/pkg/pagination"
+	"user-services/internal/domain/user/entity"
 	uservo "user-services/internal/domain/user/valueobject"
 )
 
@@ -12,15 +14,85 @@ type CreateUserRequest struct {
 	OpenID      string        `json:"open_id" binding:"required" label:"开放ID" example:"wx_123456789"`    // 微信OpenID或其他第三方平台的唯一标识
 	Name        string        `json:"name" binding:"required,max=50" label:"昵称" example:"张三"`            // 用户姓名，长度不超过50个字符
 	Gender      uservo.Gender `json:"gender" binding:"required,enum" label:"性别" example:"100"`           // 性别：100-男性，200-女性，300-其他
-	PhoneNumber string        `json:"phone_number" binding:"required" label:"手机号" example:"13800138000"` // 手机号码，需要符合中国大陆手机号格式
-	Password    string        `json:"password" binding:"required" label:"密码" example:"password123"`      // 用户密码，长度至少6位
+	PhoneNumber string        `json:"phone_number" binding:"required" label:"手机号" example:"13800138000"` // 手机号码，不带+时按默认地区(user.phone.default_region)解析，保存为E.164格式
+	Password    string        `json:"password" binding:"required" label:"密码" example:"password123"`      // 用户密码，需符合密码策略(默认至少8位并包含字母和数字)
+}
+
+// UpdateUserRequest 更新用户请求DTO，按JSON合并语义部分更新：未提供的字段保持不变，null表示清空
+type UpdateUserRequest struct {
+	Name        PatchField[string]        `json:"name" swaggertype:"string" label:"昵称" example:"张三"`                   // 用户姓名，长度不超过50个字符，不能清空
+	Gender      PatchField[uservo.Gender] `json:"gender" swaggertype:"integer" label:"性别" example:"100"`               // 性别：100-男性，200-女性，300-其他，不能清空
+	PhoneNumber PatchField[string]        `json:"phone_number" swaggertype:"string" label:"手机号" example:"13800138000"` // 手机号码，null表示解绑，只有绑定了第三方平台的账号可以解绑
+}
+
+// Updates 转换为字段更新集合
+func (r *UpdateUserRequest) Updates() map[string]interface{} {
+	updates := make(map[string]interface{})
+	r.Name.apply(updates, entity.FieldName, func(v string) interface{} { return v })
+	r.Gender.apply(updates, entity.FieldGender, func(v uservo.Gender) interface{} { return v.Int() })
+	r.PhoneNumber.apply(updates, entity.FieldPhoneNumber, func(v string) interface{} { return v })
+	return updates
 }
 
 // ListUsersRequest 用户列表请求DTO
+// 过滤和排序条件不在此声明，由 NewUserListSchema 创建的白名单从查询参数中解析
+// 传入 cursor 参数(第一页为空值)时使用游标分页，此时忽略 page
 type ListUsersRequest struct {
 	pagination.PageParams
-	Name      string         `form:"name" binding:"omitempty,max=50" label:"姓名" example:"张三"`                                   // 用户姓名，支持模糊搜索
-	Gender    *uservo.Gender `form:"gender" binding:"omitempty,enum" label:"性别" example:"100"`                                  // 性别过滤：100-男性，200-女性，300-其他
-	StartTime *time.Time     `form:"start_time" binding:"omitempty" time_format:"2006-01-02" label:"开始时间" example:"2023-01-01"` // 创建时间范围的开始时间，格式：YYYY-MM-DD
-	EndTime   *time.Time     `form:"end_time" binding:"omitempty" time_format:"2006-01-02" label:"结束时间" example:"2023-12-31"`   // 创建时间范围的结束时间，格式：YYYY-MM-DD
+	pagination.CursorParams
+}
+
+// ExportUsersRequest 导出用户请求DTO，过滤条件与用户列表相同，由 NewUserListSchema 创建的白名单解析
+type ExportUsersRequest struct {
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=csv ndjson" label:"导出格式" example:"csv"` // 导出格式：csv(默认)或ndjson
+}
+
+// NewUserListSchema 创建用户列表允许的过滤和排序字段
+// normalizePhone 把过滤条件中的手机号转换为保存时的E.164格式，任意写法都能查到同一号码
+func NewUserListSchema(normalizePhone func(string) (string, error)) *filter.Schema {
+	return filter.NewSchema("-created_at",
+		filter.Field{Name: "name", Type: filter.TypeString, Operators: []filter.Operator{filter.OpEq, filter.OpLike}, Sortable: true},
+		filter.Field{Name: "phone_number", Type: filter.TypeString, Operators: []filter.Operator{filter.OpEq}, Normalize: normalizePhone},
+		filter.Field{
+			Name:      "gender",
+			Type:      filter.TypeInt,
+			Operators: []filter.Operator{filter.OpEq, filter.OpIn},
+			Enum:      []string{"100", "200", "300"},
+		},
+		filter.Field{
+			Name:      "status",
+			Type:      filter.TypeString,
+			Operators: []filter.Operator{filter.OpEq, filter.OpNe, filter.OpIn},
+			Enum: []string{
+				uservo.UserStatusPending.String(),
+				uservo.UserStatusActive.String(),
+				uservo.UserStatusDisabled.String(),
+				uservo.UserStatusLocked.String(),
+			},
+		},
+		filter.Field{
+			Name:      "created_at",
+			Type:      filter.TypeTime,
+			Operators: []filter.Operator{filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte},
+			Sortable:  true,
+		},
+		filter.Field{
+			Name:      "updated_at",
+			Type:      filter.TypeTime,
+			Operators: []filter.Operator{filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte},
+			Sortable:  true,
+		},
+	)
+}
+
+// ChangePasswordRequest 修改密码请求DTO
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required" label:"原密码" example:"password123"`    // 当前使用的密码
+	NewPassword string `json:"new_password" binding:"required" label:"新密码" example:"newPassword456"` // 新密码，需符合密码策略且不能与最近使用过的密码相同
+}
+
+// ImportUsersRequest 批量导入用户请求DTO
+type ImportUsersRequest struct {
+	File   *multipart.FileHeader `form:"file" binding:"required" label:"导入文件" swaggerignore:"true"` // CSV或XLSX文件，表头为 open_id,name,gender,phone_number,password
+	DryRun bool                  `form:"dry_run" label:"仅校验" example:"false"`                       // 只校验不写入
 }
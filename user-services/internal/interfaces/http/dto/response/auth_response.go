@@ -0,0 +1,85 @@
+package response
+
+import (
+	"time"
+
+	"common/pkg/jwt"
+)
+
+// TokenResponse 令牌响应
+type TokenResponse struct {
+	AccessToken      string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // 访问令牌
+	RefreshToken     string `json:"refresh_token" example:"dGhpcyBpcyBhIHJlZnJlc2ggdG9rZW4"`        // 刷新令牌，仅可使用一次
+	TokenType        string `json:"token_type" example:"Bearer"`                                    // 令牌类型
+	ExpiresIn        int64  `json:"expires_in" example:"1800"`                                      // 访问令牌有效期（秒）
+	RefreshExpiresIn int64  `json:"refresh_expires_in" example:"604800"`                            // 刷新令牌有效期（秒）
+	SessionID        string `json:"session_id" example:"5b0c8f5e-3f4a-4c43-9a4e-2f1d8c7b6a90"`      // 登录会话ID
+}
+
+// ToTokenResponse 将令牌对转换为令牌响应
+func ToTokenResponse(pair *jwt.TokenPair) *TokenResponse {
+	if pair == nil {
+		return nil
+	}
+
+	return &TokenResponse{
+		AccessToken:      pair.AccessToken,
+		RefreshToken:     pair.RefreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        pair.ExpiresIn,
+		RefreshExpiresIn: pair.RefreshExpiresIn,
+		SessionID:        pair.SessionID,
+	}
+}
+
+// MFAChallengeResponse 需要两步验证时的登录响应
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required" example:"true"`                      // 是否需要两步验证
+	MFAToken    string `json:"mfa_token" example:"c2hvcnQtbGl2ZWQgbWZhIHRva2Vu"` // 两步验证令牌，用于 /auth/login/mfa
+	ExpiresIn   int64  `json:"expires_in" example:"300"`                         // 两步验证令牌有效期（秒）
+}
+
+// TOTPSetupResponse 绑定身份验证器响应
+type TOTPSetupResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`                                                 // base32密钥，无法扫码时手动输入
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/app:13800138000?secret=JBSWY3DPEHPK3PXP"` // 渲染为二维码供身份验证器扫描
+}
+
+// RecoveryCodesResponse 两步验证恢复码响应
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"ABCDE-FGHJK"` // 恢复码，每个只能使用一次，仅展示一次
+}
+
+// SendSMSCodeResponse 发送短信验证码响应
+type SendSMSCodeResponse struct {
+	ExpiresIn   int64 `json:"expires_in" example:"300"`  // 验证码有效期（秒）
+	ResendAfter int64 `json:"resend_after" example:"60"` // 可再次发送的等待时间（秒）
+}
+
+// SessionResponse 登录会话响应
+type SessionResponse struct {
+	ID         string    `json:"id" example:"5b0c8f5e-3f4a-4c43-9a4e-2f1d8c7b6a90"` // 会话ID
+	Device     string    `json:"device" example:"iPhone 15"`                        // 设备名称
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0"`                  // 登录时的User-Agent
+	ClientIP   string    `json:"client_ip" example:"192.168.1.10"`                  // 最近使用的客户端IP
+	CreatedAt  time.Time `json:"created_at"`                                        // 登录时间
+	LastSeenAt time.Time `json:"last_seen_at"`                                      // 最近活跃时间
+	Current    bool      `json:"current"`                                           // 是否为当前请求所属的会话
+}
+
+// ToSessionResponses 将会话列表转换为响应，currentSessionID 用于标记当前会话
+func ToSessionResponses(sessions []*jwt.Session, currentSessionID string) []*SessionResponse {
+	result := make([]*SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &SessionResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			UserAgent:  session.UserAgent,
+			ClientIP:   session.ClientIP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return result
+}
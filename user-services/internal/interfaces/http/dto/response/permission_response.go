@@ -0,0 +1,107 @@
+package response
+
+import (
+	"user-services/internal/application/service"
+	"user-services/internal/domain/audit/entity"
+)
+
+// PolicyResponse 权限策略响应
+type PolicyResponse struct {
+	Subject string `json:"sub" example:"admin"`           // 用户ID或角色
+	Domain  string `json:"dom" example:"default"`         // 所属租户，"*" 表示全部租户
+	Object  string `json:"obj" example:"/api/v1/admin/*"` // 资源路径
+	Action  string `json:"act" example:"*"`               // HTTP方法
+}
+
+// RoleResponse 角色响应
+type RoleResponse struct {
+	Name  string   `json:"name" example:"admin"` // 角色名称
+	Users []string `json:"users"`                // 直接拥有该角色的用户或子角色
+}
+
+// UserPermissionsResponse 用户有效权限响应
+type UserPermissionsResponse struct {
+	UserID        string            `json:"user_id" example:"user_123456789"` // 用户ID
+	Domain        string            `json:"dom" example:"default"`            // 所属租户
+	Roles         []string          `json:"roles"`                            // 直接分配的角色
+	ImplicitRoles []string          `json:"implicit_roles"`                   // 包含继承关系在内的全部角色
+	Permissions   []*PolicyResponse `json:"permissions"`                      // 有效的权限策略
+}
+
+// AuditLogResponse 审计日志响应
+type AuditLogResponse struct {
+	ID        string         `json:"id" example:"2b1c6f0e-6f1d-4c8a-9d3e-3f1f7c2a9b10"` // 审计日志ID
+	ActorID   string         `json:"actor_id" example:"user_123456789"`                 // 操作者
+	Domain    string         `json:"dom" example:"default"`                             // 所属租户
+	Action    string         `json:"action" example:"role.assign"`                      // 操作类型
+	Resource  string         `json:"resource" example:"user_987654321"`                 // 操作对象
+	Detail    map[string]any `json:"detail"`                                            // 操作详情
+	ClientIP  string         `json:"client_ip" example:"127.0.0.1"`                     // 客户端IP
+	CreatedAt int64          `json:"created_at" example:"1640995200000"`                // 操作时间戳（毫秒）
+}
+
+// ToPolicyListResponse 将权限策略列表转换为响应
+func ToPolicyListResponse(policies []service.Policy) []*PolicyResponse {
+	responses := make([]*PolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		responses = append(responses, &PolicyResponse{
+			Subject: policy.Subject,
+			Domain:  policy.Domain,
+			Object:  policy.Object,
+			Action:  policy.Action,
+		})
+	}
+	return responses
+}
+
+// ToRoleListResponse 将角色列表转换为响应
+func ToRoleListResponse(roles []service.Role) []*RoleResponse {
+	responses := make([]*RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		users := role.Users
+		if users == nil {
+			users = []string{}
+		}
+		responses = append(responses, &RoleResponse{Name: role.Name, Users: users})
+	}
+	return responses
+}
+
+// ToUserPermissionsResponse 将用户有效权限转换为响应
+func ToUserPermissionsResponse(permissions *service.UserPermissions) *UserPermissionsResponse {
+	if permissions == nil {
+		return nil
+	}
+	return &UserPermissionsResponse{
+		UserID:        permissions.UserID,
+		Domain:        permissions.Domain,
+		Roles:         nonNilStrings(permissions.Roles),
+		ImplicitRoles: nonNilStrings(permissions.ImplicitRoles),
+		Permissions:   ToPolicyListResponse(permissions.Permissions),
+	}
+}
+
+// ToAuditLogListResponse 将审计日志列表转换为响应
+func ToAuditLogListResponse(logs []*entity.AuditLog) []*AuditLogResponse {
+	responses := make([]*AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		responses = append(responses, &AuditLogResponse{
+			ID:        log.ID(),
+			ActorID:   log.ActorID(),
+			Domain:    log.Domain(),
+			Action:    log.Action(),
+			Resource:  log.Resource(),
+			Detail:    log.Detail(),
+			ClientIP:  log.ClientIP(),
+			CreatedAt: log.CreatedAt().UnixMilli(),
+		})
+	}
+	return responses
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -1,18 +1,23 @@
 package response
 
 import (
+	"strconv"
+
+	"common/pkg/mask"
+	"user-services/internal/application/service"
 	"user-services/internal/domain/user/entity"
 )
 
 // UserInfoResponse 用户信息响应
 type UserInfoResponse struct {
-	ID          string `json:"id" example:"user_123456789"`        // 用户唯一标识ID
-	OpenID      string `json:"open_id" example:"wx_123456789"`     // 第三方平台的唯一标识
-	Name        string `json:"name" example:"张三"`                  // 用户姓名
-	Gender      int    `json:"gender" example:"200"`               // 性别：100-男性，200-女性，300-其他
-	PhoneNumber string `json:"phone_number" example:"13800138000"` // 手机号码
-	CreatedAt   int64  `json:"created_at" example:"1640995200000"` // 创建时间戳（毫秒）
-	UpdatedAt   int64  `json:"updated_at" example:"1640995200000"` // 更新时间戳（毫秒）
+	ID          string `json:"id" example:"user_123456789"`           // 用户唯一标识ID
+	OpenID      string `json:"open_id" example:"wx_123456789"`        // 第三方平台的唯一标识
+	Name        string `json:"name" example:"张三"`                     // 用户姓名
+	Gender      int    `json:"gender" example:"200"`                  // 性别：100-男性，200-女性，300-其他
+	PhoneNumber string `json:"phone_number" example:"+8613800138000"` // 手机号码(E.164格式)
+	Status      string `json:"status" example:"active"`               // 账号状态：pending-待激活，active-正常，disabled-已停用，locked-已锁定
+	CreatedAt   int64  `json:"created_at" example:"1640995200000"`    // 创建时间戳（毫秒）
+	UpdatedAt   int64  `json:"updated_at" example:"1640995200000"`    // 更新时间戳（毫秒）
 }
 
 // UserListResponse 用户列表响应
@@ -36,6 +41,7 @@ func ToUserInfoResponse(user *entity.User) *UserInfoResponse {
 		Name:        user.Name(),
 		Gender:      user.Gender(),
 		PhoneNumber: user.PhoneNumber(),
+		Status:      user.Status().String(),
 		CreatedAt:   user.GetCreatedAt(),
 		UpdatedAt:   user.GetUpdatedAt(),
 	}
@@ -54,3 +60,69 @@ func ToUserListResponse(users []*entity.User) []*UserInfoResponse {
 
 	return userResponses
 }
+
+// UserExportColumns 导出CSV的表头，与 UserInfoResponse 的JSON字段一致
+var UserExportColumns = []string{"id", "open_id", "name", "gender", "phone_number", "status", "created_at", "updated_at"}
+
+// ToUserExportResponse 将用户实体转换为导出的一行，revealSensitive 为 false 时open_id和手机号脱敏
+func ToUserExportResponse(user *entity.User, revealSensitive bool) *UserInfoResponse {
+	response := ToUserInfoResponse(user)
+	if response != nil && !revealSensitive {
+		response.OpenID = mask.Middle(response.OpenID, 4, 4)
+		response.PhoneNumber = mask.Phone(response.PhoneNumber)
+	}
+	return response
+}
+
+// CSVRecord 按 UserExportColumns 的顺序返回CSV的一行
+func (r *UserInfoResponse) CSVRecord() []string {
+	return []string{
+		r.ID,
+		r.OpenID,
+		r.Name,
+		strconv.Itoa(r.Gender),
+		r.PhoneNumber,
+		r.Status,
+		strconv.FormatInt(r.CreatedAt, 10),
+		strconv.FormatInt(r.UpdatedAt, 10),
+	}
+}
+
+// UserImportJobResponse 批量导入任务响应
+type UserImportJobResponse struct {
+	ID         string `json:"id" example:"5b0c8f5e-3f4a-4c43-9a4e-2f1d8c7b6a90"` // 任务ID
+	Status     string `json:"status" example:"running"`                          // 任务状态：pending-排队中，running-执行中，completed-已完成，failed-已中断
+	FileName   string `json:"file_name" example:"users.csv"`                     // 上传的文件名
+	DryRun     bool   `json:"dry_run" example:"false"`                           // 是否只校验不写入
+	Total      int    `json:"total" example:"1000"`                              // 数据行数
+	Processed  int    `json:"processed" example:"500"`                           // 已处理行数
+	Created    int    `json:"created" example:"498"`                             // 已创建的用户数，dry-run时为校验通过的行数
+	Failed     int    `json:"failed" example:"2"`                                // 失败行数，原因见错误报告
+	Error      string `json:"error,omitempty" example:""`                        // 任务中断的原因
+	CreatedAt  int64  `json:"created_at" example:"1640995200000"`                // 创建时间戳（毫秒）
+	FinishedAt int64  `json:"finished_at,omitempty" example:"1640995260000"`     // 结束时间戳（毫秒），未结束时为空
+}
+
+// ToUserImportJobResponse 将导入任务转换为响应
+func ToUserImportJobResponse(job *service.UserImportJob) *UserImportJobResponse {
+	if job == nil {
+		return nil
+	}
+
+	resp := &UserImportJobResponse{
+		ID:        job.ID,
+		Status:    string(job.Status),
+		FileName:  job.FileName,
+		DryRun:    job.DryRun,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Created:   job.Created,
+		Failed:    job.Failed,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt.UnixMilli(),
+	}
+	if job.FinishedAt != nil {
+		resp.FinishedAt = job.FinishedAt.UnixMilli()
+	}
+	return resp
+}
@@ -4,92 +4,316 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"common/config"
 	"common/logger"
+	commonMiddleware "common/middleware"
+	"common/pkg/contextutil"
 	"common/pkg/jwt"
+	"common/pkg/netutil"
 	"common/pkg/validation"
 	"common/response"
 	"user-services/internal/application/service"
 	requestdto "user-services/internal/interfaces/http/dto/request"
+	responsedto "user-services/internal/interfaces/http/dto/response"
 )
 
+// DeviceNameHeader 客户端上报设备名称的请求头，登录时记录到会话中
+const DeviceNameHeader = "X-Device-Name"
+
 // AuthHandler 认证HTTP处理器
 type AuthHandler struct {
-	authService service.AuthServiceInterface
-	validator   *validation.Validator
-	jwtService  *jwt.JWT
+	authService   service.AuthServiceInterface
+	codeService   service.VerificationCodeServiceInterface
+	validator     *validation.Validator
+	resolveTenant commonMiddleware.DomainResolverFunc
 }
 
 // NewAuthHandler 创建认证HTTP处理器
+// 登录的租户与Casbin中间件使用相同的请求头和默认租户
 func NewAuthHandler(
 	authService service.AuthServiceInterface,
+	codeService service.VerificationCodeServiceInterface,
 	validator *validation.Validator,
-	jwtService *jwt.JWT,
+	cfg *config.Config,
 ) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		validator:   validator,
-		jwtService:  jwtService,
+		authService:   authService,
+		codeService:   codeService,
+		validator:     validator,
+		resolveTenant: commonMiddleware.TenantResolver(cfg.Tenant),
 	}
 }
 
 // LoginByPassword 用户登录
 // @Summary 用户密码登录
-// @Description 使用手机号和密码进行用户登录，成功后返回JWT Token
+// @Description 使用手机号和密码进行用户登录，成功后返回访问令牌和刷新令牌；已启用两步验证的用户返回 mfa_required 和两步验证令牌，需调用 /auth/login/mfa 完成登录；连续失败过多时需等待或被临时锁定，响应头 Retry-After 给出可重试的秒数
 // @Tags 认证授权
 // @Accept json
 // @Produce json
 // @Param request body requestdto.LoginRequest true "登录请求"
-// @Success 200 {object} response.Response{data=map[string]string} "登录成功，返回token"
+// @Param X-Tenant-ID header string false "登录的租户，签发的令牌绑定该租户，之后的请求不能通过请求头切换；默认为配置的默认租户"
+// @Success 200 {object} response.Response{data=responsedto.TokenResponse} "登录成功，返回令牌；需要两步验证时返回 responsedto.MFAChallengeResponse"
 // @Failure 400 {object} response.Response "请求参数验证失败"
 // @Failure 401 {object} response.Response "用户名或密码错误"
+// @Failure 429 {object} response.Response "登录失败次数过多(业务码5002需等待，2003账号已被临时锁定)"
 // @Failure 500 {object} response.Response "服务器内部错误"
-// @Router /auth/login [post]
+// @Router /auth/login/password [post]
 func (h *AuthHandler) LoginByPassword(c *gin.Context) {
 	ctx := c.Request.Context()
 	var req requestdto.LoginRequest
 	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
 		return
 	}
+	meta, err := h.sessionMeta(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
 
 	// 验证用户密码
-	userID, userName, err := h.authService.LoginByPassword(ctx, req.PhoneNumber, req.Password)
+	result, err := h.authService.LoginByPassword(ctx, req.PhoneNumber, req.Password, netutil.ClientIPFromContext(c))
 	if err != nil {
 		logger.Error(ctx, "Login failed", zap.Error(err))
 		HandleError(c, err) // 使用语义化的 HandleError
 		return
 	}
 
-	// 生成 JWT Token
-	token, err := h.jwtService.Generate(userID, userName)
+	h.completeLogin(c, result, meta)
+}
+
+// completeLogin 第一步验证通过后的响应：已启用两步验证时返回两步验证令牌，否则签发访问令牌和刷新令牌
+func (h *AuthHandler) completeLogin(c *gin.Context, result *service.LoginResult, meta jwt.SessionMeta) {
+	ctx := c.Request.Context()
+
+	// 已启用两步验证，返回两步验证令牌
+	if result.MFAChallenge != nil {
+		HandleSuccess(c, &responsedto.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAChallenge.Token,
+			ExpiresIn:   result.MFAChallenge.ExpiresIn,
+		})
+		return
+	}
+
+	// 签发访问令牌和刷新令牌
+	pair, err := h.authService.IssueTokenPair(ctx, result.UserID, result.Username, meta)
 	if err != nil {
-		logger.Error(ctx, "Failed to generate token", zap.Error(err))
-		HandleError(c, response.NewInternalServerError("Failed to generate token", err))
+		logger.Error(ctx, "Failed to issue token pair", zap.Error(err))
+		HandleError(c, err)
 		return
 	}
 
 	// 返回成功响应
-	HandleSuccess(c, gin.H{
-		"token": token,
+	HandleSuccess(c, responsedto.ToTokenResponse(pair))
+}
+
+// LoginByMFA 两步验证登录
+// @Summary 完成两步验证登录
+// @Description 使用登录返回的两步验证令牌和身份验证器中的验证码(或恢复码)完成登录，成功后返回访问令牌和刷新令牌
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.MFALoginRequest true "两步验证登录请求"
+// @Param X-Tenant-ID header string false "登录的租户，签发的令牌绑定该租户，之后的请求不能通过请求头切换；默认为配置的默认租户"
+// @Success 200 {object} response.Response{data=responsedto.TokenResponse} "登录成功，返回令牌"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "验证码错误，或两步验证令牌已过期、尝试次数过多"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/login/mfa [post]
+func (h *AuthHandler) LoginByMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.MFALoginRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+	meta, err := h.sessionMeta(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	userID, userName, err := h.authService.LoginByMFA(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		logger.Error(ctx, "MFA login failed", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	pair, err := h.authService.IssueTokenPair(ctx, userID, userName, meta)
+	if err != nil {
+		logger.Error(ctx, "Failed to issue token pair", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToTokenResponse(pair))
+}
+
+// RefreshToken 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效；重复使用已失效的刷新令牌将导致该登录会话的所有令牌被吊销
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.RefreshTokenRequest true "刷新令牌请求"
+// @Success 200 {object} response.Response{data=responsedto.TokenResponse} "刷新成功，返回新的令牌"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "刷新令牌无效、已过期或已被吊销"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.RefreshTokenRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	pair, err := h.authService.RefreshToken(ctx, req.RefreshToken, netutil.ClientIPFromContext(c))
+	if err != nil {
+		logger.Error(ctx, "Refresh token failed", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToTokenResponse(pair))
+}
+
+// SendSMSCode 发送短信验证码
+// @Summary 发送短信登录验证码
+// @Description 向指定手机号发送登录验证码，同一手机号有发送间隔和每日上限，同一IP有每小时上限
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.SendSMSCodeRequest true "发送验证码请求"
+// @Success 200 {object} response.Response{data=responsedto.SendSMSCodeResponse} "发送成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 429 {object} response.Response "发送过于频繁"
+// @Failure 502 {object} response.Response "短信服务不可用"
+// @Router /auth/sms/send [post]
+func (h *AuthHandler) SendSMSCode(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.SendSMSCodeRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	result, err := h.codeService.SendCode(ctx, service.CodePurposeLogin, req.PhoneNumber, netutil.ClientIPFromContext(c))
+	if err != nil {
+		logger.Error(ctx, "Send SMS code failed", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, &responsedto.SendSMSCodeResponse{
+		ExpiresIn:   result.ExpiresIn,
+		ResendAfter: result.ResendAfter,
 	})
 }
 
+// LoginBySMS 短信验证码登录
+// @Summary 短信验证码登录
+// @Description 使用手机号和短信验证码登录，验证码校验成功后立即失效，成功后返回访问令牌和刷新令牌；已启用两步验证的用户返回 mfa_required 和两步验证令牌，需调用 /auth/login/mfa 完成登录
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.SMSLoginRequest true "短信登录请求"
+// @Param X-Tenant-ID header string false "登录的租户，签发的令牌绑定该租户，之后的请求不能通过请求头切换；默认为配置的默认租户"
+// @Success 200 {object} response.Response{data=responsedto.TokenResponse} "登录成功，返回令牌；需要两步验证时返回 responsedto.MFAChallengeResponse"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "验证码错误、已过期或手机号未注册"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/login/sms [post]
+func (h *AuthHandler) LoginBySMS(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.SMSLoginRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+	meta, err := h.sessionMeta(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	result, err := h.authService.LoginBySMS(ctx, req.PhoneNumber, req.Code)
+	if err != nil {
+		logger.Error(ctx, "SMS login failed", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	h.completeLogin(c, result, meta)
+}
+
 // LoginByWeChat 微信登录
 // @Summary 微信登录
-// @Description 使用微信授权码进行用户登录，成功后返回JWT Token
+// @Description 使用小程序 wx.login 返回的授权码登录，首次登录自动注册，成功后返回访问令牌和刷新令牌；已启用两步验证的用户返回 mfa_required 和两步验证令牌，需调用 /auth/login/mfa 完成登录
 // @Tags 认证授权
 // @Accept json
 // @Produce json
 // @Param request body requestdto.WeChatLoginRequest true "微信登录请求"
-// @Success 200 {object} response.Response{data=map[string]string} "登录成功，返回token"
-// @Failure 400 {object} response.Response "请求参数验证失败"
-// @Failure 401 {object} response.Response "微信授权失败"
+// @Param X-Tenant-ID header string false "登录的租户，签发的令牌绑定该租户，之后的请求不能通过请求头切换；默认为配置的默认租户"
+// @Success 200 {object} response.Response{data=responsedto.TokenResponse} "登录成功，返回令牌；需要两步验证时返回 responsedto.MFAChallengeResponse"
+// @Failure 400 {object} response.Response "请求参数验证失败或授权码无效"
 // @Failure 500 {object} response.Response "服务器内部错误"
-// @Router /auth/wechat [post]
+// @Failure 502 {object} response.Response "微信服务不可用"
+// @Router /auth/login/wechat [post]
 func (h *AuthHandler) LoginByWeChat(c *gin.Context) {
-	// 1. Get code from request
-	// 2. Call authService.LoginByWeChat
-	// 3. Generate JWT and return
-	response.Handle(c, gin.H{"message": "WeChat login placeholder"}, nil)
+	ctx := c.Request.Context()
+	var req requestdto.WeChatLoginRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+	meta, err := h.sessionMeta(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	result, err := h.authService.LoginByWeChat(ctx, req.Code)
+	if err != nil {
+		logger.Error(ctx, "WeChat login failed", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	h.completeLogin(c, result, meta)
+}
+
+// BindWeChatPhone 绑定微信手机号
+// @Summary 绑定微信手机号
+// @Description 使用小程序手机号快速验证组件返回的code，为当前登录用户绑定手机号
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.WeChatBindPhoneRequest true "绑定手机号请求"
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "绑定成功，返回用户信息"
+// @Failure 400 {object} response.Response "请求参数验证失败、授权码无效或手机号已被使用"
+// @Failure 401 {object} response.Response "未授权或Token无效"
+// @Failure 502 {object} response.Response "微信服务不可用"
+// @Security BearerAuth
+// @Router /auth/wechat/bind-phone [post]
+func (h *AuthHandler) BindWeChatPhone(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.WeChatBindPhoneRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	userID, ok := contextutil.GetUserIDFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	user, err := h.authService.BindWeChatPhone(ctx, userID, req.Code)
+	if err != nil {
+		logger.Error(ctx, "Bind WeChat phone failed", zap.Error(err), zap.String("user_id", userID))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToUserInfoResponse(user))
 }
 
 // Logout 登出
@@ -106,21 +330,15 @@ func (h *AuthHandler) LoginByWeChat(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// 获取用户Claims
-	claimsValue, exists := c.Get("claims")
-	if !exists {
-		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
-		return
-	}
-
-	claims, ok := claimsValue.(*jwt.CustomClaims)
+	// 获取认证中间件写入的用户Claims
+	claims, ok := jwt.ClaimsFromContext(ctx)
 	if !ok {
-		HandleError(c, response.NewInternalServerError("用户信息类型断言失败"))
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
 		return
 	}
 
 	// 执行登出操作
-	err := h.authService.Logout(ctx, claims.ID, claims.ExpiresAt.Time)
+	err := h.authService.Logout(ctx, claims)
 	if err != nil {
 		logger.Error(ctx, "Logout failed", zap.Error(err))
 		HandleError(c, err)
@@ -130,3 +348,77 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	// 返回成功响应
 	HandleSuccess(c, "登出成功")
 }
+
+// ListSessions 获取当前用户的登录会话
+// @Summary 获取登录会话列表
+// @Description 列出当前用户所有有效的登录会话(设备)，current 标记当前请求所属的会话
+// @Tags 认证授权
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]responsedto.SessionResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		logger.Error(ctx, "Failed to list sessions", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToSessionResponses(sessions, claims.SessionID))
+}
+
+// RevokeSession 吊销指定的登录会话
+// @Summary 吊销登录会话
+// @Description 吊销当前用户的指定会话，该会话的访问令牌和刷新令牌立即失效，可用于下线其他设备
+// @Tags 认证授权
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "会话ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "会话不存在或已失效"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	if err := h.authService.RevokeSession(ctx, claims.UserID, c.Param("id")); err != nil {
+		logger.Error(ctx, "Failed to revoke session", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, "会话已吊销")
+}
+
+// sessionMeta 采集登录会话的设备信息和登录的租户
+// 租户取自租户请求头，未携带时为默认租户；会话内签发的访问令牌均绑定该租户
+func (h *AuthHandler) sessionMeta(c *gin.Context) (jwt.SessionMeta, error) {
+	tenantID, err := h.resolveTenant(c)
+	if err != nil {
+		return jwt.SessionMeta{}, err
+	}
+	return jwt.SessionMeta{
+		Device:    c.GetHeader(DeviceNameHeader),
+		UserAgent: c.Request.UserAgent(),
+		ClientIP:  netutil.ClientIPFromContext(c),
+		TenantID:  tenantID,
+	}, nil
+}
@@ -1,12 +1,13 @@
 package handler
 
 import (
-	"common/logger"
-	"common/response"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"common/logger"
+	"common/response"
 )
 
 // HandleWithLogging 使用新的统一API处理响应，并记录DomainError的上下文信息
@@ -35,6 +36,17 @@ func HandlePagingWithLogging(c *gin.Context, data any, page, pageSize int, total
 	response.HandlePaging(c, data, page, pageSize, total, err)
 }
 
+// HandleCursorPagingWithLogging 使用统一的游标分页API处理响应，并记录DomainError的上下文信息
+func HandleCursorPagingWithLogging(c *gin.Context, data any, pagination *response.CursorPagination, err error) {
+	if err != nil {
+		if domainErr, ok := err.(*response.DomainError); ok {
+			logDomainError(c, domainErr)
+		}
+	}
+
+	response.HandleCursorPaging(c, data, pagination, err)
+}
+
 // logDomainError 记录领域错误的详细信息
 func logDomainError(c *gin.Context, domainErr *response.DomainError) {
 	// 构建日志字段
@@ -11,26 +11,30 @@ import (
 	"common/config"
 	"common/databases/redis"
 	"common/logger"
+	pkgcasbin "common/pkg/casbin"
 	"user-services/internal/infrastructure/persistence"
 )
 
 // HealthHandler 健康检查处理器
 type HealthHandler struct {
-	dbProvider  *persistence.DatabaseProvider
-	redisClient *redis.RedisClient
-	config      *config.Config
+	dbProvider    *persistence.DatabaseProvider
+	redisClient   *redis.RedisClient
+	policyWatcher *pkgcasbin.PolicyWatcher
+	config        *config.Config
 }
 
 // NewHealthHandler 创建健康检查处理器
 func NewHealthHandler(
 	dbProvider *persistence.DatabaseProvider,
 	redisClient *redis.RedisClient,
+	policyWatcher *pkgcasbin.PolicyWatcher,
 	config *config.Config,
 ) *HealthHandler {
 	return &HealthHandler{
-		dbProvider:  dbProvider,
-		redisClient: redisClient,
-		config:      config,
+		dbProvider:    dbProvider,
+		redisClient:   redisClient,
+		policyWatcher: policyWatcher,
+		config:        config,
 	}
 }
 
@@ -40,11 +44,19 @@ type HealthResponse struct {
 	Timestamp time.Time         `json:"timestamp" example:"2023-01-01T12:00:00Z" description:"检查时间戳"`
 	Version   string            `json:"version" example:"1.0.0" description:"应用版本号"`
 	Services  map[string]string `json:"services" description:"各个服务组件的健康状态"`
+	Policy    PolicySyncStatus  `json:"policy" description:"本实例的权限策略同步状态"`
+}
+
+// PolicySyncStatus 权限策略同步状态
+type PolicySyncStatus struct {
+	SyncEnabled bool      `json:"sync_enabled" example:"true" description:"是否通过Redis与其他实例同步策略变更"`
+	InstanceID  string    `json:"instance_id" example:"user-services-7d9f-1a2b3c4d" description:"实例标识"`
+	LastSyncAt  time.Time `json:"last_sync_at" example:"2023-01-01T12:00:00Z" description:"最近一次加载策略或应用其他实例变更的时间"`
 }
 
 // Health 健康检查
 // @Summary 系统健康检查
-// @Description 检查系统各个组件的健康状态，包括数据库、Redis等服务的连接状态
+// @Description 检查系统各个组件的健康状态，包括数据库、Redis等服务的连接状态，以及权限策略最近的同步时间
 // @Tags 健康检查
 // @Accept json
 // @Produce json
@@ -59,6 +71,7 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
 		Services:  make(map[string]string),
+		Policy:    h.policySyncStatus(),
 	}
 
 	// 检查数据库连接
@@ -87,6 +100,16 @@ func (h *HealthHandler) Health(c *gin.Context) {
 	c.JSON(statusCode, responseData)
 }
 
+// policySyncStatus 读取策略同步状态，同步异常不影响整体健康状态
+func (h *HealthHandler) policySyncStatus() PolicySyncStatus {
+	status := h.policyWatcher.Status()
+	return PolicySyncStatus{
+		SyncEnabled: status.Enabled,
+		InstanceID:  status.InstanceID,
+		LastSyncAt:  status.LastSyncAt,
+	}
+}
+
 // checkDatabase 检查数据库连接
 func (h *HealthHandler) checkDatabase(ctx context.Context) error {
 	client, err := h.dbProvider.GetHealthCheckClient()
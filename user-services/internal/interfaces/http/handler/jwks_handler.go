@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"common/pkg/jwt"
+)
+
+// JWKSHandler 公钥集合处理器
+type JWKSHandler struct {
+	jwtService *jwt.JWT
+}
+
+// NewJWKSHandler 创建公钥集合处理器
+func NewJWKSHandler(jwtService *jwt.JWT) *JWKSHandler {
+	return &JWKSHandler{
+		jwtService: jwtService,
+	}
+}
+
+// JWKS 获取JWT验签公钥
+// @Summary 获取JWT验签公钥集合
+// @Description 返回标准JWKS格式(RFC 7517)的公钥集合，供其他服务根据令牌头部的kid验证令牌，响应不使用统一响应结构
+// @Tags 认证授权
+// @Produce json
+// @Success 200 {object} jwt.JSONWebKeySet "公钥集合"
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	// 密钥轮换后下游服务需要在合理时间内拿到新公钥，缓存时间不宜过长
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/pkg/jwt"
+	"common/pkg/validation"
+	"common/response"
+	"user-services/internal/application/service"
+	requestdto "user-services/internal/interfaces/http/dto/request"
+	responsedto "user-services/internal/interfaces/http/dto/response"
+)
+
+// MFAHandler 两步验证HTTP处理器
+type MFAHandler struct {
+	mfaService service.MFAServiceInterface
+	validator  *validation.Validator
+}
+
+// NewMFAHandler 创建两步验证HTTP处理器
+func NewMFAHandler(
+	mfaService service.MFAServiceInterface,
+	validator *validation.Validator,
+) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+		validator:  validator,
+	}
+}
+
+// SetupTOTP 生成身份验证器密钥
+// @Summary 绑定身份验证器
+// @Description 为当前用户生成新的TOTP密钥和 otpauth:// 链接，客户端渲染为二维码供身份验证器扫描；需调用确认接口后才会启用两步验证
+// @Tags 两步验证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=responsedto.TOTPSetupResponse} "生成成功"
+// @Failure 400 {object} response.Response "已启用两步验证"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/mfa/totp/setup [post]
+func (h *MFAHandler) SetupTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	setup, err := h.mfaService.SetupTOTP(ctx, claims.UserID)
+	if err != nil {
+		logger.Error(ctx, "Failed to setup TOTP", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, &responsedto.TOTPSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// ConfirmTOTP 确认并启用两步验证
+// @Summary 启用两步验证
+// @Description 提交身份验证器中的验证码确认绑定，成功后启用两步验证并返回一次性恢复码，恢复码仅展示一次
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body requestdto.TOTPCodeRequest true "验证码"
+// @Success 200 {object} response.Response{data=responsedto.RecoveryCodesResponse} "启用成功，返回恢复码"
+// @Failure 400 {object} response.Response "请求参数验证失败、验证码错误、尚未绑定身份验证器或已启用两步验证"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.TOTPCodeRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmTOTP(ctx, claims.UserID, req.Code)
+	if err != nil {
+		logger.Error(ctx, "Failed to confirm TOTP", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	logger.Info(ctx, "TOTP enabled", zap.String("user_id", claims.UserID))
+	HandleSuccess(c, &responsedto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 提交身份验证器中的验证码或恢复码关闭两步验证，同时清除密钥和剩余恢复码
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body requestdto.TOTPCodeRequest true "验证码或恢复码"
+// @Success 200 {object} response.Response "关闭成功"
+// @Failure 400 {object} response.Response "请求参数验证失败、验证码错误或未启用两步验证"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/mfa/totp/disable [post]
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.TOTPCodeRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	if err := h.mfaService.DisableTOTP(ctx, claims.UserID, req.Code); err != nil {
+		logger.Error(ctx, "Failed to disable TOTP", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	logger.Info(ctx, "TOTP disabled", zap.String("user_id", claims.UserID))
+	HandleSuccess(c, "两步验证已关闭")
+}
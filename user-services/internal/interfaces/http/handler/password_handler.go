@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/pkg/jwt"
+	"common/pkg/validation"
+	"common/response"
+	"user-services/internal/application/service"
+	requestdto "user-services/internal/interfaces/http/dto/request"
+)
+
+// PasswordHandler 密码管理HTTP处理器
+type PasswordHandler struct {
+	passwordService service.PasswordServiceInterface
+	validator       *validation.Validator
+}
+
+// NewPasswordHandler 创建密码管理HTTP处理器
+func NewPasswordHandler(
+	passwordService service.PasswordServiceInterface,
+	validator *validation.Validator,
+) *PasswordHandler {
+	return &PasswordHandler{
+		passwordService: passwordService,
+		validator:       validator,
+	}
+}
+
+// ChangePassword 修改当前用户密码
+// @Summary 修改密码
+// @Description 校验原密码后设置新密码，新密码需符合密码策略且不能与最近使用过的密码相同；修改成功后该用户的全部令牌失效，需要重新登录
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body requestdto.ChangePasswordRequest true "修改密码请求"
+// @Success 200 {object} response.Response "修改成功"
+// @Failure 400 {object} response.Response "请求参数验证失败、原密码错误或新密码不符合策略"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /users/me/password [put]
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.ChangePasswordRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+
+	if err := h.passwordService.ChangePassword(ctx, claims.UserID, req.OldPassword, req.NewPassword); err != nil {
+		logger.Error(ctx, "Failed to change password", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, "密码已修改，请重新登录")
+}
+
+// RequestPasswordReset 申请重置密码
+// @Summary 申请重置密码
+// @Description 向手机号发送重置令牌(或重置链接)，手机号未注册时同样返回成功；同一手机号有申请间隔限制
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.PasswordResetRequest true "申请重置密码请求"
+// @Success 200 {object} response.Response "申请成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 429 {object} response.Response "申请过于频繁"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/password/reset [post]
+func (h *PasswordHandler) RequestPasswordReset(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.PasswordResetRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	if err := h.passwordService.RequestReset(ctx, req.PhoneNumber); err != nil {
+		logger.Error(ctx, "Failed to request password reset", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, "如果该手机号已注册，将收到重置密码短信")
+}
+
+// ConfirmPasswordReset 确认重置密码
+// @Summary 确认重置密码
+// @Description 使用重置令牌设置新密码，新密码需符合密码策略且不能与最近使用过的密码相同；重置成功后该用户的全部令牌失效
+// @Tags 认证授权
+// @Accept json
+// @Produce json
+// @Param request body requestdto.PasswordResetConfirmRequest true "确认重置密码请求"
+// @Success 200 {object} response.Response "重置成功"
+// @Failure 400 {object} response.Response "请求参数验证失败、重置令牌无效或新密码不符合策略"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /auth/password/reset/confirm [post]
+func (h *PasswordHandler) ConfirmPasswordReset(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.PasswordResetConfirmRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	if err := h.passwordService.ConfirmReset(ctx, req.Token, req.NewPassword); err != nil {
+		logger.Error(ctx, "Failed to confirm password reset", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, "密码已重置，请使用新密码登录")
+}
@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/pkg/contextutil"
+	"common/pkg/netutil"
+	"common/pkg/validation"
+	"common/response"
+	"user-services/internal/application/service"
+	auditrepo "user-services/internal/domain/audit/repository"
+	requestdto "user-services/internal/interfaces/http/dto/request"
+	responsedto "user-services/internal/interfaces/http/dto/response"
+)
+
+// PermissionHandler 角色与权限策略管理HTTP处理器
+type PermissionHandler struct {
+	permissionService service.PermissionServiceInterface
+	auditService      service.AuditServiceInterface
+	validator         *validation.Validator
+}
+
+// NewPermissionHandler 创建角色与权限策略管理HTTP处理器
+func NewPermissionHandler(
+	permissionService service.PermissionServiceInterface,
+	auditService service.AuditServiceInterface,
+	validator *validation.Validator,
+) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+		auditService:      auditService,
+		validator:         validator,
+	}
+}
+
+// ListPolicies 查询权限策略
+// @Summary 查询权限策略
+// @Description 查询当前租户的权限策略，可按主体、资源、操作精确过滤，为空表示不过滤
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param request query requestdto.PolicyQueryRequest false "过滤条件"
+// @Success 200 {object} response.Response{data=[]responsedto.PolicyResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/policies [get]
+func (h *PermissionHandler) ListPolicies(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.PolicyQueryRequest
+	if !h.validator.Verify(c, &req, validation.QueryBindAdapter) {
+		return
+	}
+
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	policies, err := h.permissionService.ListPolicies(ctx, domain, req.Subject, req.Object, req.Action)
+	if err != nil {
+		logger.Error(ctx, "Failed to list policies", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToPolicyListResponse(policies))
+}
+
+// AddPolicy 添加权限策略
+// @Summary 添加权限策略
+// @Description 在当前租户添加一条 p 策略，资源支持 * 通配，操作为 "*" 时匹配全部HTTP方法；变更会写入审计日志
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param request body requestdto.PolicyRequest true "权限策略"
+// @Success 200 {object} response.Response{data=responsedto.PolicyResponse} "添加成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 409 {object} response.Response "策略已存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/policies [post]
+func (h *PermissionHandler) AddPolicy(c *gin.Context) {
+	var req requestdto.PolicyRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := auditContext(c)
+	added, err := h.permissionService.AddPolicy(ctx, req.Subject, domain, req.Object, req.Action)
+	if err != nil {
+		logger.Error(ctx, "Failed to add policy", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+	if !added {
+		HandleError(c, response.NewAlreadyExistsError("权限策略已存在"))
+		return
+	}
+
+	HandleSuccess(c, &responsedto.PolicyResponse{Subject: req.Subject, Domain: domain, Object: req.Object, Action: req.Action})
+}
+
+// RemovePolicy 删除权限策略
+// @Summary 删除权限策略
+// @Description 删除当前租户的一条 p 策略，主体、资源、操作均需指定；变更会写入审计日志
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param request query requestdto.PolicyQueryRequest true "权限策略"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "策略不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/policies [delete]
+func (h *PermissionHandler) RemovePolicy(c *gin.Context) {
+	var req requestdto.PolicyQueryRequest
+	if !h.validator.Verify(c, &req, validation.QueryBindAdapter) {
+		return
+	}
+
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := auditContext(c)
+	removed, err := h.permissionService.RemovePolicy(ctx, req.Subject, domain, req.Object, req.Action)
+	if err != nil {
+		logger.Error(ctx, "Failed to remove policy", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+	if !removed {
+		HandleError(c, response.NewNotFoundError("权限策略不存在"))
+		return
+	}
+
+	HandleSuccess(c, "权限策略已删除")
+}
+
+// ListRoles 查询角色
+// @Summary 查询角色
+// @Description 列出当前租户内的角色及直接拥有该角色的用户或子角色
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Success 200 {object} response.Response{data=[]responsedto.RoleResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/roles [get]
+func (h *PermissionHandler) ListRoles(c *gin.Context) {
+	ctx := c.Request.Context()
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.permissionService.ListRoles(ctx, domain)
+	if err != nil {
+		logger.Error(ctx, "Failed to list roles", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToRoleListResponse(roles))
+}
+
+// AssignRole 为用户分配角色
+// @Summary 分配角色
+// @Description 在当前租户内为用户添加角色(g 规则)，变更会写入审计日志
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param id path string true "用户ID"
+// @Param request body requestdto.AssignRoleRequest true "角色"
+// @Success 200 {object} response.Response "分配成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 409 {object} response.Response "用户已拥有该角色"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/users/{id}/roles [post]
+func (h *PermissionHandler) AssignRole(c *gin.Context) {
+	var req requestdto.AssignRoleRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := auditContext(c)
+	userID := c.Param("id")
+	added, err := h.permissionService.AddRoleForUser(ctx, userID, req.Role, domain)
+	if err != nil {
+		logger.Error(ctx, "Failed to assign role", zap.String("user_id", userID), zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+	if !added {
+		HandleError(c, response.NewAlreadyExistsError("用户已拥有该角色"))
+		return
+	}
+
+	HandleSuccess(c, "角色已分配")
+}
+
+// UnassignRole 撤销用户的角色
+// @Summary 撤销角色
+// @Description 撤销用户在当前租户内的角色(g 规则)，变更会写入审计日志
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param id path string true "用户ID"
+// @Param role path string true "角色名称"
+// @Success 200 {object} response.Response "撤销成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "用户未拥有该角色"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/users/{id}/roles/{role} [delete]
+func (h *PermissionHandler) UnassignRole(c *gin.Context) {
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := auditContext(c)
+	userID := c.Param("id")
+	role := c.Param("role")
+	deleted, err := h.permissionService.DeleteRoleForUser(ctx, userID, role, domain)
+	if err != nil {
+		logger.Error(ctx, "Failed to unassign role", zap.String("user_id", userID), zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+	if !deleted {
+		HandleError(c, response.NewNotFoundError("用户未拥有该角色"))
+		return
+	}
+
+	HandleSuccess(c, "角色已撤销")
+}
+
+// GetUserPermissions 查询用户的有效权限
+// @Summary 查询用户有效权限
+// @Description 返回用户在当前租户内直接分配的角色、通过角色继承获得的全部角色，以及由此生效的全部权限策略
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param id path string true "用户ID"
+// @Success 200 {object} response.Response{data=responsedto.UserPermissionsResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/users/{id}/permissions [get]
+func (h *PermissionHandler) GetUserPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.permissionService.GetUserPermissions(ctx, c.Param("id"), domain)
+	if err != nil {
+		logger.Error(ctx, "Failed to get user permissions", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, responsedto.ToUserPermissionsResponse(permissions))
+}
+
+// ListAuditLogs 查询审计日志
+// @Summary 查询审计日志
+// @Description 按时间倒序分页查询当前租户角色与权限策略的变更记录
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Tenant-ID header string false "租户，JWT未绑定租户时生效，默认为配置的默认租户"
+// @Param request query requestdto.ListAuditLogsRequest false "过滤条件"
+// @Success 200 {object} response.Response{data=response.PageData{items=[]responsedto.AuditLogResponse}} "获取成功"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /admin/audit-logs [get]
+func (h *PermissionHandler) ListAuditLogs(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req requestdto.ListAuditLogsRequest
+	if !h.validator.Verify(c, &req, validation.QueryBindAdapter) {
+		return
+	}
+
+	domain, ok := tenantFromContext(c)
+	if !ok {
+		return
+	}
+
+	filter := auditrepo.AuditLogFilter{
+		Domain:    domain,
+		ActorID:   req.ActorID,
+		Action:    req.Action,
+		Resource:  req.Resource,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	logs, total, err := h.auditService.List(ctx, filter, req.Page, req.PageSize)
+	if err != nil {
+		logger.Error(ctx, "Failed to list audit logs", zap.Error(err))
+	}
+
+	HandlePagingWithLogging(c, responsedto.ToAuditLogListResponse(logs), req.Page, req.PageSize, total, err)
+}
+
+// tenantFromContext 获取Casbin中间件解析出的当前租户，管理接口只读写该租户的角色和策略
+func tenantFromContext(c *gin.Context) (string, bool) {
+	domain, ok := contextutil.GetTenantIDFromContext(c.Request.Context())
+	if !ok {
+		HandleError(c, response.NewForbiddenError("无法确定所属租户"))
+		return "", false
+	}
+	return domain, true
+}
+
+// auditContext 将客户端IP写入请求上下文，供审计日志记录
+func auditContext(c *gin.Context) context.Context {
+	return context.WithValue(c.Request.Context(), contextutil.ClientIPContextKey, netutil.ClientIPFromContext(c))
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"common/logger"
+	"common/pkg/contextutil"
+	"common/pkg/filter"
+	"common/pkg/tabular"
+	"common/pkg/validation"
+	"user-services/internal/application/query/user"
+	"user-services/internal/application/queryhandler"
+	"user-services/internal/application/service"
+	"user-services/internal/domain/user/entity"
+	uservalidator "user-services/internal/domain/user/validator"
+	requestdto "user-services/internal/interfaces/http/dto/request"
+	responsedto "user-services/internal/interfaces/http/dto/response"
+)
+
+// 导出格式
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+// sensitiveUserFieldsPermission 查看用户敏感字段原文的权限名，没有该权限时导出的open_id和手机号脱敏
+const sensitiveUserFieldsPermission = "users:sensitive"
+
+// exportFlushInterval 每写入多少行把已缓冲的数据发送给客户端
+const exportFlushInterval = 500
+
+// UserExportHandler 导出用户HTTP处理器
+type UserExportHandler struct {
+	queryHandler      *queryhandler.UserQueryHandler
+	permissionService service.PermissionServiceInterface
+	validator         *validation.Validator
+	listSchema        *filter.Schema
+}
+
+// NewUserExportHandler 创建导出用户HTTP处理器
+func NewUserExportHandler(
+	queryHandler *queryhandler.UserQueryHandler,
+	permissionService service.PermissionServiceInterface,
+	validator *validation.Validator,
+	userValidator uservalidator.UserValidator,
+) *UserExportHandler {
+	return &UserExportHandler{
+		queryHandler:      queryHandler,
+		permissionService: permissionService,
+		validator:         validator,
+		listSchema:        requestdto.NewUserListSchema(userValidator.NormalizePhoneNumber),
+	}
+}
+
+// ExportUsers 导出用户
+// @Summary 导出用户
+// @Description 按用户列表的过滤条件流式导出全部符合条件的用户，按创建时间倒序，不支持sort；响应不使用统一的JSON格式
+// @Description csv 的列为 id,open_id,name,gender,phone_number,status,created_at,updated_at；ndjson 每行一个JSON对象，字段与用户信息相同
+// @Description 调用方没有 users:sensitive 权限时open_id和手机号脱敏
+// @Tags 用户管理
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param request query requestdto.ExportUsersRequest false "导出用户请求"
+// @Param filter[name][like] query string false "姓名包含" example(张)
+// @Param filter[status][in] query string false "账号状态，多个以逗号分隔" example(active,locked)
+// @Param filter[created_at][gte] query string false "创建时间起，RFC3339或YYYY-MM-DD" example(2023-01-01)
+// @Success 200 {file} file "导出文件"
+// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /users/export [get]
+func (h *UserExportHandler) ExportUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req requestdto.ExportUsersRequest
+	if !h.validator.Verify(c, &req, validation.QueryBindAdapter) {
+		return
+	}
+
+	params := c.Request.URL.Query()
+	exportFilter, err := h.listSchema.Parse(params)
+	if err == nil && params.Has(filter.SortParam) {
+		err = filter.Errors{filter.SortParam: "导出按创建时间倒序，不支持指定排序"}
+	}
+	if !h.validator.ValidateError(c, &req, err) {
+		return
+	}
+
+	revealSensitive, err := h.canViewSensitiveFields(ctx)
+	if err != nil {
+		logger.Error(ctx, "Failed to check sensitive field permission", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = exportFormatCSV
+	}
+	writer := &userExportWriter{c: c, format: format}
+
+	err = h.queryHandler.HandleExportUsers(ctx, &user.ExportUsersQuery{Filter: exportFilter}, func(u *entity.User) error {
+		return writer.Write(responsedto.ToUserExportResponse(u, revealSensitive))
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		logger.Error(ctx, "Failed to export users", zap.Error(err), zap.Int("rows", writer.rows))
+		// 响应头发送前出错时仍可以返回统一的错误响应，之后只能中断输出
+		if !writer.started {
+			HandleError(c, err)
+		}
+		return
+	}
+
+	logger.Info(ctx, "Users exported successfully",
+		zap.String("format", format),
+		zap.Int("rows", writer.rows),
+		zap.Bool("reveal_sensitive", revealSensitive))
+}
+
+// canViewSensitiveFields 当前用户在请求所属租户下是否有权查看敏感字段原文
+func (h *UserExportHandler) canViewSensitiveFields(ctx context.Context) (bool, error) {
+	userID, ok := contextutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return false, nil
+	}
+	tenantID, _ := contextutil.GetTenantIDFromContext(ctx)
+	return h.permissionService.Enforce(ctx, userID, tenantID, sensitiveUserFieldsPermission, http.MethodGet)
+}
+
+// userExportWriter 把用户逐行写入响应
+// 写入第一行时才发送响应头，读取第一批用户失败时仍可以返回JSON错误
+type userExportWriter struct {
+	c       *gin.Context
+	format  string
+	csv     *tabular.CSVWriter
+	json    *json.Encoder
+	rows    int
+	started bool
+}
+
+func (w *userExportWriter) start() error {
+	w.started = true
+
+	contentType := "text/csv; charset=utf-8"
+	if w.format == exportFormatNDJSON {
+		contentType = "application/x-ndjson; charset=utf-8"
+	}
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="users-%s.%s"`, time.Now().Format("20060102150405"), w.format))
+	// 导出耗时与数据量成正比，不受服务器写超时限制；客户端断开时查询随请求上下文取消
+	_ = http.NewResponseController(w.c.Writer).SetWriteDeadline(time.Time{})
+	w.c.Status(http.StatusOK)
+
+	if w.format == exportFormatNDJSON {
+		w.json = json.NewEncoder(w.c.Writer)
+		return nil
+	}
+
+	// 写入BOM，Excel打开时按UTF-8识别中文
+	if _, err := w.c.Writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+	w.csv = tabular.NewCSVWriter(w.c.Writer)
+	return w.csv.Write(responsedto.UserExportColumns)
+}
+
+// Write 写入一行，每 exportFlushInterval 行发送一次
+func (w *userExportWriter) Write(row *responsedto.UserInfoResponse) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if w.json != nil {
+		err = w.json.Encode(row)
+	} else {
+		err = w.csv.Write(row.CSVRecord())
+	}
+	if err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%exportFlushInterval == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close 发送剩余数据，没有符合条件的用户时只输出表头
+func (w *userExportWriter) Close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+func (w *userExportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	w.c.Writer.Flush()
+	return nil
+}
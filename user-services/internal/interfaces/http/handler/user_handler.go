@@ -5,11 +5,15 @@ import (
 	"go.uber.org/zap"
 
 	"common/logger"
+	"common/pkg/contextutil"
+	"common/pkg/filter"
 	"common/pkg/validation"
+	"common/response"
 	command "user-services/internal/application/command/user"
 	"user-services/internal/application/commandhandler"
 	"user-services/internal/application/query/user"
 	"user-services/internal/application/queryhandler"
+	uservalidator "user-services/internal/domain/user/validator"
 	requestdto "user-services/internal/interfaces/http/dto/request"
 	responsedto "user-services/internal/interfaces/http/dto/response"
 )
@@ -19,6 +23,7 @@ type UserHandler struct {
 	commandHandler *commandhandler.UserCommandHandler
 	queryHandler   *queryhandler.UserQueryHandler
 	validator      *validation.Validator
+	listSchema     *filter.Schema
 }
 
 // NewUserHandler 创建用户HTTP处理器
@@ -26,11 +31,13 @@ func NewUserHandler(
 	commandHandler *commandhandler.UserCommandHandler,
 	queryHandler *queryhandler.UserQueryHandler,
 	validator *validation.Validator,
+	userValidator uservalidator.UserValidator,
 ) *UserHandler {
 	return &UserHandler{
 		commandHandler: commandHandler,
 		queryHandler:   queryHandler,
 		validator:      validator,
+		listSchema:     requestdto.NewUserListSchema(userValidator.NormalizePhoneNumber),
 	}
 }
 
@@ -75,13 +82,19 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 // ListUsers 获取用户列表
 // @Summary 获取用户列表
-// @Description 分页获取用户列表，支持按姓名、性别、时间范围等条件过滤
+// @Description 分页获取用户列表，过滤条件格式为 filter[字段][操作符]=值(省略操作符为eq，in的多个值以逗号分隔)，排序格式为 sort=-created_at,name(- 表示倒序)
+// @Description 可过滤字段：name(eq,like)、phone_number(eq)、gender(eq,in)、status(eq,ne,in)、created_at/updated_at(gt,gte,lt,lte)；可排序字段：name、created_at、updated_at
+// @Description 传入 cursor 参数(第一页为空值)时按创建时间倒序使用游标分页，不能指定sort，响应 data 为 response.CursorPageData，返回 next_cursor 和 has_more，with_total=true 时额外返回总数
 // @Tags 用户管理
 // @Accept json
 // @Produce json
 // @Param request query requestdto.ListUsersRequest false "列表用户请求"
+// @Param filter[name][like] query string false "姓名包含" example(张)
+// @Param filter[gender][in] query string false "性别，多个以逗号分隔" example(100,200)
+// @Param filter[created_at][gte] query string false "创建时间起，RFC3339或YYYY-MM-DD" example(2023-01-01)
+// @Param sort query string false "排序字段，默认-created_at" example(-created_at,name)
 // @Success 200 {object} response.Response{data=response.PageData{items=[]responsedto.UserInfoResponse}} "获取成功"
-// @Failure 400 {object} response.Response "请求参数验证失败"
+// @Failure 400 {object} response.Response "请求参数验证失败或游标无效"
 // @Failure 401 {object} response.Response "未授权访问"
 // @Failure 500 {object} response.Response "服务器内部错误"
 // @Security BearerAuth
@@ -95,24 +108,28 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
+	// 解析过滤和排序条件
+	params := c.Request.URL.Query()
+	listFilter, err := h.listSchema.Parse(params)
+	if err == nil && req.CursorMode() && params.Has(filter.SortParam) {
+		err = filter.Errors{filter.SortParam: "游标分页按创建时间倒序，不支持指定排序"}
+	}
+	if !h.validator.ValidateError(c, &req, err) {
+		return
+	}
+
 	// 构建查询对象
 	query := &user.ListUsersQuery{
 		Page:     req.Page,
 		PageSize: req.PageSize,
+		Filter:   listFilter,
 	}
 
-	// 设置过滤条件
-	if req.Name != "" {
-		query.Name = &req.Name
-	}
-	if req.Gender != nil {
-		query.Gender = req.Gender.IntPointer()
-	}
-	if req.StartTime != nil {
-		query.StartTime = req.StartTime
-	}
-	if req.EndTime != nil {
-		query.EndTime = req.EndTime
+	if req.CursorMode() {
+		query.Cursor = req.CursorToken()
+		query.WithTotal = req.WithTotal
+		h.listUsersByCursor(c, query)
+		return
 	}
 
 	// 调用查询处理器
@@ -132,6 +149,29 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	HandlePagingWithLogging(c, userResponses, req.Page, req.PageSize, total, err)
 }
 
+// listUsersByCursor 游标分页获取用户列表
+func (h *UserHandler) listUsersByCursor(c *gin.Context, query *user.ListUsersQuery) {
+	ctx := c.Request.Context()
+
+	page, err := h.queryHandler.HandleListUsersByCursor(ctx, query)
+	if err != nil {
+		logger.Error(ctx, "Failed to list users by cursor", zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+	logger.Info(ctx, "User list retrieved successfully",
+		zap.Int("count", len(page.Users)),
+		zap.Bool("has_more", page.HasMore),
+		zap.Int("page_size", query.PageSize))
+
+	HandleCursorPagingWithLogging(c, responsedto.ToUserListResponse(page.Users), &response.CursorPagination{
+		PageSize:   query.PageSize,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+		Total:      page.Total,
+	}, nil)
+}
+
 // GetUser 获取用户信息
 // @Summary 获取用户详细信息
 // @Description 根据用户ID获取用户的详细信息
@@ -162,3 +202,174 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 	HandleWithLogging(c, responsedto.ToUserInfoResponse(userInfo), err)
 }
+
+// UpdateUser 更新用户信息
+// @Summary 更新用户信息
+// @Description 按JSON合并语义部分更新用户资料：未提供的字段保持不变，null表示清空；手机号不能与其他用户重复
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID" example("user_123456789")
+// @Param request body requestdto.UpdateUserRequest true "更新用户请求"
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数验证失败或手机号已被使用"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权修改该用户"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /users/{id} [patch]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	h.updateUser(c, c.Param("id"))
+}
+
+// UpdateCurrentUser 更新当前用户信息
+// @Summary 更新当前用户信息
+// @Description 按JSON合并语义部分更新当前登录用户的资料，规则与更新用户信息相同
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body requestdto.UpdateUserRequest true "更新用户请求"
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数验证失败或手机号已被使用"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /users/me [patch]
+func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
+	userID, ok := contextutil.GetUserIDFromContext(c.Request.Context())
+	if !ok {
+		HandleError(c, response.NewUnauthorizedError("无法获取用户信息"))
+		return
+	}
+	h.updateUser(c, userID)
+}
+
+func (h *UserHandler) updateUser(c *gin.Context, userID string) {
+	ctx := c.Request.Context()
+	var req requestdto.UpdateUserRequest
+	if !h.validator.Verify(c, &req, validation.JSONBindAdapter) {
+		return
+	}
+
+	cmd := &command.UpdateUserCommand{
+		UserID:  userID,
+		Updates: req.Updates(),
+	}
+	user, err := h.commandHandler.HandleUpdateUser(ctx, cmd)
+	if err != nil {
+		logger.Error(ctx, "Failed to update user", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	HandleWithLogging(c, responsedto.ToUserInfoResponse(user), err)
+}
+
+// DeleteUser 删除用户
+// @Summary 删除用户
+// @Description 软删除用户并吊销其全部令牌，删除后的用户无法登录和查询，手机号可以被重新注册；保留期内可由管理员恢复
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID" example("user_123456789")
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权删除该用户"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("id")
+
+	if err := h.commandHandler.HandleDeleteUser(ctx, &command.DeleteUserCommand{UserID: userID}); err != nil {
+		logger.Error(ctx, "Failed to delete user", zap.Error(err), zap.String("user_id", userID))
+		HandleError(c, err)
+		return
+	}
+
+	HandleSuccess(c, "用户已删除")
+}
+
+// RestoreUser 恢复已删除的用户
+// @Summary 恢复用户
+// @Description 恢复已软删除且未被彻底删除的用户；手机号或open_id已被其他用户使用时无法恢复
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID" example("user_123456789")
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "恢复成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "已删除的用户不存在"
+// @Failure 409 {object} response.Response "手机号或open_id已被其他用户使用"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /admin/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("id")
+
+	user, err := h.commandHandler.HandleRestoreUser(ctx, &command.RestoreUserCommand{UserID: userID})
+	if err != nil {
+		logger.Error(ctx, "Failed to restore user", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	HandleWithLogging(c, responsedto.ToUserInfoResponse(user), err)
+}
+
+// EnableUser 启用账号
+// @Summary 启用账号
+// @Description 将待激活、已锁定或已停用的账号恢复为正常状态
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID" example("user_123456789")
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "启用成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /admin/users/{id}/enable [post]
+func (h *UserHandler) EnableUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("id")
+
+	user, err := h.commandHandler.HandleEnableUser(ctx, &command.EnableUserCommand{UserID: userID})
+	if err != nil {
+		logger.Error(ctx, "Failed to enable user", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	HandleWithLogging(c, responsedto.ToUserInfoResponse(user), err)
+}
+
+// DisableUser 停用账号
+// @Summary 停用账号
+// @Description 停用账号并吊销其全部令牌，停用后无法登录，直至管理员重新启用
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID" example("user_123456789")
+// @Success 200 {object} response.Response{data=responsedto.UserInfoResponse} "停用成功"
+// @Failure 400 {object} response.Response "请求参数错误或当前状态不允许停用"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /admin/users/{id}/disable [post]
+func (h *UserHandler) DisableUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("id")
+
+	user, err := h.commandHandler.HandleDisableUser(ctx, &command.DisableUserCommand{UserID: userID})
+	if err != nil {
+		logger.Error(ctx, "Failed to disable user", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	HandleWithLogging(c, responsedto.ToUserInfoResponse(user), err)
+}
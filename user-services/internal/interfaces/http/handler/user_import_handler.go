@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"common/config"
+	"common/logger"
+	"common/pkg/contextutil"
+	"common/pkg/tabular"
+	"common/pkg/validation"
+	"common/response"
+	"user-services/internal/application/service"
+	requestdto "user-services/internal/interfaces/http/dto/request"
+	responsedto "user-services/internal/interfaces/http/dto/response"
+)
+
+// defaultImportMaxFileSize 未配置 user.import.max_file_size 时上传文件的大小上限
+const defaultImportMaxFileSize = 10 << 20
+
+// UserImportHandler 批量导入用户HTTP处理器
+type UserImportHandler struct {
+	importer    service.UserImporterInterface
+	jobService  service.UserImportJobServiceInterface
+	validator   *validation.Validator
+	maxFileSize int64
+}
+
+// NewUserImportHandler 创建批量导入用户HTTP处理器
+func NewUserImportHandler(
+	importer service.UserImporterInterface,
+	jobService service.UserImportJobServiceInterface,
+	validator *validation.Validator,
+	cfg *config.Config,
+) *UserImportHandler {
+	maxFileSize := cfg.User.Import.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = defaultImportMaxFileSize
+	}
+	return &UserImportHandler{
+		importer:    importer,
+		jobService:  jobService,
+		validator:   validator,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// ImportUsers 批量导入用户
+// @Summary 批量导入用户
+// @Description 上传CSV或XLSX文件创建导入任务，任务在后台执行，通过任务ID查询进度和下载错误报告
+// @Description 表头为 open_id,name,gender,phone_number,password(顺序任意)，每行按创建用户的规则校验，文件内重复的open_id和手机号会被拒绝
+// @Tags 用户管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV或XLSX文件"
+// @Param dry_run formData bool false "只校验不写入"
+// @Success 200 {object} response.Response{data=responsedto.UserImportJobResponse} "任务已创建"
+// @Failure 400 {object} response.Response "文件格式错误、缺少列或超过行数上限"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Security BearerAuth
+// @Router /users/import [post]
+func (h *UserImportHandler) ImportUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req requestdto.ImportUsersRequest
+	if !h.validator.Verify(c, &req, validation.FormBindAdapter) {
+		return
+	}
+	if req.File.Size > h.maxFileSize {
+		HandleError(c, response.NewValidationError(fmt.Sprintf("导入文件不能超过%dMB", h.maxFileSize>>20)))
+		return
+	}
+	format, err := tabular.FormatFromPath(req.File.Filename)
+	if err != nil {
+		HandleError(c, response.NewValidationError("导入文件只支持.csv和.xlsx"))
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		HandleError(c, response.NewValidationError("无法读取导入文件", err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := h.importer.ReadRows(file, format)
+	if err != nil {
+		logger.Warn(ctx, "Failed to read user import file", zap.Error(err), zap.String("file_name", req.File.Filename))
+		HandleError(c, err)
+		return
+	}
+
+	operatorID, _ := contextutil.GetUserIDFromContext(ctx)
+	job, err := h.jobService.Submit(ctx, req.File.Filename, rows, req.DryRun, operatorID)
+	if err != nil {
+		logger.Error(ctx, "Failed to submit user import job", zap.Error(err))
+	} else {
+		logger.Info(ctx, "User import job submitted",
+			zap.String("job_id", job.ID),
+			zap.Int("rows", len(rows)),
+			zap.Bool("dry_run", req.DryRun))
+	}
+
+	HandleWithLogging(c, responsedto.ToUserImportJobResponse(job), err)
+}
+
+// GetImportJob 查询批量导入任务进度
+// @Summary 查询批量导入任务进度
+// @Description 任务进度和错误报告在任务创建后保留 user.import.job_ttl(默认24小时)
+// @Tags 用户管理
+// @Produce json
+// @Param job_id path string true "任务ID"
+// @Success 200 {object} response.Response{data=responsedto.UserImportJobResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "任务不存在或已过期"
+// @Security BearerAuth
+// @Router /users/import/{job_id} [get]
+func (h *UserImportHandler) GetImportJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.Request.Context(), c.Param("job_id"))
+	HandleWithLogging(c, responsedto.ToUserImportJobResponse(job), err)
+}
+
+// DownloadImportReport 下载批量导入错误报告
+// @Summary 下载批量导入错误报告
+// @Description 任务结束后以CSV下载失败的行及原因，列为 line,open_id,column,message，line 为导入文件中的行号(表头为第1行)
+// @Tags 用户管理
+// @Produce text/csv
+// @Param job_id path string true "任务ID"
+// @Success 200 {file} file "错误报告"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "任务不存在或已过期"
+// @Failure 409 {object} response.Response "任务尚未结束"
+// @Security BearerAuth
+// @Router /users/import/{job_id}/report [get]
+func (h *UserImportHandler) DownloadImportReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	jobID := c.Param("job_id")
+
+	rowErrors, err := h.jobService.Report(ctx, jobID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-import-%s-errors.csv"`, jobID))
+	c.Status(http.StatusOK)
+
+	// 写入BOM，Excel打开时按UTF-8识别中文
+	_, _ = c.Writer.Write([]byte("\xEF\xBB\xBF"))
+	writer := tabular.NewCSVWriter(c.Writer)
+	_ = writer.Write([]string{"line", "open_id", "column", "message"})
+	for _, rowErr := range rowErrors {
+		_ = writer.Write([]string{strconv.Itoa(rowErr.Line), rowErr.OpenID, rowErr.Column, rowErr.Message})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logger.Error(ctx, "Failed to write user import report", zap.Error(err), zap.String("job_id", jobID))
+	}
+}
@@ -1,19 +1,86 @@
 package http
 
 import (
+	"context"
+
 	"common/config"
 	commonMiddleware "common/middleware"
 	"common/pkg/jwt"
+	"common/response"
 	service "user-services/internal/application/service"
 	"user-services/internal/interfaces/http/routes"
 )
 
 // NewCasbinMiddleware 创建 Casbin 中间件的 Provider
-func NewCasbinMiddleware(permissionService service.PermissionServiceInterface) routes.CasbinMiddleware {
-	return routes.CasbinMiddleware(commonMiddleware.CasbinMiddleware(permissionService.Enforce))
+// 租户按JWT的tid声明、tenant.header请求头、tenant.default_tenant的顺序解析，
+// 资源为路由模板或 routes.Permissions 中指定的权限名，路由通过 ownership 声明所有权校验，
+// API Key还需具备 routes.Scopes 中路由要求的授权范围
+func NewCasbinMiddleware(permissionService service.PermissionServiceInterface, ownership *commonMiddleware.Ownership, config *config.Config) routes.CasbinMiddleware {
+	return routes.CasbinMiddleware(commonMiddleware.CasbinMiddleware(permissionService.Enforce,
+		commonMiddleware.WithDomainResolver(commonMiddleware.TenantResolver(config.Tenant)),
+		commonMiddleware.WithRoutePermissions(routes.Permissions),
+		commonMiddleware.WithRouteScopes(routes.Scopes),
+		commonMiddleware.WithOwnership(ownership, permissionService.EnforceWithOwner),
+	))
 }
 
 // NewAuthMiddleware 创建 Auth 中间件的 Provider
-func NewAuthMiddleware(jwtService *jwt.JWT, config *config.Config) routes.AuthMiddleware {
-	return routes.AuthMiddleware(commonMiddleware.AuthMiddleware(jwtService, config.Auth))
-}
\ No newline at end of file
+func NewAuthMiddleware(
+	jwtService *jwt.JWT,
+	authService service.AuthServiceInterface,
+	apiKeyService service.APIKeyServiceInterface,
+	config *config.Config,
+) routes.AuthMiddleware {
+	return routes.AuthMiddleware(commonMiddleware.AuthMiddleware(jwtService, config.Auth,
+		commonMiddleware.WithClaimsCheck(revocationCheck(authService)),
+		commonMiddleware.WithClaimsCheck(sessionCheck(authService)),
+		commonMiddleware.WithAPIKeyAuth(apiKeyAuth(apiKeyService)),
+	))
+}
+
+// revocationCheck 拒绝已登出或已被整体吊销的令牌
+func revocationCheck(authService service.AuthServiceInterface) commonMiddleware.ClaimsCheckFunc {
+	return func(ctx context.Context, claims *jwt.CustomClaims) error {
+		revoked, err := authService.IsTokenRevoked(ctx, claims)
+		if err != nil {
+			return response.NewInternalServerError("Token revocation check failed", err)
+		}
+		if revoked {
+			return response.NewUnauthorizedError("Token has been revoked")
+		}
+		return nil
+	}
+}
+
+// sessionCheck 拒绝所属会话已被吊销、淘汰或过期的令牌
+// 未携带会话ID的令牌(如会话功能上线前签发的令牌)不做检查
+func sessionCheck(authService service.AuthServiceInterface) commonMiddleware.ClaimsCheckFunc {
+	return func(ctx context.Context, claims *jwt.CustomClaims) error {
+		if claims.SessionID == "" {
+			return nil
+		}
+		active, err := authService.IsSessionActive(ctx, claims.SessionID)
+		if err != nil {
+			return response.NewInternalServerError("Session check failed", err)
+		}
+		if !active {
+			return response.NewUnauthorizedError("Session has been revoked")
+		}
+		return nil
+	}
+}
+
+// apiKeyAuth 使用API Key认证服务间调用，调用方身份为API Key的所有者
+func apiKeyAuth(apiKeyService service.APIKeyServiceInterface) commonMiddleware.APIKeyAuthFunc {
+	return func(ctx context.Context, apiKey string) (*commonMiddleware.APIKeyPrincipal, error) {
+		key, err := apiKeyService.Authenticate(ctx, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		return &commonMiddleware.APIKeyPrincipal{
+			KeyID:   key.ID(),
+			Subject: key.OwnerID(),
+			Scopes:  key.Scopes(),
+		}, nil
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-services/internal/interfaces/http/handler"
+)
+
+// SetupAdminRoutes 设置管理API路由
+// 由上层路由组统一认证和授权，需为管理员配置 /api/v1/admin/* 的策略
+func SetupAdminRoutes(rg *gin.RouterGroup, permissionHandler *handler.PermissionHandler, userHandler *handler.UserHandler, logger *zap.Logger) {
+	admin := rg.Group("/admin")
+	{
+		admin.GET("/policies", permissionHandler.ListPolicies)
+		admin.POST("/policies", permissionHandler.AddPolicy)
+		admin.DELETE("/policies", permissionHandler.RemovePolicy)
+		admin.GET("/roles", permissionHandler.ListRoles)
+		admin.POST("/users/:id/roles", permissionHandler.AssignRole)
+		admin.DELETE("/users/:id/roles/:role", permissionHandler.UnassignRole)
+		admin.GET("/users/:id/permissions", permissionHandler.GetUserPermissions)
+		admin.POST("/users/:id/restore", userHandler.RestoreUser)
+		admin.POST("/users/:id/enable", userHandler.EnableUser)
+		admin.POST("/users/:id/disable", userHandler.DisableUser)
+		admin.GET("/audit-logs", permissionHandler.ListAuditLogs)
+	}
+
+	logger.Info("Admin API routes registered")
+}
@@ -8,14 +8,26 @@ import (
 )
 
 // SetupAuthRoutes 设置认证API路由
-func SetupAuthRoutes(rg *gin.RouterGroup, authHandler *handler.AuthHandler, authMiddleware AuthMiddleware, logger *zap.Logger) {
+func SetupAuthRoutes(rg *gin.RouterGroup, authHandler *handler.AuthHandler, mfaHandler *handler.MFAHandler, passwordHandler *handler.PasswordHandler, authMiddleware AuthMiddleware, logger *zap.Logger) {
 	auth := rg.Group("/auth")
 	{
 		auth.POST("/login/password", authHandler.LoginByPassword)
 		auth.POST("/login/wechat", authHandler.LoginByWeChat)
+		auth.POST("/login/sms", authHandler.LoginBySMS)
+		auth.POST("/login/mfa", authHandler.LoginByMFA)
+		auth.POST("/sms/send", authHandler.SendSMSCode)
+		auth.POST("/refresh", authHandler.RefreshToken)
+		auth.POST("/password/reset", passwordHandler.RequestPasswordReset)
+		auth.POST("/password/reset/confirm", passwordHandler.ConfirmPasswordReset)
 
-		// 登出需要认证
+		// 以下接口需要认证
 		auth.POST("/logout", gin.HandlerFunc(authMiddleware), authHandler.Logout)
+		auth.POST("/wechat/bind-phone", gin.HandlerFunc(authMiddleware), authHandler.BindWeChatPhone)
+		auth.GET("/sessions", gin.HandlerFunc(authMiddleware), authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", gin.HandlerFunc(authMiddleware), authHandler.RevokeSession)
+		auth.POST("/mfa/totp/setup", gin.HandlerFunc(authMiddleware), mfaHandler.SetupTOTP)
+		auth.POST("/mfa/totp/confirm", gin.HandlerFunc(authMiddleware), mfaHandler.ConfirmTOTP)
+		auth.POST("/mfa/totp/disable", gin.HandlerFunc(authMiddleware), mfaHandler.DisableTOTP)
 	}
 
 	logger.Info("Auth API routes registered")
@@ -7,6 +7,7 @@ import (
 
 	"common/config"
 	commonMiddleware "common/middleware"
+	"user-services/internal/application/service"
 	"user-services/internal/interfaces/http/handler"
 )
 
@@ -20,21 +21,29 @@ type (
 type RoutesParams struct {
 	fx.In
 
-	Engine           *gin.Engine
-	UserHandler      *handler.UserHandler
-	HealthHandler    *handler.HealthHandler
-	AuthHandler      *handler.AuthHandler
-	CasbinMiddleware CasbinMiddleware
-	AuthMiddleware   AuthMiddleware
-	Config           *config.Config
-	ZapLogger        *zap.Logger
+	Engine            *gin.Engine
+	UserHandler       *handler.UserHandler
+	UserImportHandler *handler.UserImportHandler
+	UserExportHandler *handler.UserExportHandler
+	HealthHandler     *handler.HealthHandler
+	AuthHandler       *handler.AuthHandler
+	MFAHandler        *handler.MFAHandler
+	PasswordHandler   *handler.PasswordHandler
+	PermissionHandler *handler.PermissionHandler
+	JWKSHandler       *handler.JWKSHandler
+	PermissionService service.PermissionServiceInterface
+	CasbinMiddleware  CasbinMiddleware
+	Ownership         *commonMiddleware.Ownership
+	AuthMiddleware    AuthMiddleware
+	Config            *config.Config
+	ZapLogger         *zap.Logger
 }
 
 // SetupRoutesFinal
 func SetupRoutesFinal(p RoutesParams) {
 
 	// 1. 系统路由（无需认证）
-	SetupSystemRoutes(p.Engine, p.HealthHandler, p.ZapLogger)
+	SetupSystemRoutes(p.Engine, p.HealthHandler, p.JWKSHandler, p.ZapLogger)
 
 	// 2. Swagger API 文档路由（条件性启用）
 	SetupSwaggerRoutes(p.Engine, p.Config, p.ZapLogger)
@@ -43,15 +52,27 @@ func SetupRoutesFinal(p RoutesParams) {
 	v1 := p.Engine.Group("/api/v1")
 
 	// 3.1 认证相关路由（部分需要Token）
-	SetupAuthRoutes(v1, p.AuthHandler, p.AuthMiddleware, p.ZapLogger)
+	SetupAuthRoutes(v1, p.AuthHandler, p.MFAHandler, p.PasswordHandler, p.AuthMiddleware, p.ZapLogger)
 
-	// 3.2 业务路由（需要认证和授权）
 	v1.Use(commonMiddleware.RequestLogMiddleware())
-	// v1.Use(gin.HandlerFunc(p.CasbinMiddleware))
+
+	// 3.2 当前用户路由（只需认证，所有登录用户均可访问）
+	SetupCurrentUserRoutes(v1, p.UserHandler, p.PasswordHandler, p.AuthMiddleware, p.ZapLogger)
+
+	// 以上路由不经过Casbin授权，之后注册的路由均需通过授权，启动时检查其策略覆盖情况
+	public := routeKeys(p.Engine)
+
+	// 3.3 业务路由（需要认证和授权）
+	v1.Use(gin.HandlerFunc(p.AuthMiddleware), gin.HandlerFunc(p.CasbinMiddleware))
 	{
-		SetupUserRoutes(v1, p.UserHandler, p.ZapLogger)
+		SetupUserRoutes(v1, p.UserHandler, p.Ownership, p.ZapLogger)
+		SetupUserImportRoutes(v1, p.UserImportHandler, p.ZapLogger)
+		SetupUserExportRoutes(v1, p.UserExportHandler, p.ZapLogger)
+		SetupAdminRoutes(v1, p.PermissionHandler, p.UserHandler, p.ZapLogger)
 		// 后续添加其他模块
 	}
 
+	checkRouteCoverage(p.Engine, public, p.PermissionService, p.ZapLogger)
+
 	p.ZapLogger.Info("All routes setup completed successfully")
 }
@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	commonMiddleware "common/middleware"
+	pkgcasbin "common/pkg/casbin"
+	"user-services/internal/application/service"
+)
+
+// Permissions 需要与路径解耦的接口在此指定权限名，策略随后按权限名编写；未列出的接口按路由模板授权
+var Permissions = commonMiddleware.RoutePermissions{
+	"POST /api/v1/users/import":               "users:import",
+	"GET /api/v1/users/import/:job_id":        "users:import",
+	"GET /api/v1/users/import/:job_id/report": "users:import",
+	"GET /api/v1/users/export":                "users:export",
+}
+
+// Scopes API Key访问需要授权的接口时所需的授权范围，未列出的接口(如管理接口)只允许授权范围为 "*" 的API Key访问
+var Scopes = commonMiddleware.RouteScopes{
+	"GET /api/v1/users":                       "users:read",
+	"GET /api/v1/users/:id":                   "users:read",
+	"POST /api/v1/users":                      "users:write",
+	"PATCH /api/v1/users/:id":                 "users:write",
+	"DELETE /api/v1/users/:id":                "users:write",
+	"POST /api/v1/users/import":               "users:import",
+	"GET /api/v1/users/import/:job_id":        "users:import",
+	"GET /api/v1/users/import/:job_id/report": "users:import",
+	"GET /api/v1/users/export":                "users:export",
+}
+
+// routeKeys 记录已注册路由，用于区分之后注册的需要授权的路由
+func routeKeys(engine *gin.Engine) map[string]struct{} {
+	keys := make(map[string]struct{})
+	for _, route := range engine.Routes() {
+		keys[route.Method+" "+route.Path] = struct{}{}
+	}
+	return keys
+}
+
+// checkRouteCoverage 列出没有任何策略覆盖的路由
+// 只在启动时检查并输出警告，不阻止启动：策略可以在启动后通过管理接口补充
+func checkRouteCoverage(engine *gin.Engine, public map[string]struct{}, permissionService service.PermissionServiceInterface, logger *zap.Logger) {
+	protected := make([]pkgcasbin.Route, 0)
+	for _, route := range engine.Routes() {
+		if _, ok := public[route.Method+" "+route.Path]; ok {
+			continue
+		}
+		protected = append(protected, pkgcasbin.Route{
+			Method: route.Method,
+			Object: Permissions.Object(route.Method, route.Path),
+		})
+	}
+
+	uncovered, err := permissionService.UncoveredRoutes(context.Background(), protected)
+	if err != nil {
+		logger.Warn("Failed to check route authorization coverage", zap.Error(err))
+		return
+	}
+	if len(uncovered) == 0 {
+		logger.Info("All protected routes are covered by casbin policies", zap.Int("routes", len(protected)))
+		return
+	}
+
+	routes := make([]string, 0, len(uncovered))
+	for _, route := range uncovered {
+		routes = append(routes, fmt.Sprintf("%s %s", route.Method, route.Object))
+	}
+	logger.Warn("Routes not covered by any casbin policy, requests to them will be denied",
+		zap.Int("count", len(uncovered)),
+		zap.Strings("routes", routes))
+}
@@ -9,11 +9,12 @@ import (
 )
 
 // SetupSystemRoutes 设置系统路由
-func SetupSystemRoutes(engine *gin.Engine, healthHandler *handler.HealthHandler, logger *zap.Logger) {
+func SetupSystemRoutes(engine *gin.Engine, healthHandler *handler.HealthHandler, jwksHandler *handler.JWKSHandler, logger *zap.Logger) {
 	engine.GET("/health", healthHandler.Health)
 	engine.GET("/ping", func(c *gin.Context) {
 		response.Handle(c, gin.H{"message": "pong"}, nil)
 	})
+	engine.GET("/.well-known/jwks.json", jwksHandler.JWKS)
 
 	logger.Info("System routes registered")
 }
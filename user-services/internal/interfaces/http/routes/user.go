@@ -1,20 +1,60 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	commonMiddleware "common/middleware"
 	"user-services/internal/interfaces/http/handler"
 )
 
-// SetupUserRoutes 设置用户API路由
-func SetupUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, logger *zap.Logger) {
+// SetupUserRoutes 设置用户API路由，由上层路由组统一认证和授权
+// 声明了所有权校验的路由，除角色策略外，主体为 $owner 的策略还会放行资源所有者本人
+func SetupUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, ownership *commonMiddleware.Ownership, logger *zap.Logger) {
 	users := rg.Group("/users")
 	{
 		users.POST("", userHandler.CreateUser)
 		users.GET("", userHandler.ListUsers)
 		users.GET("/:id", userHandler.GetUser)
+		ownership.Require(users, http.MethodGet, "/:id", commonMiddleware.OwnerFromParam("id"))
+		users.PATCH("/:id", userHandler.UpdateUser)
+		ownership.Require(users, http.MethodPatch, "/:id", commonMiddleware.OwnerFromParam("id"))
+		users.DELETE("/:id", userHandler.DeleteUser)
+		ownership.Require(users, http.MethodDelete, "/:id", commonMiddleware.OwnerFromParam("id"))
 	}
 
 	logger.Info("User API routes registered")
 }
+
+// SetupUserImportRoutes 设置批量导入用户路由，按权限名 users:import 授权，见 Permissions
+func SetupUserImportRoutes(rg *gin.RouterGroup, importHandler *handler.UserImportHandler, logger *zap.Logger) {
+	imports := rg.Group("/users/import")
+	{
+		imports.POST("", importHandler.ImportUsers)
+		imports.GET("/:job_id", importHandler.GetImportJob)
+		imports.GET("/:job_id/report", importHandler.DownloadImportReport)
+	}
+
+	logger.Info("User import API routes registered")
+}
+
+// SetupUserExportRoutes 设置导出用户路由，按权限名 users:export 授权，见 Permissions
+func SetupUserExportRoutes(rg *gin.RouterGroup, exportHandler *handler.UserExportHandler, logger *zap.Logger) {
+	rg.GET("/users/export", exportHandler.ExportUsers)
+
+	logger.Info("User export API routes registered")
+}
+
+// SetupCurrentUserRoutes 设置当前用户API路由，只需认证，不经过Casbin授权
+// 这些接口修改的是调用方本人，API Key 需要 users:write 授权范围，否则只读的 API Key 也能改写所有者的资料
+func SetupCurrentUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, passwordHandler *handler.PasswordHandler, authMiddleware AuthMiddleware, logger *zap.Logger) {
+	me := rg.Group("/users/me", gin.HandlerFunc(authMiddleware), commonMiddleware.RequireScope("users:write"))
+	{
+		me.PATCH("", userHandler.UpdateCurrentUser)
+		me.PUT("/password", passwordHandler.ChangePassword)
+	}
+
+	logger.Info("Current user API routes registered")
+}